@@ -2,8 +2,12 @@ package colors
 
 import (
 	"fmt"
-	"runtime"
+	"math"
+	"os"
 	"sort"
+	"strings"
+
+	"golang.org/x/term"
 )
 
 var (
@@ -32,6 +36,7 @@ var (
 	// Text Styles
 	Underline = "\033[4m"
 	Italic    = "\033[3m"
+	Dim       = "\033[2m"
 
 	// Bold Gray Variants
 	BoldGray1 = "\033[1;38;5;232m"
@@ -110,6 +115,7 @@ var (
 		// Text Styles
 		"underline": Underline,
 		"italic":    Italic,
+		"dim":       Dim,
 
 		// Bold colors
 		"bold_red":    BoldRed,
@@ -155,11 +161,16 @@ func GenerateGradient(startHex, endHex string, steps int) []string {
 	startR, startG, startB := hexToRGB(startHex)
 	endR, endG, endB := hexToRGB(endHex)
 
+	// The first entry is the start color and the last one the end color
+	last := steps - 1
+	if last < 1 {
+		last = 1 // A single step is just the start color
+	}
 	for i := 0; i < steps; i++ {
-		r := startR + (endR-startR)*i/steps
-		g := startG + (endG-startG)*i/steps
-		b := startB + (endB-startB)*i/steps
-		gradient[i] = fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+		r := startR + (endR-startR)*i/last
+		g := startG + (endG-startG)*i/last
+		b := startB + (endB-startB)*i/last
+		gradient[i] = RGB(r, g, b)
 	}
 	return gradient
 }
@@ -170,11 +181,16 @@ func GenerateGradientBackground(startHex, endHex string, steps int) []string {
 	startR, startG, startB := hexToRGB(startHex)
 	endR, endG, endB := hexToRGB(endHex)
 
+	// The first entry is the start color and the last one the end color
+	last := steps - 1
+	if last < 1 {
+		last = 1 // A single step is just the start color
+	}
 	for i := 0; i < steps; i++ {
-		r := startR + (endR-startR)*i/steps
-		g := startG + (endG-startG)*i/steps
-		b := startB + (endB-startB)*i/steps
-		gradient[i] = fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b)
+		r := startR + (endR-startR)*i/last
+		g := startG + (endG-startG)*i/last
+		b := startB + (endB-startB)*i/last
+		gradient[i] = BgRGB(r, g, b)
 	}
 	return gradient
 }
@@ -203,6 +219,109 @@ func ColorizeBold(text, color string) string {
 	return text // Return uncolored text if color not found
 }
 
+// Color256 returns the foreground color n (0-255) of the 256-color palette.
+func Color256(n int) string {
+	if !enabled {
+		return ""
+	}
+	return fmt.Sprintf("\033[38;5;%dm", clampByte(n))
+}
+
+// BgColor256 returns the background color n (0-255) of the 256-color palette.
+func BgColor256(n int) string {
+	if !enabled {
+		return ""
+	}
+	return fmt.Sprintf("\033[48;5;%dm", clampByte(n))
+}
+
+// RGB returns a 24-bit foreground color.
+func RGB(r, g, b int) string {
+	if !enabled {
+		return ""
+	}
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", clampByte(r), clampByte(g), clampByte(b))
+}
+
+// BgRGB returns a 24-bit background color.
+func BgRGB(r, g, b int) string {
+	if !enabled {
+		return ""
+	}
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm", clampByte(r), clampByte(g), clampByte(b))
+}
+
+// FromHex returns the 24-bit foreground color for a hex string like "#ff8800" (the # is optional).
+func FromHex(hex string) string {
+	if !strings.HasPrefix(hex, "#") {
+		hex = "#" + hex
+	}
+	return RGB(hexToRGB(hex))
+}
+
+// BgFromHex returns the 24-bit background color for a hex string like "#ff8800" (the # is optional).
+func BgFromHex(hex string) string {
+	if !strings.HasPrefix(hex, "#") {
+		hex = "#" + hex
+	}
+	return BgRGB(hexToRGB(hex))
+}
+
+// BestTextColor returns a near-black or near-white foreground color, whichever
+// contrasts more with the background bgHex (compared by WCAG relative luminance).
+func BestTextColor(bgHex string) string {
+	if !strings.HasPrefix(bgHex, "#") {
+		bgHex = "#" + bgHex
+	}
+	// Contrast ratio is (L1 + 0.05) / (L2 + 0.05): against black (L=0) and white (L=1)
+	l := relativeLuminance(hexToRGB(bgHex))
+	if (l+0.05)/0.05 >= 1.05/(l+0.05) {
+		return RGB(18, 18, 18) // Dark text on a light background
+	}
+	return RGB(240, 240, 240) // Light text on a dark background
+}
+
+// Blend mixes two hex colors, from aHex at t=0 to bHex at t=1, and returns the
+// result as a hex color ("#rrggbb").
+func Blend(aHex, bHex string, t float64) string {
+	if !strings.HasPrefix(aHex, "#") {
+		aHex = "#" + aHex
+	}
+	if !strings.HasPrefix(bHex, "#") {
+		bHex = "#" + bHex
+	}
+	t = math.Max(0, math.Min(1, t))
+	aR, aG, aB := hexToRGB(aHex)
+	bR, bG, bB := hexToRGB(bHex)
+	mix := func(a, b int) int {
+		return int(math.Round(float64(a) + (float64(b)-float64(a))*t))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", mix(aR, bR), mix(aG, bG), mix(aB, bB))
+}
+
+// relativeLuminance returns the WCAG relative luminance (0-1) of an sRGB color.
+func relativeLuminance(r, g, b int) float64 {
+	linear := func(c int) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// clampByte limits a color component or palette index to 0-255.
+func clampByte(n int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > 255 {
+		return 255
+	}
+	return n
+}
+
 // hexToRGB converts a hex color string to RGB format.
 func hexToRGB(hex string) (int, int, int) {
 	var r, g, b int
@@ -210,72 +329,67 @@ func hexToRGB(hex string) (int, int, int) {
 	return r, g, b
 }
 
-// Disable colors on Windows if necessary
+// colorVars lists every color and style variable, so SetEnabled can blank and restore them.
+var colorVars = []*string{
+	&Gray1, &Gray2, &Gray3, &Gray4, &Gray5,
+	&Reset, &Red, &Green, &Yellow, &Orange, &Blue, &Purple, &Magenta, &Cyan, &Gray, &White, &Black,
+	&Underline, &Italic, &Dim,
+	&BoldGray1, &BoldGray2, &BoldGray3, &BoldGray4, &BoldGray5,
+	&BoldRed, &BoldGreen, &BoldYellow, &BoldOrange, &BoldBlue, &BoldPurple, &BoldMagenta, &BoldCyan, &BoldGray, &BoldWhite, &BoldBlack,
+	&BgGray1, &BgGray2, &BgGray3, &BgGray4, &BgGray5,
+	&BgBlack, &BgRed, &BgGreen, &BgYellow, &BgOrange, &BgBlue, &BgPurple, &BgMagenta, &BgCyan, &BgGray, &BgWhite,
+	&BgBoldGray1, &BgBoldGray2, &BgBoldGray3, &BgBoldGray4, &BgBoldGray5,
+	&BgBrightBlack, &BgBrightRed, &BgBrightGreen, &BgBrightYellow, &BgBrightBlue, &BgBrightPurple, &BgBrightMagenta, &BgBrightCyan, &BgBrightWhite,
+	&BgReset,
+}
+
+var (
+	originalColors []string          // Sequences of colorVars, saved before any disabling
+	originalMap    map[string]string // Original ColorMap
+	enabled        = true
+)
+
+// SetEnabled turns colors on or off at runtime: when disabled, every color variable,
+// ColorMap entry and helper (Color256, RGB, gradients, ...) yields "".
+// Colors already copied elsewhere (e.g. into UI elements) keep their value, so call
+// it before building the UI.
+func SetEnabled(on bool) {
+	enabled = on
+	for i, v := range colorVars {
+		if on {
+			*v = originalColors[i]
+		} else {
+			*v = ""
+		}
+	}
+	for name, code := range originalMap {
+		if on {
+			ColorMap[name] = code
+		} else {
+			ColorMap[name] = ""
+		}
+	}
+}
+
+// Enabled reports whether colors are enabled.
+func Enabled() bool {
+	return enabled
+}
+
+// Colors start disabled if NO_COLOR is set, stdout is not a terminal, or the
+// terminal can't handle ANSI sequences (Windows consoles without VT support).
 func init() {
-	if runtime.GOOS == "windows" {
-		Reset = ""
-		Red = ""
-		Green = ""
-		Yellow = ""
-		Blue = ""
-		Purple = ""
-		Cyan = ""
-		Gray = ""
-		White = ""
-		BoldRed = ""
-		BoldGreen = ""
-		BoldYellow = ""
-		BoldBlue = ""
-		BoldPurple = ""
-		BoldCyan = ""
-		BoldGray = ""
-		BoldWhite = ""
-
-		Underline = "" // Disable Underline on Windows
-		Italic = ""    // Disable Italic on Windows
-
-		BgBlack = ""
-		BgRed = ""
-		BgGreen = ""
-		BgYellow = ""
-		BgBlue = ""
-		BgPurple = ""
-		BgCyan = ""
-		BgGray = ""
-		BgWhite = ""
-		BgBrightBlack = ""
-		BgBrightRed = ""
-		BgBrightGreen = ""
-		BgBrightYellow = ""
-		BgBrightBlue = ""
-		BgBrightPurple = ""
-		BgBrightCyan = ""
-		BgBrightWhite = ""
-		BgReset = ""
-
-		Gray1 = ""
-		Gray2 = ""
-		Gray3 = ""
-		Gray4 = ""
-		Gray5 = ""
-
-		BgGray1 = ""
-		BgGray2 = ""
-		BgGray3 = ""
-		BgGray4 = ""
-		BgGray5 = ""
-
-		BoldGray1 = ""
-		BoldGray2 = ""
-		BoldGray3 = ""
-		BoldGray4 = ""
-		BoldGray5 = ""
-
-		BgBoldGray1 = ""
-		BgBoldGray2 = ""
-		BgBoldGray3 = ""
-		BgBoldGray4 = ""
-		BgBoldGray5 = ""
+	originalColors = make([]string, len(colorVars))
+	for i, v := range colorVars {
+		originalColors[i] = *v
+	}
+	originalMap = make(map[string]string, len(ColorMap))
+	for name, code := range ColorMap {
+		originalMap[name] = code
+	}
+
+	if os.Getenv("NO_COLOR") != "" || !term.IsTerminal(int(os.Stdout.Fd())) || !enableVirtualTerminal() {
+		SetEnabled(false)
 	}
 }
 
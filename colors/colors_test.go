@@ -0,0 +1,39 @@
+package colors
+
+import "testing"
+
+func TestGenerateGradientEndsOnEndColor(t *testing.T) {
+	defer SetEnabled(Enabled())
+	SetEnabled(true) // Colors start disabled when stdout isn't a terminal, as under go test
+
+	tests := []struct {
+		name     string
+		generate func(startHex, endHex string, steps int) []string
+		rgb      func(r, g, b int) string
+	}{
+		{"GenerateGradient", GenerateGradient, RGB},
+		{"GenerateGradientBackground", GenerateGradientBackground, BgRGB},
+	}
+	for _, tt := range tests {
+		for _, steps := range []int{2, 3, 5, 7, 10, 256} {
+			gradient := tt.generate("#ff8000", "#0a80fe", steps)
+			if len(gradient) != steps {
+				t.Fatalf("%s(%d) returned %d colors", tt.name, steps, len(gradient))
+			}
+			if want := tt.rgb(255, 128, 0); gradient[0] != want {
+				t.Errorf("%s(%d)[0] = %q, want the start color %q", tt.name, steps, gradient[0], want)
+			}
+			if want := tt.rgb(10, 128, 254); gradient[steps-1] != want {
+				t.Errorf("%s(%d)[%d] = %q, want the end color %q", tt.name, steps, steps-1, gradient[steps-1], want)
+			}
+		}
+
+		// A single step is the start color
+		if gradient := tt.generate("#ff8000", "#0a80fe", 1); len(gradient) != 1 || gradient[0] != tt.rgb(255, 128, 0) {
+			t.Errorf("%s(1) = %q, want just the start color", tt.name, gradient)
+		}
+		if gradient := tt.generate("#ff8000", "#0a80fe", 0); len(gradient) != 0 {
+			t.Errorf("%s(0) = %q, want no colors", tt.name, gradient)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package colors
+
+// enableVirtualTerminal reports whether the terminal understands ANSI sequences;
+// terminals outside Windows always do.
+func enableVirtualTerminal() bool {
+	return true
+}
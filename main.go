@@ -44,6 +44,12 @@ func initializeDemoApps() {
 		Description: "A dialog-based demo application",
 		RunApp:      tests.TestDialogApp,
 	})
+	registerDemoApp(types.DemoApp{
+		ID:          5,
+		Name:        "Windows Demo",
+		Description: "Overlapping windows run by a WindowManager",
+		RunApp:      tests.TestWindowsApp,
+	})
 }
 
 func printUsage() {
@@ -83,6 +83,7 @@ func TestDialogApp() {
 		return false
 	})
 	singleLineBtn.SetActive(false)
+	singleLineBtn.Mnemonic = 's'
 	win.AddElement(singleLineBtn)
 
 	// Info Dialog
@@ -110,10 +111,12 @@ func TestDialogApp() {
 			colors.White,
 			buttons,
 		)
+		currentDialog.Shadow = true
 		win.AddElement(currentDialog)
 		currentDialog.SetActive(true)
 		return false
 	})
+	infoBtn.Mnemonic = 'i'
 	win.AddElement(infoBtn)
 
 	// Warning Dialog
@@ -148,6 +151,7 @@ func TestDialogApp() {
 		currentDialog.SetActive(true)
 		return false
 	})
+	warningBtn.Mnemonic = 'w'
 	win.AddElement(warningBtn)
 
 	// Error Dialog
@@ -182,6 +186,7 @@ func TestDialogApp() {
 		currentDialog.SetActive(true)
 		return false
 	})
+	errorBtn.Mnemonic = 'e'
 	win.AddElement(errorBtn)
 
 	// Custom Dialog
@@ -228,12 +233,47 @@ func TestDialogApp() {
 		currentDialog.SetActive(true)
 		return false
 	})
+	customBtn.Mnemonic = 'c'
 	win.AddElement(customBtn)
 
+	// Input Dialog
+	inputBtn := NewButton("Input Dialog", 2, buttonY+buttonSpacing*5, 20, colors.BoldGreen, colors.BgWhite+colors.Green, func() bool {
+		if currentDialog != nil {
+			currentDialog.SetActive(false)
+			win.RemoveElement(currentDialog)
+			currentDialog = nil
+		}
+		currentDialog = NewInputPrompt(
+			"Your Name",
+			"What should we call you?",
+			"Guest",
+			winWidth/4, winHeight/4, winWidth/2,
+			colors.BgGreen, colors.Green, colors.BoldWhite, colors.White,
+			func(value string) bool {
+				updateStatus("Hello, "+value+"!", colors.Green)
+				win.RemoveElement(currentDialog)
+				currentDialog = nil
+				return false
+			},
+			func() bool {
+				updateStatus("Input dialog cancelled", colors.Red)
+				win.RemoveElement(currentDialog)
+				currentDialog = nil
+				return false
+			},
+		)
+		win.AddElement(currentDialog)
+		currentDialog.SetActive(true)
+		return false
+	})
+	inputBtn.Mnemonic = 'n'
+	win.AddElement(inputBtn)
+
 	// Add quit button
 	quitBtn := NewButton("Quit", 2, buttonY+buttonSpacing*6, 20, colors.BoldRed, colors.BgWhite+colors.Red, func() bool {
 		return true
 	})
+	quitBtn.Mnemonic = 'q'
 	win.AddElement(quitBtn)
 
 	// Add instructions
@@ -241,6 +281,7 @@ func TestDialogApp() {
 		"Instructions:",
 		"• Use Tab/Shift+Tab to navigate between buttons",
 		"• Press Enter to show the selected dialog type",
+		"• Or press a button's underlined letter (or Alt+letter)",
 		"• In dialogs, use arrow keys to select buttons",
 		"• Press Enter to activate the selected button",
 		"• Press Escape to close non-modal dialogs",
@@ -35,13 +35,14 @@ func TestMenuApp() {
 	fileMenu := menuBar.AddSubMenu("File", colors.White, colors.BgBlue+colors.White)
 	fileMenu.AddItem(NewMenuItem("New", colors.Cyan, colors.BgBlack+colors.White, func() bool {
 		return false
-	}))
+	}).WithShortcut("Ctrl+N"))
 	fileMenu.AddItem(NewMenuItem("Open", colors.Cyan, colors.BgBlack+colors.White, func() bool {
 		return false
-	}))
+	}).WithShortcut("Ctrl+O"))
 	fileMenu.AddItem(NewMenuItem("Save", colors.Cyan, colors.BgBlack+colors.White, func() bool {
 		return false
-	}))
+	}).WithShortcut("Ctrl+S"))
+	fileMenu.AddSeparator()
 	fileMenu.AddItem(NewMenuItem("Exit", colors.Cyan, colors.BgBlack+colors.White, func() bool {
 		return true // Quit
 	}))
@@ -57,12 +58,19 @@ func TestMenuApp() {
 	advancedSubmenu := formatSubmenu.AddSubMenu("Advanced", colors.Cyan, colors.BgBlack+colors.White)
 	advancedSubmenu.AddItem(NewMenuItem("Option 1", colors.Cyan, colors.BgBlack+colors.White, nil))
 	advancedSubmenu.AddItem(NewMenuItem("Option 2", colors.Cyan, colors.BgBlack+colors.White, nil))
+	unavailable := NewMenuItem("Option 3", colors.Cyan, colors.BgBlack+colors.White, nil)
+	unavailable.Disabled = true // Shown grey and skipped by the arrow keys
+	advancedSubmenu.AddItem(unavailable)
 
 	// View Menu
 	viewMenu := menuBar.AddSubMenu("View", colors.White, colors.BgBlue+colors.White)
 	viewMenu.AddItem(NewMenuItem("Zoom In", colors.Cyan, colors.BgBlack+colors.White, nil))
 	viewMenu.AddItem(NewMenuItem("Zoom Out", colors.Cyan, colors.BgBlack+colors.White, nil))
 	viewMenu.AddItem(NewMenuItem("Reset Zoom", colors.Cyan, colors.BgBlack+colors.White, nil))
+	viewMenu.AddSeparator()
+	viewMenu.AddItem(NewCheckMenuItem("Show Line Numbers", colors.Cyan, colors.BgBlack+colors.White, true, nil))
+	viewMenu.AddItem(NewRadioMenuItem("Light Mode", "mode", colors.Cyan, colors.BgBlack+colors.White, false, nil))
+	viewMenu.AddItem(NewRadioMenuItem("Dark Mode", "mode", colors.Cyan, colors.BgBlack+colors.White, true, nil))
 
 	// Help Menu
 	helpMenu := menuBar.AddSubMenu("Help", colors.White, colors.BgBlue+colors.White)
@@ -83,6 +91,7 @@ func TestMenuApp() {
 		"• Use Enter to activate a menu item",
 		"• Use Left arrow to close submenu",
 		"• Use Escape to close all menus",
+		"• Ctrl+N/Ctrl+O/Ctrl+S run the File menu items while the menu bar is focused",
 		"• Press 'q' or Ctrl+C to quit",
 	}
 
@@ -91,6 +100,16 @@ func TestMenuApp() {
 		win.AddElement(tipLabel)
 	}
 
+	// Keep the window at 80% of the terminal when it is resized
+	win.OnResize = func(newWidth, newHeight int) {
+		menuBar.Width = newWidth - 2
+	}
+	win.OnTerminalResize = func(termWidth, termHeight int) {
+		win.X, win.Y = 0, 0 // Let Resize use the whole terminal, then center
+		win.Resize(termWidth*8/10, termHeight*8/10)
+		win.Recenter()
+	}
+
 	// Start the window interaction loop
 	win.WindowActions()
 }
@@ -3,6 +3,7 @@ package tests
 import (
 	"fmt"
 	"window-go/colors"
+	"window-go/ui/chars"
 	. "window-go/ui/gui" // Import the gui package
 	// Removed unused imports: strconv, strings, time
 )
@@ -59,6 +60,7 @@ func TestSegmentsApp() {
 	var titleInput *TextBox
 	var contentInput *TextArea // Using TextArea
 	var infoLabel *Label       // To display status messages
+	var notesWin *Window       // Declared early so helpers can update the terminal title
 
 	// --- Helper Functions ---
 
@@ -118,6 +120,9 @@ func TestSegmentsApp() {
 				contentInput.SetText(note.Content) // Use SetText for TextArea
 			}
 			selectedNoteIndex = index
+			if notesWin != nil {
+				notesWin.SetTerminalTitle("Notes - " + note.Title) // Show the open note in the terminal tab
+			}
 			if infoLabel != nil {
 				infoLabel.Text = fmt.Sprintf("Editing note %d: %s", index, note.Title)
 				infoLabel.Color = colors.Cyan
@@ -155,7 +160,7 @@ func TestSegmentsApp() {
 	winY := (termHeight - winHeight) / 2
 
 	// Create Window
-	notesWin := NewWindow("📝", "Window-Go Notes App", winX, winY, winWidth, winHeight,
+	notesWin = NewWindow("📝", "Window-Go Notes App", winX, winY, winWidth, winHeight,
 		"rounded", colors.BoldYellow, colors.Yellow, colors.BgBlack, colors.White)
 
 	contentAreaWidth := winWidth - 2
@@ -172,6 +177,18 @@ func TestSegmentsApp() {
 		rightSegmentWidth = 0
 	}
 
+	// --- Header Bar (Top) ---
+	header := NewPowerBar(0, 0, 0,
+		PowerBarSegment{Text: "NOTES", Fg: colors.BoldBlack, Bg: colors.BgYellow},
+		PowerBarSegment{Text: "Tab: move focus", Fg: colors.BoldWhite, Bg: colors.BgBlue},
+		PowerBarSegment{Text: "F1: help", Fg: colors.White, Bg: colors.BgGray2},
+	)
+	header.BackgroundColor = colors.BgBlack // Matches the window
+	if !chars.HasGlyphSupport() {
+		header.Separator = " " // No Nerd Font for the Powerline arrows
+	}
+	notesWin.AddElement(header)
+
 	currentY := 1 // Relative Y within window content
 
 	// --- Info Label (Top) ---
@@ -196,15 +213,11 @@ func TestSegmentsApp() {
 		loadNoteForEditing(index)
 	}
 	notesWin.AddElement(notesListContainer)
+	notesWin.SetHelpText(notesListContainer, "Pick a note to load it into the editor.")
 
-	// --- Draw vertical line divider ---
-	dividerX := leftSegmentWidth + 1
-	dividerY := 1 // Start at the top of content area
-	dividerHeight := contentAreaHeight - 1
-	for i := 0; i < dividerHeight; i++ {
-		divider := NewLabel("│", dividerX, dividerY+i, colors.Gray)
-		notesWin.AddElement(divider)
-	}
+	// --- Dividers: under the info label, and between the segments down to the bottom border ---
+	notesWin.AddHorizontalDivider(2)
+	notesWin.AddVerticalDividerSpan(leftSegmentWidth+1, 2, -1)
 
 	// --- Right Segment: Editor ---
 	editorY := 3 // Start editor elements slightly lower, aligned with Notes label
@@ -216,6 +229,7 @@ func TestSegmentsApp() {
 	editorInputY++
 	titleInput = NewTextBox("", rightSegmentX, editorInputY, rightSegmentWidth, colors.BgBlack+colors.White, colors.BgCyan+colors.BoldBlack) // Use calculated width
 	notesWin.AddElement(titleInput)
+	notesWin.SetHelpText(titleInput, "The note's title, shown in the list.")
 	editorInputY += 2 // Add space
 
 	// Content
@@ -231,7 +245,9 @@ func TestSegmentsApp() {
 	contentInput = NewTextArea("", rightSegmentX, editorInputY, rightSegmentWidth, textAreaHeight, 0, // Use calculated width
 		colors.BgBlack+colors.White, colors.BgCyan+colors.BoldBlack, true, true) // Show word and char count
 	contentInput.IsActive = false     // Start inactive, but allow it to be focused
+	contentInput.SetWordWrap(true)    // Long note lines flow onto the next row
 	notesWin.AddElement(contentInput) // TextArea added to the window
+	notesWin.SetHelpText(contentInput, "The note's body; Ctrl+F finds text. Save to keep your changes.")
 
 	// Calculate Y position for buttons based on the bottom of the window
 	buttonY := contentAreaHeight - 2 // Position buttons near the bottom
@@ -253,9 +269,9 @@ func TestSegmentsApp() {
 	// New Button
 	newButton := NewButton("New", buttonStartX, buttonY, buttonWidth, colors.BoldGreen, colors.BgGreen+colors.BoldWhite, func() bool {
 		clearEditor()
-		updateNotesListDisplay() // Update list to remove selection highlight
-		// Optionally move focus back to title input? Requires focus API extension.
-		return false // Don't quit
+		updateNotesListDisplay()          // Update list to remove selection highlight
+		notesWin.FocusElement(titleInput) // Start typing the new note's title
+		return false                      // Don't quit
 	})
 	notesWin.AddElement(newButton)
 
@@ -266,6 +282,7 @@ func TestSegmentsApp() {
 		content := contentInput.GetText() // Use GetText for TextArea
 		if title == "" {
 			infoLabel.Text = "Error: Title cannot be empty."
+			notesWin.Bell()
 			infoLabel.Color = colors.Red
 			return false
 		}
@@ -305,12 +322,18 @@ func TestSegmentsApp() {
 			updateNotesListDisplay() // Update list display
 		} else {
 			infoLabel.Text = "Error: No note selected to delete."
+			notesWin.Bell()
 			infoLabel.Color = colors.Red
 		}
 		return false // Don't quit
-	})
+	}).WithConfirm(notesWin, "Delete Note", "Delete the selected note? This cannot be undone.")
 	notesWin.AddElement(deleteButton)
 
+	// Keep the buttons one row above the bottom border when the window is resized
+	for _, button := range []*Button{newButton, saveButton, deleteButton} {
+		notesWin.SetConstraints(button, Constraints{Anchor: AnchorBottom, Bottom: 1})
+	}
+
 	// --- Initial Display & Interaction ---
 	updateNotesListDisplay() // Load initial notes into the list
 	if len(notes) > 0 {
@@ -328,6 +351,9 @@ func TestSegmentsApp() {
 	}
 	notesWin.SetKeyStrokeHandler(keyHandler)
 
+	// Start with the notes list focused so arrows browse notes right away
+	notesWin.SetInitialFocus(notesListContainer)
+
 	// Start the interaction loop
 	notesWin.WindowActions()
 }
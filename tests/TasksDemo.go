@@ -61,6 +61,7 @@ func TestWindowApp() {
 	// Generate 25 sample tasks and prepare initial content for the container
 	priorities := []string{"Low", "Medium", "High"}
 	initialContent := []string{} // Store formatted strings for NewContainer
+	initialColors := []string{}  // Row colors for the container, by priority
 	for i := 0; i < 25; i++ {
 		taskName := fmt.Sprintf("Generated Task %d", i+1)
 		// Add some longer names occasionally
@@ -91,8 +92,9 @@ func TestWindowApp() {
 		case "High":
 			lineColor = colors.BoldRed
 		}
-		line := fmt.Sprintf("%s%d: %s %s (%s)%s", lineColor, i, status, task.Name, task.Priority, colors.Reset)
+		line := fmt.Sprintf("%d: %s %s (%s)", i, status, task.Name, task.Priority)
 		initialContent = append(initialContent, line)
+		initialColors = append(initialColors, lineColor)
 	}
 
 	var infoLabel *Label
@@ -110,9 +112,11 @@ func TestWindowApp() {
 	// Updates the container content and progress bar based on the tasks slice
 	updateTaskListDisplay := func() {
 		content := []string{}
+		rowColors := []string{}
 		doneCount := 0
 		if len(tasks) == 0 {
-			content = append(content, colors.Gray+"<No tasks yet>"+colors.Reset)
+			content = append(content, "<No tasks yet>")
+			rowColors = append(rowColors, colors.Gray)
 		} else {
 			for i, task := range tasks {
 				status := "[ ]"
@@ -130,62 +134,18 @@ func TestWindowApp() {
 				case "High":
 					lineColor = colors.BoldRed
 				}
-				// Format: "Index: Status Name (Priority)" with color
-				line := fmt.Sprintf("%s%d: %s %s (%s)%s", lineColor, i, status, task.Name, task.Priority, colors.Reset)
+				// Format: "Index: Status Name (Priority)", colored through RowColors
+				line := fmt.Sprintf("%d: %s %s (%s)", i, status, task.Name, task.Priority)
 				content = append(content, line)
+				rowColors = append(rowColors, lineColor)
 			}
 		}
 		// Only call SetContent if the container already exists
 		if taskListContainer != nil {
-			// This call updates container content AND scrollbar state (visibility, maxvalue)
+			// This call updates container content AND scrollbar state, calling OnScroll if it changed
+			taskListContainer.RowColors = rowColors
 			taskListContainer.SetContent(content)
 		}
-
-		// Update progress bar based on the LATEST scroll state
-		// Check if completionProgress and taskListContainer exist before using
-		if completionProgress != nil && taskListContainer != nil {
-			scrollbar := taskListContainer.GetScrollbar() // Scrollbar always exists now
-
-			// Check if the scrollbar is currently needed/visible
-			if scrollbar.Visible {
-				// Update MaxValue based on current scrollbar state
-				completionProgress.MaxValue = float64(scrollbar.MaxValue)
-				// Update Value based on current scrollbar state
-				completionProgress.SetValue(float64(scrollbar.Value))
-				// Update MaxValue of the gradient progress bar
-				progressGradient.MaxValue = float64(scrollbar.MaxValue)
-				// Update Value of the gradient progress bar
-				progressGradient.SetValue(float64(scrollbar.Value))
-
-				// Ensure the OnScroll callback is attached ONCE to update progress bar DURING scrolling
-				if scrollbar.OnScroll == nil {
-					scrollbar.OnScroll = func(newValue int) {
-						// This function will be called by scrollbar.SetValue during scroll actions
-						if completionProgress != nil {
-							// Directly update progress bar value when scrollbar value changes
-							completionProgress.SetValue(float64(newValue))
-							// NOTE: We rely on the WindowActions loop to trigger a Render after scroll input.
-							// If we needed immediate render on scroll *callback*, we'd need a way to signal it.
-						}
-						if progressGradient != nil {
-							// Directly update gradient progress bar value when scrollbar value changes
-							progressGradient.SetValue(float64(newValue))
-							// NOTE: We rely on the WindowActions loop to trigger a Render after scroll input.
-							// If we needed immediate render on scroll *callback*, we'd need a way to signal it.
-						}
-					}
-				}
-			} else {
-				// Scrollbar is not visible, set progress to 0
-				completionProgress.MaxValue = 0
-				completionProgress.SetValue(0)
-				// Detach callback? Not strictly necessary, but good practice if scrollbar could be destroyed/recreated.
-				// scrollbar.OnScroll = nil // Optional cleanup
-				// Set gradient progress bar to 0 as well
-				progressGradient.MaxValue = 0
-				progressGradient.SetValue(0)
-			}
-		}
 	}
 
 	// Clears input fields
@@ -289,18 +249,14 @@ func TestWindowApp() {
 	priorityLabel := NewLabel("Priority:", inputStartX, currentY, colors.White)
 	testWin.AddElement(priorityLabel)
 	priorityGroup = NewRadioGroup()
-	prioBtnY := currentY
-	prioBtnX := inputFieldX
-	prioBtnSpacing := 12 // Adjust spacing if needed
+	// The HBox positions the buttons, so they are created at (0, 0)
 	// Low: Green
-	prioLow := NewRadioButton("Low", "Low", prioBtnX, prioBtnY, colors.BoldGreen, colors.BgGreen+colors.BoldWhite, priorityGroup)
-	testWin.AddElement(prioLow)
+	prioLow := NewRadioButton("Low", "Low", 0, 0, colors.BoldGreen, colors.BgGreen+colors.BoldWhite, priorityGroup)
 	// Medium: Yellow
-	prioMedium := NewRadioButton("Medium", "Medium", prioBtnX+prioBtnSpacing, prioBtnY, colors.BoldYellow, colors.BgWhite+colors.BoldBlack, priorityGroup) // Yellow, Black text on active
-	testWin.AddElement(prioMedium)
+	prioMedium := NewRadioButton("Medium", "Medium", 0, 0, colors.BoldYellow, colors.BgWhite+colors.BoldBlack, priorityGroup) // Yellow, Black text on active
 	// High: Red
-	prioHigh := NewRadioButton("High", "High", prioBtnX+prioBtnSpacing*2, prioBtnY, colors.BoldRed, colors.BgRed+colors.BoldWhite, priorityGroup)
-	testWin.AddElement(prioHigh)
+	prioHigh := NewRadioButton("High", "High", 0, 0, colors.BoldRed, colors.BgRed+colors.BoldWhite, priorityGroup)
+	testWin.AddLayout(NewHBox(inputFieldX, currentY, 3, prioLow, prioMedium, prioHigh))
 	priorityGroup.Select(0) // Default to Low
 	currentY++
 
@@ -316,6 +272,7 @@ func TestWindowApp() {
 	containerWidth := contentAreaWidth - 1
 
 	taskListContainer = NewContainer(containerX, containerY, containerWidth, containerHeight, initialContent)
+	taskListContainer.RowColors = initialColors
 	// Add the OnItemSelected callback
 	taskListContainer.OnItemSelected = func(newIndex int) {
 		if indexInput != nil { // Ensure indexInput exists
@@ -349,6 +306,14 @@ func TestWindowApp() {
 	testWin.AddElement(progressGradient)
 	currentY++ // Move past gradient progress bar row
 
+	// Mirror the task list's scroll position in the progress bars, live while scrolling
+	taskListContainer.OnScroll = func(offset, maxOffset int) {
+		completionProgress.MaxValue = float64(maxOffset)
+		completionProgress.SetValue(float64(offset))
+		progressGradient.MaxValue = float64(maxOffset)
+		progressGradient.SetValue(float64(offset))
+	}
+
 	// Spacer
 	testWin.AddElement(NewSpacer(1, currentY, 1))
 	currentY++
@@ -361,6 +326,8 @@ func TestWindowApp() {
 	testWin.AddElement(indexLabel)
 	indexInputWidth := 6
 	indexInput = NewTextBox("", indexInputX, indexInputY, indexInputWidth, colors.BgBlack+colors.White, colors.BgCyan+colors.BoldBlack) // Black BG, White Text
+	// Only digits can be typed into the index field
+	indexInput.InputMode = InputNumeric
 	testWin.AddElement(indexInput)
 	// Load button - Adjusted colors
 	loadButton := NewButton("Load", indexInputX+indexInputWidth+1, indexInputY, 8, colors.BoldCyan, colors.BgCyan+colors.BoldBlack, func() bool { // Black text on active
@@ -456,7 +423,7 @@ func TestWindowApp() {
 		infoLabel.Text = fmt.Sprintf("Task %d deleted successfully.", idx)
 		infoLabel.Color = colors.Red
 		return false // Don't quit
-	})
+	}).WithConfirm(testWin, "Delete Task", "Delete the task at the given index? This cannot be undone.")
 	testWin.AddElement(deleteButton)
 
 	// Quit Button - Bold Red
@@ -480,7 +447,7 @@ func TestWindowApp() {
 	testWin.SetKeyStrokeHandler(keyHandler)
 
 	// --- Initial Display & Interaction ---
-	updateTaskListDisplay() // Call once to set initial progress bar state based on initial tasks
+	updateTaskListDisplay() // Call once to show the initial tasks
 	testWin.WindowActions() // Start the interaction loop
 
 }
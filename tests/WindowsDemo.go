@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"fmt"
+	"window-go/colors"
+	. "window-go/ui/gui"
+)
+
+// TestWindowsApp shows two overlapping windows run by a WindowManager, and a
+// dialog window opened over them.
+func TestWindowsApp() {
+	fmt.Print(ClearScreenAndBuffer())
+	termWidth := GetTerminalWidth()
+	termHeight := GetTerminalHeight()
+
+	wm := NewWindowManager()
+
+	// Main window
+	mainWin := NewWindow("🪟", "Main Window", 2, 1, termWidth/2, termHeight/2,
+		"rounded", colors.BoldCyan, colors.Cyan, colors.BgBlack, colors.White)
+	mainWin.MouseEnabled = true
+	mainWin.Movable = true
+	mainWin.AddElement(NewLabel("Alt+Tab or a click switches windows.", 2, 1, colors.Gray))
+	mainWin.AddElement(NewLabel("Drag a title bar to move a window.", 2, 2, colors.Gray))
+	mainWin.AddElement(NewButton("Open Dialog", 2, 4, 16, colors.BoldGreen, colors.BgGreen+colors.BoldWhite, func() bool {
+		dialog := NewWindow("💬", "Dialog", termWidth/3, termHeight/3, 34, 7,
+			"double", colors.BoldYellow, colors.Yellow, colors.BgBlack, colors.White)
+		dialog.ZIndex = 1 // Stays above the other windows
+		dialog.AddElement(NewLabel("Only this window gets the keys.", 1, 1, colors.White))
+		dialog.AddElement(NewButton("Close", 1, 3, 10, colors.BoldYellow, colors.BgYellow+colors.Black, func() bool {
+			return true // Quitting removes the dialog from the manager
+		}))
+		wm.AddWindow(dialog)
+		return false
+	}))
+	mainWin.AddElement(NewButton("Quit All", 20, 4, 12, colors.BoldRed, colors.BgRed+colors.BoldWhite, func() bool {
+		for _, w := range wm.Windows {
+			if w != mainWin {
+				wm.RemoveWindow(w)
+			}
+		}
+		return true
+	}))
+
+	// Second window, overlapping the first
+	notesWin := NewWindow("📝", "Notes", termWidth/3, termHeight/4, termWidth/2, termHeight/2,
+		"single", colors.BoldMagenta, colors.Magenta, colors.BgBlack, colors.White)
+	notesWin.Movable = true
+	notesWin.AddElement(NewLabel("A second window.", 2, 1, colors.White))
+	notesWin.AddElement(NewTextBox("Type here", 2, 3, 20, colors.BgBlack+colors.White, colors.BgBlue+colors.BoldWhite))
+
+	wm.AddWindow(mainWin)
+	wm.AddWindow(notesWin)
+	wm.Run()
+}
@@ -2,44 +2,110 @@ package chars
 
 import (
 	_ "embed" // Required for embedding font data
+	"os"
+	"strings"
 )
 
 //go:embed fonts/MesloLGSNerdFont-Regular.ttf
 var fontData []byte
 
+// Powerline and Nerd Font glyphs (private use area, U+E0xx) need a patched font
+// such as the embedded MesloLGS Nerd Font; the box drawing characters don't.
+// They are written as \u escapes so the exact code points survive any editor's encoding.
 const (
-	LeftCircleHalfFilled     = ""
-	RightCircleHalfFilled    = ""
-	LeftCircleHalf           = ""
-	RightCircleHalf          = ""
-	LeftArrowFilled          = ""
-	RightArrowFilled         = ""
-	LeftArrow                = ""
-	RightArrow               = ""
-	ThinRightArrow           = "⟩"
-	GlitchDivider            = ""
-	ThreeDashedVertical      = "┆"
-	SimpleLine               = "│"
-	LeftFlameFilled          = ""
-	RightFlameFilled         = ""
-	LeftFlame                = ""
-	RightFlame               = ""
-	LeftGlitchFilled         = ""
-	RightGlitchFilled        = ""
-	RoundedCornerLeftTop     = "╭"
-	RoundedCornerRightTop    = "╮"
-	RoundedCornerLeftBottom  = "╰"
-	RoundedCornerRightBottom = "╯"
-	SquareCornerLeftTop      = "┌"
-	SquareCornerRightTop     = "┐"
-	SquareCornerLeftBottom   = "└"
-	SquareCornerRightBottom  = "┘"
-	DoubleCornerLeftTop      = "╔"
-	DoubleCornerRightTop     = "╗"
-	DoubleCornerLeftBottom   = "╚"
-	DoubleCornerRightBottom  = "╝"
+	LeftCircleHalfFilled     = "\ue0b6"
+	RightCircleHalfFilled    = "\ue0b4"
+	LeftCircleHalf           = "\ue0b7"
+	RightCircleHalf          = "\ue0b5"
+	LeftArrowFilled          = "\ue0b2"
+	RightArrowFilled         = "\ue0b0"
+	LeftArrow                = "\ue0b3"
+	RightArrow               = "\ue0b1"
+	ThinRightArrow           = "\u27e9"
+	GlitchDivider            = "\ue0c4"
+	ThreeDashedVertical      = "\u2506"
+	SimpleLine               = "\u2502"
+	LeftFlameFilled          = "\ue0c2"
+	RightFlameFilled         = "\ue0c0"
+	LeftFlame                = "\ue0c3"
+	RightFlame               = "\ue0c1"
+	LeftGlitchFilled         = "\ue0c7"
+	RightGlitchFilled        = "\ue0c6"
+	RoundedCornerLeftTop     = "\u256d"
+	RoundedCornerRightTop    = "\u256e"
+	RoundedCornerLeftBottom  = "\u2570"
+	RoundedCornerRightBottom = "\u256f"
+	SquareCornerLeftTop      = "\u250c"
+	SquareCornerRightTop     = "\u2510"
+	SquareCornerLeftBottom   = "\u2514"
+	SquareCornerRightBottom  = "\u2518"
+	DoubleCornerLeftTop      = "\u2554"
+	DoubleCornerRightTop     = "\u2557"
+	DoubleCornerLeftBottom   = "\u255a"
+	DoubleCornerRightBottom  = "\u255d"
 )
 
+// Glyph maps the name of each constant above to its glyph, e.g. for looking up
+// glyphs named in a config file: Glyph["RightArrowFilled"].
+var Glyph = map[string]string{
+	"LeftCircleHalfFilled":     LeftCircleHalfFilled,
+	"RightCircleHalfFilled":    RightCircleHalfFilled,
+	"LeftCircleHalf":           LeftCircleHalf,
+	"RightCircleHalf":          RightCircleHalf,
+	"LeftArrowFilled":          LeftArrowFilled,
+	"RightArrowFilled":         RightArrowFilled,
+	"LeftArrow":                LeftArrow,
+	"RightArrow":               RightArrow,
+	"ThinRightArrow":           ThinRightArrow,
+	"GlitchDivider":            GlitchDivider,
+	"ThreeDashedVertical":      ThreeDashedVertical,
+	"SimpleLine":               SimpleLine,
+	"LeftFlameFilled":          LeftFlameFilled,
+	"RightFlameFilled":         RightFlameFilled,
+	"LeftFlame":                LeftFlame,
+	"RightFlame":               RightFlame,
+	"LeftGlitchFilled":         LeftGlitchFilled,
+	"RightGlitchFilled":        RightGlitchFilled,
+	"RoundedCornerLeftTop":     RoundedCornerLeftTop,
+	"RoundedCornerRightTop":    RoundedCornerRightTop,
+	"RoundedCornerLeftBottom":  RoundedCornerLeftBottom,
+	"RoundedCornerRightBottom": RoundedCornerRightBottom,
+	"SquareCornerLeftTop":      SquareCornerLeftTop,
+	"SquareCornerRightTop":     SquareCornerRightTop,
+	"SquareCornerLeftBottom":   SquareCornerLeftBottom,
+	"SquareCornerRightBottom":  SquareCornerRightBottom,
+	"DoubleCornerLeftTop":      DoubleCornerLeftTop,
+	"DoubleCornerRightTop":     DoubleCornerRightTop,
+	"DoubleCornerLeftBottom":   DoubleCornerLeftBottom,
+	"DoubleCornerRightBottom":  DoubleCornerRightBottom,
+}
+
+// HasGlyphSupport reports whether the terminal can likely show the Nerd Font glyphs.
+// A terminal's font can't be queried, so this is a guess: NERD_FONT=1 or NERD_FONT=0
+// decides when set; otherwise it needs a UTF-8 locale and a terminal other than
+// the Linux console or a dumb terminal.
+func HasGlyphSupport() bool {
+	switch os.Getenv("NERD_FONT") {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	switch os.Getenv("TERM") {
+	case "linux", "dumb", "":
+		return false
+	}
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	locale = strings.ToUpper(locale)
+	return strings.Contains(locale, "UTF-8") || strings.Contains(locale, "UTF8")
+}
+
 func InitFont() {
 	_ = fontData // This is just to ensure the font data is embedded
 }
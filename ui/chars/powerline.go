@@ -0,0 +1,53 @@
+package chars
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// Segment is one part of a Powerline status bar: text on a colored background.
+type Segment struct {
+	Text string
+	Fg   string // Text color, as a hex color like "#ffffff"
+	Bg   string // Background color, as a hex color like "#005f87"
+}
+
+// PowerlineSegment returns a segment showing text in the hex colors fg on bg.
+func PowerlineSegment(text, fg, bg string) Segment {
+	return Segment{Text: text, Fg: fg, Bg: bg}
+}
+
+// PowerlineBar joins segments left to right into a status bar, separated by
+// RightArrowFilled glyphs that blend each segment's background into the next.
+// The result needs a Nerd Font (see HasGlyphSupport).
+func PowerlineBar(segments ...Segment) string {
+	var bar strings.Builder
+	for i, seg := range segments {
+		bar.WriteString(colors.BgFromHex(seg.Bg) + colors.FromHex(seg.Fg) + " " + seg.Text + " ")
+		// The arrow is drawn in this segment's background, on the next one's
+		bar.WriteString(colors.Reset + colors.FromHex(seg.Bg))
+		if i+1 < len(segments) {
+			bar.WriteString(colors.BgFromHex(segments[i+1].Bg))
+		}
+		bar.WriteString(RightArrowFilled)
+	}
+	bar.WriteString(colors.Reset)
+	return bar.String()
+}
+
+// PowerlineBarRight joins segments into a status bar for the right edge of the
+// screen: each segment starts with a LeftArrowFilled glyph blending the previous
+// background (or the terminal's, for the first) into its own.
+func PowerlineBarRight(segments ...Segment) string {
+	var bar strings.Builder
+	for i, seg := range segments {
+		bar.WriteString(colors.Reset + colors.FromHex(seg.Bg))
+		if i > 0 {
+			bar.WriteString(colors.BgFromHex(segments[i-1].Bg))
+		}
+		bar.WriteString(LeftArrowFilled)
+		bar.WriteString(colors.BgFromHex(seg.Bg) + colors.FromHex(seg.Fg) + " " + seg.Text + " ")
+	}
+	bar.WriteString(colors.Reset)
+	return bar.String()
+}
@@ -0,0 +1,345 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// AccordionSection is one collapsible section of an Accordion. Its elements are
+// only rendered and focusable while it is expanded.
+type AccordionSection struct {
+	Title     string
+	Elements  []UIElement // Elements positioned relative to the section's content area, under its header
+	Height    int         // Rows the content takes while expanded
+	expanded  bool
+	accordion *Accordion
+}
+
+// AddElement adds a UI element to the section.
+func (s *AccordionSection) AddElement(element UIElement) {
+	s.Elements = append(s.Elements, element)
+	if s.expanded && s.accordion != nil && s.accordion.window != nil {
+		s.accordion.window.syncAccordion(s.accordion) // Make the new element focusable right away
+	}
+}
+
+// IsExpanded reports whether the section's content is shown.
+func (s *AccordionSection) IsExpanded() bool {
+	return s.expanded
+}
+
+// Accordion stacks titled sections whose content can be expanded and collapsed.
+// Up/Down move between the headers while it is focused, Enter/Space toggle the
+// highlighted section, and Right/Left expand and collapse it. Only expanded
+// sections take space and have their elements in the window's focus cycle, right
+// after the headers. Put the accordion in a VBox to move the elements below it
+// as it grows and shrinks.
+type Accordion struct {
+	X, Y         int // Position relative to window content area
+	Width        int // Width of the headers and content areas
+	Sections     []*AccordionSection
+	Selected     int                            // Index of the highlighted header
+	SingleExpand bool                           // Expanding a section collapses the others
+	Color        string                         // Color of the headers
+	ActiveColor  string                         // Color of the highlighted header when focused
+	IsActive     bool                           // State for rendering/input handling
+	Disabled     bool                           // Disabled accordions are dimmed and skipped by focus traversal
+	Hidden       bool                           // Hidden accordions are not rendered and are skipped by focus traversal
+	OnToggle     func(index int, expanded bool) // Called when a section is expanded or collapsed
+	enableState  enableGroup                    // Tracks section elements disabled via SetEnabled
+	window       *Window                        // Window the accordion was added to, for focus bookkeeping
+	themed
+}
+
+// NewAccordion creates a new Accordion without sections; add them with AddSection.
+func NewAccordion(x, y, width int, color, activeColor string) *Accordion {
+	return &Accordion{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Sections:    make([]*AccordionSection, 0),
+		Color:       color,
+		ActiveColor: activeColor,
+	}
+}
+
+// AddSection appends a collapsed section with the given title, whose content takes
+// height rows when expanded, and returns it.
+func (a *Accordion) AddSection(title string, height int) *AccordionSection {
+	section := &AccordionSection{Title: title, Height: height, Elements: make([]UIElement, 0), accordion: a}
+	a.Sections = append(a.Sections, section)
+	return section
+}
+
+// SetExpanded expands or collapses the section at index and calls OnToggle. With
+// SingleExpand, expanding a section collapses the others. Out of range indexes
+// are ignored.
+func (a *Accordion) SetExpanded(index int, expanded bool) {
+	if index < 0 || index >= len(a.Sections) || a.Sections[index].expanded == expanded {
+		return
+	}
+	if expanded && a.SingleExpand {
+		for i, section := range a.Sections {
+			if section.expanded {
+				section.expanded = false
+				if a.OnToggle != nil {
+					a.OnToggle(i, false)
+				}
+			}
+		}
+	}
+	a.Sections[index].expanded = expanded
+	if a.window != nil {
+		a.window.syncAccordion(a)
+	}
+	if a.OnToggle != nil {
+		a.OnToggle(index, expanded)
+	}
+}
+
+// Toggle expands the section at index if it is collapsed, and collapses it otherwise.
+func (a *Accordion) Toggle(index int) {
+	if index >= 0 && index < len(a.Sections) {
+		a.SetExpanded(index, !a.Sections[index].expanded)
+	}
+}
+
+// SetActive sets the focus state of the accordion's headers.
+func (a *Accordion) SetActive(active bool) {
+	a.IsActive = active
+}
+
+// Select highlights the header at index, clamped to the sections.
+func (a *Accordion) Select(index int) {
+	if index >= len(a.Sections) {
+		index = len(a.Sections) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	a.Selected = index
+}
+
+// sectionTop returns the row of the header of the section at index, relative to
+// the accordion: the rows above it are the headers and expanded content before it.
+func (a *Accordion) sectionTop(index int) int {
+	row := 0
+	for _, section := range a.Sections[:index] {
+		row++
+		if section.expanded {
+			row += section.Height
+		}
+	}
+	return row
+}
+
+// headerAt returns the index of the section whose header is at row (relative to
+// the accordion), or -1.
+func (a *Accordion) headerAt(row int) int {
+	for i := range a.Sections {
+		if a.sectionTop(i) == row {
+			return i
+		}
+	}
+	return -1
+}
+
+// shownElements returns the elements of the expanded sections.
+func (a *Accordion) shownElements() []UIElement {
+	var elements []UIElement
+	for _, section := range a.Sections {
+		if section.expanded {
+			elements = append(elements, section.Elements...)
+		}
+	}
+	return elements
+}
+
+// allElements returns the elements of every section.
+func (a *Accordion) allElements() []UIElement {
+	var elements []UIElement
+	for _, section := range a.Sections {
+		elements = append(elements, section.Elements...)
+	}
+	return elements
+}
+
+// sectionOf returns the index of the section holding element, directly or inside
+// a segment in it, or -1.
+func (a *Accordion) sectionOf(element UIElement) int {
+	for i, section := range a.Sections {
+		if containsElement(section.Elements, element) {
+			return i
+		}
+	}
+	return -1
+}
+
+// segmentParent returns the Segment or SegmentGroup in one of the accordion's
+// sections that holds element directly, or nil.
+func (a *Accordion) segmentParent(element UIElement) UIElement {
+	return segmentParent(a.allElements(), element)
+}
+
+// HandleInput implements InputHandler: Up/Down, Home/End and PageUp/PageDown
+// highlight a header, Right/Left expand and collapse its section and Enter or
+// Space toggles it.
+func (a *Accordion) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !a.IsActive {
+		return false, false, false
+	}
+	switch {
+	case ev.Key == KeyUp: // Previous header
+		a.Select(a.Selected - 1)
+	case ev.Key == KeyDown: // Next header
+		a.Select(a.Selected + 1)
+	case ev.Key == KeyHome, ev.Key == KeyPageUp: // First header
+		a.Select(0)
+	case ev.Key == KeyEnd, ev.Key == KeyPageDown: // Last header
+		a.Select(len(a.Sections) - 1)
+	case ev.Key == KeyRight: // Expand
+		a.SetExpanded(a.Selected, true)
+	case ev.Key == KeyLeft: // Collapse
+		a.SetExpanded(a.Selected, false)
+	case ev.Key == KeyEnter, ev.Key == KeyRune && ev.Mod == 0 && ev.Rune == ' ': // Toggle the highlighted section
+		a.Toggle(a.Selected)
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface; the accordion asks for the cursor
+// on behalf of an active element in an expanded section.
+func (a *Accordion) NeedsCursor() bool {
+	return a.activeCursorManager() != nil
+}
+
+// GetCursorPosition implements CursorManager interface
+func (a *Accordion) GetCursorPosition() (int, int, bool) {
+	if cm := a.activeCursorManager(); cm != nil {
+		return cm.GetCursorPosition()
+	}
+	return 0, 0, false
+}
+
+// GetCursorShape implements CursorShaper for the element holding the cursor.
+func (a *Accordion) GetCursorShape() CursorShape {
+	if shaper, ok := a.activeCursorManager().(CursorShaper); ok {
+		return shaper.GetCursorShape()
+	}
+	return CursorDefault
+}
+
+// activeCursorManager returns the expanded sections' element that wants the cursor, if any.
+func (a *Accordion) activeCursorManager() CursorManager {
+	for _, element := range a.shownElements() {
+		if cm, ok := element.(CursorManager); ok && cm.NeedsCursor() && isVisible(element) {
+			return cm
+		}
+	}
+	return nil
+}
+
+// Bounds implements Bounded: the headers and the expanded sections' content.
+func (a *Accordion) Bounds() (int, int, int, int) {
+	return a.X, a.Y, a.Width, a.sectionTop(len(a.Sections))
+}
+
+// SetPosition implements Positioner.
+func (a *Accordion) SetPosition(x, y int) {
+	a.X, a.Y = x, y
+}
+
+// SetSize implements Sizer; the height follows the expanded sections.
+func (a *Accordion) SetSize(width, height int) {
+	a.Width = width
+}
+
+// SetEnabled implements Enabler. It enables or disables the headers and the
+// elements of every section. Re-enabling leaves elements that were disabled
+// individually disabled.
+func (a *Accordion) SetEnabled(enabled bool) {
+	a.Disabled = !enabled
+	a.enableState.set(enabled, a.allElements())
+}
+
+// IsEnabled implements Enabler.
+func (a *Accordion) IsEnabled() bool {
+	return !a.Disabled
+}
+
+// SetVisible implements Hider.
+func (a *Accordion) SetVisible(visible bool) {
+	a.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (a *Accordion) IsVisible() bool {
+	return !a.Hidden
+}
+
+// Render draws each section's header and, under the expanded ones, their elements.
+func (a *Accordion) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + a.X
+	absY := winY + a.Y
+
+	headerColor := themeColor(a.Color, a.theme().Accent)
+	if a.Disabled {
+		headerColor = a.disabledColor()
+	}
+
+	row := 0
+	for i, section := range a.Sections {
+		// 1. Header: an arrow showing the state, and the title
+		arrow := glyphs.collapsed
+		if section.expanded {
+			arrow = glyphs.expanded
+		}
+		header := alignText(truncateToWidth(arrow+" "+section.Title, a.Width), a.Width, AlignLeft)
+		buffer.WriteString(MoveCursorCmd(absY+row, absX))
+		if i == a.Selected && a.IsActive && !a.Disabled {
+			buffer.WriteString(themeColor(a.ActiveColor, a.theme().Active()) + ReverseVideo())
+		} else {
+			buffer.WriteString(headerColor)
+		}
+		buffer.WriteString(header)
+		buffer.WriteString(colors.Reset)
+		row++
+		if !section.expanded {
+			continue
+		}
+
+		// 2. Content: cleared, then the section's elements under the header
+		for r := 0; r < section.Height; r++ {
+			buffer.WriteString(MoveCursorCmd(absY+row+r, absX))
+			buffer.WriteString(strings.Repeat(" ", a.Width))
+		}
+		for _, element := range section.Elements {
+			if isVisible(element) {
+				renderElement(element, a.windowTheme, buffer, absX, absY+row, a.Width)
+			}
+		}
+		row += section.Height
+	}
+}
+
+// syncAccordion updates the focus list after a section of accordion was expanded
+// or collapsed: only the expanded sections' elements are focusable, right after the
+// headers. Focus inside a collapsed section moves to the headers.
+func (w *Window) syncAccordion(accordion *Accordion) {
+	w.syncChildFocus(accordion, accordion.allElements(), accordion.shownElements())
+}
+
+// accordionFor returns the window's Accordion that contains element, or nil.
+func (w *Window) accordionFor(element UIElement) *Accordion {
+	if element == nil {
+		return nil
+	}
+	for _, el := range w.Elements {
+		if accordion, ok := el.(*Accordion); ok && accordion.sectionOf(element) != -1 {
+			return accordion
+		}
+	}
+	return nil
+}
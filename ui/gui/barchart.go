@@ -0,0 +1,182 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"window-go/colors"
+)
+
+// Bar is one category of a BarChart.
+type Bar struct {
+	Label string  // Category name, shown left of the bar
+	Value float64 // Length of the bar (negative, NaN and infinite values draw as 0)
+	Color string  // Color of the bar ("" = the chart's Color)
+}
+
+// BarChart draws horizontal bars for reporting dashboards: category labels on the
+// left, bars scaled so Max fills the width, and an axis below labeled from 0 to Max.
+// Labels are truncated when the chart is too narrow to fit them.
+//
+//	CPU │██████████▓ 72
+//	Mem │█████ 35
+//	    └──────────────
+//	    0          100
+type BarChart struct {
+	X, Y       int     // Position relative to window content area
+	Width      int     // Total width, including labels and value readouts
+	Bars       []Bar   // One row per bar, top to bottom
+	Max        float64 // Value of a full-width bar (0 = the largest value)
+	Format     string  // fmt verb for the values and the axis labels ("%.0f" if empty)
+	ShowValues bool    // Write each bar's value after its end
+	Color      string  // Color of bars without their own
+	LabelColor string  // Color of the labels, the axis and the values
+	Hidden     bool    // Hidden charts are not rendered
+	themed
+}
+
+// NewBarChart creates a BarChart at (x, y), width cells wide, showing bars.
+func NewBarChart(x, y, width int, bars []Bar, color string) *BarChart {
+	return &BarChart{X: x, Y: y, Width: width, Bars: bars, Color: color}
+}
+
+// SetBars replaces the bars shown.
+func (bc *BarChart) SetBars(bars []Bar) {
+	bc.Bars = bars
+}
+
+// format formats value for the readouts and axis labels.
+func (bc *BarChart) format(value float64) string {
+	format := bc.Format
+	if format == "" {
+		format = "%.0f"
+	}
+	return fmt.Sprintf(format, value)
+}
+
+// readout returns the value written after a bar: its formatted value, or nothing
+// for NaN and infinite values.
+func (bc *BarChart) readout(value float64) string {
+	if !isFinite(value) {
+		return ""
+	}
+	return bc.format(value)
+}
+
+// scale returns the value of a full-width bar: Max, or the largest finite value.
+func (bc *BarChart) scale() float64 {
+	if bc.Max > 0 && isFinite(bc.Max) {
+		return bc.Max
+	}
+	scale := 0.0
+	for _, bar := range bc.Bars {
+		if bar.Value > scale && isFinite(bar.Value) {
+			scale = bar.Value
+		}
+	}
+	return scale
+}
+
+// columns returns the widths of the label column, the bars and the value readouts
+// (a space and the widest value). Labels are narrowed to a third of the width, and
+// readouts dropped, when the bars would otherwise not fit.
+func (bc *BarChart) columns() (labelWidth, barWidth, valueWidth int) {
+	for _, bar := range bc.Bars {
+		if w := DisplayWidth(bar.Label); w > labelWidth {
+			labelWidth = w
+		}
+		if w := DisplayWidth(bc.readout(bar.Value)); bc.ShowValues && w+1 > valueWidth {
+			valueWidth = w + 1
+		}
+	}
+	barWidth = bc.Width - labelWidth - 2 - valueWidth // A space and the axis after the labels
+	if barWidth < 1 && labelWidth > bc.Width/3 {
+		labelWidth = bc.Width / 3
+		barWidth = bc.Width - labelWidth - 2 - valueWidth
+	}
+	if barWidth < 1 {
+		barWidth += valueWidth
+		valueWidth = 0
+	}
+	return labelWidth, barWidth, valueWidth
+}
+
+// Bounds implements Bounded: a row per bar, the axis and its labels.
+func (bc *BarChart) Bounds() (int, int, int, int) {
+	return bc.X, bc.Y, bc.Width, len(bc.Bars) + 2
+}
+
+// SetPosition implements Positioner.
+func (bc *BarChart) SetPosition(x, y int) {
+	bc.X, bc.Y = x, y
+}
+
+// SetSize implements Sizer; the height follows the number of bars.
+func (bc *BarChart) SetSize(width, height int) {
+	bc.Width = width
+}
+
+// SetVisible implements Hider.
+func (bc *BarChart) SetVisible(visible bool) {
+	bc.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (bc *BarChart) IsVisible() bool {
+	return !bc.Hidden
+}
+
+// Render draws the labeled bars, then the axis with 0 and the scale's maximum.
+func (bc *BarChart) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + bc.X
+	absY := winY + bc.Y
+	labelWidth, barWidth, valueWidth := bc.columns()
+	if barWidth < 1 {
+		return
+	}
+	scale := bc.scale()
+
+	for i, bar := range bc.Bars {
+		buffer.WriteString(MoveCursorCmd(absY+i, absX))
+		buffer.WriteString(bc.LabelColor)
+		buffer.WriteString(alignText(truncateToWidth(bar.Label, labelWidth), labelWidth, AlignRight))
+		buffer.WriteString(" " + glyphs.vertical + colors.Reset)
+
+		// Whole cells filled, and a shaded cell for a filled part of at least half
+		cells := 0.0
+		if scale > 0 && bar.Value > 0 {
+			cells = bar.Value / scale * float64(barWidth)
+		}
+		if !isFinite(cells) {
+			cells = 0
+		}
+		cells = math.Max(0, math.Min(cells, float64(barWidth)))
+		filled := int(cells)
+		fill := strings.Repeat(glyphs.fill, filled)
+		if filled < barWidth && cells-float64(filled) >= 0.5 {
+			fill += glyphs.trackFilled
+			filled++
+		}
+		buffer.WriteString(themeColor(themeColor(bar.Color, bc.Color), bc.theme().Accent) + fill + colors.Reset)
+
+		rest := barWidth - filled + valueWidth
+		if valueWidth > 0 {
+			value := " " + bc.readout(bar.Value)
+			buffer.WriteString(bc.LabelColor + value + colors.Reset)
+			rest -= DisplayWidth(value)
+		}
+		buffer.WriteString(strings.Repeat(" ", rest))
+	}
+
+	// Axis under the bars, labeled with 0 under the corner and the maximum at the end
+	axisX := absX + labelWidth + 1
+	buffer.WriteString(MoveCursorCmd(absY+len(bc.Bars), axisX))
+	buffer.WriteString(bc.LabelColor + glyphs.axisCorner + strings.Repeat(glyphs.horizontal, barWidth) + colors.Reset)
+	buffer.WriteString(MoveCursorCmd(absY+len(bc.Bars)+1, axisX))
+	zero, max := bc.format(0), bc.format(scale)
+	axisLabels := truncateToWidth(zero, barWidth+1)
+	if DisplayWidth(zero)+1+DisplayWidth(max) <= barWidth+1 {
+		axisLabels = alignText(zero, barWidth+1-DisplayWidth(max), AlignLeft) + max
+	}
+	buffer.WriteString(bc.LabelColor + axisLabels + colors.Reset)
+}
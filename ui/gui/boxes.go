@@ -6,19 +6,55 @@ import (
 	"strings"
 	"unicode/utf8"
 	"window-go/colors"
+	"window-go/ui/chars"
 
 	"golang.org/x/term"
 )
 
 // ANSI Escape Codes
 const (
-	clearScreen          = "\x1b[2J"
-	clearScreenAndBuffer = "\033[H\033[2J\033[3J"
-	moveCursorFormat     = "\x1b[%d;%dH" // row, col (1-based) - Renamed format string
-	hideCursor           = "\x1b[?25l"
-	showCursor           = "\x1b[?25h"
+	clearScreen           = "\x1b[2J"
+	clearScreenAndBuffer  = "\033[H\033[2J\033[3J"
+	moveCursorFormat      = "\x1b[%d;%dH" // row, col (1-based) - Renamed format string
+	hideCursor            = "\x1b[?25l"
+	showCursor            = "\x1b[?25h"
+	cursorShapeFormat     = "\x1b[%d q"      // DECSCUSR
+	cursorColorFormat     = "\x1b]12;%s\x07" // OSC 12
+	resetCursorColor      = "\x1b]112\x07"   // OSC 112
+	terminalTitleFormat   = "\x1b]2;%s\x07"  // OSC 2
+	pushTerminalTitle     = "\x1b[22;0t"     // XTWINOPS: save title on the terminal's stack
+	popTerminalTitle      = "\x1b[23;0t"     // XTWINOPS: restore saved title
+	bell                  = "\a"
+	mouseEnable           = "\x1b[?1002h\x1b[?1006h" // xterm: report button presses, drags and the wheel, in SGR encoding
+	mouseDisable          = "\x1b[?1006l\x1b[?1002l"
+	bracketedPasteEnable  = "\x1b[?2004h" // Pasted text arrives between pasteStart and pasteEnd
+	bracketedPasteDisable = "\x1b[?2004l"
+	pasteStart            = "\x1b[200~"
+	pasteEnd              = "\x1b[201~"
 )
 
+// BellEnabled controls whether Bell makes a sound. Set it to false to silence the bell.
+var BellEnabled = true
+
+// CursorShape is a terminal cursor style, as set with the DECSCUSR escape sequence.
+type CursorShape int
+
+const (
+	CursorDefault           CursorShape = iota // The terminal's configured cursor
+	CursorBlinkingBlock                        // Blinking block
+	CursorSteadyBlock                          // Steady block
+	CursorBlinkingUnderline                    // Blinking underline
+	CursorSteadyUnderline                      // Steady underline
+	CursorBlinkingBar                          // Blinking vertical bar
+	CursorSteadyBar                            // Steady vertical bar
+)
+
+// CursorShaper is implemented by CursorManager elements that want a specific
+// cursor shape while they show the cursor (e.g. a bar for text input).
+type CursorShaper interface {
+	GetCursorShape() CursorShape
+}
+
 // ClearScreen clears the entire terminal screen.
 func ClearScreen() string { // Return string instead of printing directly
 	return clearScreen
@@ -51,6 +87,42 @@ func ShowCursor() string { // Return string
 	return showCursor
 }
 
+// CursorShapeCmd returns the ANSI escape code string to change the cursor shape.
+func CursorShapeCmd(shape CursorShape) string {
+	return fmt.Sprintf(cursorShapeFormat, int(shape))
+}
+
+// EmitCursorShape changes the cursor shape of the terminal on stdout.
+// Use CursorDefault to restore the terminal's configured shape.
+// Window.EmitCursorShape writes to the window's Output instead.
+func EmitCursorShape(shape CursorShape) {
+	fmt.Print(CursorShapeCmd(shape))
+}
+
+// CursorColorCmd returns the escape code string to change the cursor color.
+// color is any color spec the terminal accepts, e.g. "#ff8800" or "orange".
+func CursorColorCmd(color string) string {
+	return fmt.Sprintf(cursorColorFormat, color)
+}
+
+// ResetCursorColorCmd returns the escape code string that restores the terminal's cursor color.
+func ResetCursorColorCmd() string {
+	return resetCursorColor
+}
+
+// TerminalTitleCmd returns the escape code string that sets the terminal window/tab title.
+func TerminalTitleCmd(title string) string {
+	return fmt.Sprintf(terminalTitleFormat, title)
+}
+
+// Bell rings the bell of the terminal on stdout (e.g. to signal an error), unless
+// BellEnabled is false. Window.Bell rings the window's Output instead.
+func Bell() {
+	if BellEnabled {
+		fmt.Print(bell)
+	}
+}
+
 // ClearLineSuffix returns ANSI sequence to clear from cursor to end of line
 func ClearLineSuffix() string {
 	return "\x1b[K"
@@ -71,14 +143,27 @@ func ResetVideo() string {
 	return "\x1b[27m"
 }
 
-// BoxType defines the structure for different box styles
+// BoxType defines the structure for different box styles. Each field is the
+// string drawn for one part of the frame and should be one column wide.
 type BoxType struct {
-	TopLeft     string
-	TopRight    string
-	BottomLeft  string
-	BottomRight string
-	Horizontal  string
-	Vertical    string
+	TopLeft     string // Top-left corner
+	TopRight    string // Top-right corner
+	BottomLeft  string // Bottom-left corner
+	BottomRight string // Bottom-right corner
+	Horizontal  string // Top and bottom edges
+	Vertical    string // Left and right edges
+	TopTee      string // Top edge meeting a vertical divider (┬); empty = Horizontal
+	BottomTee   string // Bottom edge meeting a vertical divider (┴); empty = Horizontal
+	LeftTee     string // Left edge meeting a horizontal divider (├); empty = Vertical
+	RightTee    string // Right edge meeting a horizontal divider (┤); empty = Vertical
+	Cross       string // Crossing of a vertical and a horizontal divider (┼); empty = Vertical
+}
+
+// RegisterBoxType adds (or replaces) a box style that windows, segments and
+// banners can then use by name, e.g. RegisterBoxType("stars", BoxType{...}) for
+// NewWindow(..., "stars", ...). Unknown names still fall back to "single".
+func RegisterBoxType(name string, b BoxType) {
+	BoxTypes[name] = b
 }
 
 // TextAlignment defines the structure for text alignment
@@ -96,6 +181,11 @@ var (
 			BottomRight: "┘",
 			Horizontal:  "─",
 			Vertical:    "│",
+			TopTee:      "┬",
+			BottomTee:   "┴",
+			LeftTee:     "├",
+			RightTee:    "┤",
+			Cross:       "┼",
 		},
 		"double": {
 			TopLeft:     "╔",
@@ -104,6 +194,11 @@ var (
 			BottomRight: "╝",
 			Horizontal:  "═",
 			Vertical:    "║",
+			TopTee:      "╦",
+			BottomTee:   "╩",
+			LeftTee:     "╠",
+			RightTee:    "╣",
+			Cross:       "╬",
 		},
 		"round": {
 			TopLeft:     "╭",
@@ -112,6 +207,24 @@ var (
 			BottomRight: "╯",
 			Horizontal:  "─",
 			Vertical:    "│",
+			TopTee:      "┬",
+			BottomTee:   "┴",
+			LeftTee:     "├",
+			RightTee:    "┤",
+			Cross:       "┼",
+		},
+		"rounded": { // Same as "round"
+			TopLeft:     "╭",
+			TopRight:    "╮",
+			BottomLeft:  "╰",
+			BottomRight: "╯",
+			Horizontal:  "─",
+			Vertical:    "│",
+			TopTee:      "┬",
+			BottomTee:   "┴",
+			LeftTee:     "├",
+			RightTee:    "┤",
+			Cross:       "┼",
 		},
 		"bold": {
 			TopLeft:     "┏",
@@ -120,10 +233,150 @@ var (
 			BottomRight: "┛",
 			Horizontal:  "━",
 			Vertical:    "┃",
+			TopTee:      "┳",
+			BottomTee:   "┻",
+			LeftTee:     "┣",
+			RightTee:    "┫",
+			Cross:       "╋",
+		},
+		"ascii": { // For terminals without box drawing characters
+			TopLeft:     "+",
+			TopRight:    "+",
+			BottomLeft:  "+",
+			BottomRight: "+",
+			Horizontal:  "-",
+			Vertical:    "|",
+			TopTee:      "+",
+			BottomTee:   "+",
+			LeftTee:     "+",
+			RightTee:    "+",
+			Cross:       "+",
+		},
+		"dashed": {
+			TopLeft:     "┌",
+			TopRight:    "┐",
+			BottomLeft:  "└",
+			BottomRight: "┘",
+			Horizontal:  "╌",
+			Vertical:    "╎",
+			TopTee:      "┬",
+			BottomTee:   "┴",
+			LeftTee:     "├",
+			RightTee:    "┤",
+			Cross:       "┼",
+		},
+		"double-single": { // Double top and bottom edges, single sides
+			TopLeft:     "╒",
+			TopRight:    "╕",
+			BottomLeft:  "╘",
+			BottomRight: "╛",
+			Horizontal:  "═",
+			Vertical:    "│",
+			TopTee:      "╤",
+			BottomTee:   "╧",
+			LeftTee:     "╞",
+			RightTee:    "╡",
+			Cross:       "╪",
+		},
+		"single-double": { // Single top and bottom edges, double sides
+			TopLeft:     "╓",
+			TopRight:    "╖",
+			BottomLeft:  "╙",
+			BottomRight: "╜",
+			Horizontal:  "─",
+			Vertical:    "║",
+			TopTee:      "╥",
+			BottomTee:   "╨",
+			LeftTee:     "╟",
+			RightTee:    "╢",
+			Cross:       "╫",
 		},
 	}
 )
 
+// asciiMode is set by SetASCIIMode.
+var asciiMode bool
+
+// glyphSet holds the characters the element renderers draw besides text and frames.
+type glyphSet struct {
+	fill         string // Filled part of progress bars; slider thumb
+	empty        string // Empty part of progress bars and sliders
+	trackFilled  string // Slider track left of the thumb
+	scrollThumb  string // Default scrollbar thumb
+	scrollTrack  string // Default vertical scrollbar track
+	scrollTrackH string // Default horizontal scrollbar track
+	submenuArrow string // Marks menu items that open a submenu
+	knob         string // ToggleKnob switch knob
+	knobTrack    string // ToggleKnob switch track
+	check        string // Checked menu item
+	radio        string // Checked radio menu item
+	teeLeft      string // Left end of a separator line inside a frame
+	teeRight     string // Right end of a separator line inside a frame
+	cross        string // Crossing of table column and header lines
+	horizontal   string // Separator lines (tables, menus)
+	vertical     string // Column separators (tables, segment groups)
+	powerArrow   string // End of a PowerBar segment
+	tick         string // Gauge tick mark
+	sparkBars    string // Sparkline bar heights, lowest first
+	axisCorner   string // BarChart axis corner
+	collapsed    string // Header of a collapsed Accordion section
+	expanded     string // Header of an expanded Accordion section
+	crumb        string // Default Breadcrumb separator
+}
+
+var (
+	unicodeGlyphs = glyphSet{
+		fill: "█", empty: "░", trackFilled: "▓",
+		scrollThumb: "█", scrollTrack: "│", scrollTrackH: "─",
+		submenuArrow: "▶", knob: "●", knobTrack: "━", check: "✓", radio: "●",
+		teeLeft: "├", teeRight: "┤", cross: "┼", horizontal: "─", vertical: "│",
+		powerArrow: chars.RightArrowFilled, tick: "╵",
+		sparkBars: "▁▂▃▄▅▆▇█", axisCorner: "└",
+		collapsed: "▶", expanded: "▼", crumb: "›",
+	}
+	asciiGlyphs = glyphSet{
+		fill: "#", empty: "-", trackFilled: "=",
+		scrollThumb: "#", scrollTrack: "|", scrollTrackH: "-",
+		submenuArrow: ">", knob: "o", knobTrack: "-", check: "x", radio: "*",
+		teeLeft: "+", teeRight: "+", cross: "+", horizontal: "-", vertical: "|",
+		powerArrow: ">", tick: "'",
+		sparkBars: "._-=+*#@", axisCorner: "+",
+		collapsed: ">", expanded: "v", crumb: ">",
+	}
+	glyphs = unicodeGlyphs // Glyphs in use (see SetASCIIMode)
+)
+
+// SetASCIIMode switches all drawing to plain ASCII for terminals without good
+// Unicode support: frames use the "ascii" box style (+-|), progress bars #/-,
+// scrollbars #/| and submenu arrows >. It affects every window and element
+// rendered afterwards.
+func SetASCIIMode(enabled bool) {
+	asciiMode = enabled
+	if enabled {
+		glyphs = asciiGlyphs
+	} else {
+		glyphs = unicodeGlyphs
+	}
+}
+
+// ASCIIMode reports whether SetASCIIMode is on.
+func ASCIIMode() bool {
+	return asciiMode
+}
+
+// frameBox returns the box style named name ("single" if unknown), or the "ascii"
+// style in ASCII mode.
+func frameBox(name string) BoxType {
+	if asciiMode {
+		return BoxTypes["ascii"]
+	}
+	box, exists := BoxTypes[name]
+	if !exists {
+		box = BoxTypes["single"]
+	}
+	return box
+}
+
 func PrintColoredText(text string, color string) {
 	// Print colored text
 	fmt.Printf("%s%s%s", color, text, colors.Reset)
@@ -335,10 +588,7 @@ func wrapText(text string, width int) []string {
 
 func PrintBanner(text string, boxStyle string, textColor string, bgColor string, borderColor string, width int, height int, alignment TextAlignment) {
 	fmt.Print(colors.Reset)
-	box, exists := BoxTypes[boxStyle]
-	if !exists {
-		box = BoxTypes["single"]
-	}
+	box := frameBox(boxStyle)
 
 	padding := 2
 	effectiveWidth := width - (padding * 2)
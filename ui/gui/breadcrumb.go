@@ -0,0 +1,265 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// Breadcrumb shows a path, e.g. the folders leading to a file, as items separated
+// by a glyph. Left/Right highlight an item while it is focused, and Enter or a
+// click navigates to it through OnNavigate. When the path is wider than Width,
+// items from the middle are replaced by an ellipsis; the first and last items,
+// and the highlighted one, stay visible.
+type Breadcrumb struct {
+	X, Y        int             // Position relative to window content area
+	Width       int             // Width available for the path
+	Items       []string        // Path items, outermost first
+	Separator   string          // Drawn between items ("" = " › "), e.g. " " + chars.RightArrowFilled + " "
+	Selected    int             // Index of the highlighted item
+	Color       string          // Color of the items
+	ActiveColor string          // Color of the highlighted item when focused
+	IsActive    bool            // State for rendering/input handling
+	Disabled    bool            // Disabled breadcrumbs are dimmed and skipped by focus traversal
+	Hidden      bool            // Hidden breadcrumbs are not rendered and are skipped by focus traversal
+	OnNavigate  func(index int) // Called when an item is chosen with Enter or a click
+	themed
+}
+
+// crumb is an item of a Breadcrumb as laid out: the item's index (-1 for an
+// ellipsis standing for hidden items), its text and its column.
+type crumb struct {
+	index int
+	text  string
+	x     int
+}
+
+// NewBreadcrumb creates a Breadcrumb at (x, y) showing items, with the last one
+// highlighted.
+func NewBreadcrumb(x, y, width int, items []string, color, activeColor string) *Breadcrumb {
+	return &Breadcrumb{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Items:       items,
+		Selected:    len(items) - 1,
+		Color:       color,
+		ActiveColor: activeColor,
+	}
+}
+
+// SetItems replaces the path, highlighting its last item.
+func (b *Breadcrumb) SetItems(items []string) {
+	b.Items = items
+	b.Selected = len(items) - 1
+}
+
+// Select highlights the item at index, clamped to the items.
+func (b *Breadcrumb) Select(index int) {
+	if index >= len(b.Items) {
+		index = len(b.Items) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	b.Selected = index
+}
+
+// Navigate calls OnNavigate for the highlighted item.
+func (b *Breadcrumb) Navigate() {
+	if b.OnNavigate != nil && b.Selected >= 0 && b.Selected < len(b.Items) {
+		b.OnNavigate(b.Selected)
+	}
+}
+
+// SetActive sets the focus state of the breadcrumb.
+func (b *Breadcrumb) SetActive(active bool) {
+	b.IsActive = active
+}
+
+// separator returns the text drawn between items.
+func (b *Breadcrumb) separator() string {
+	if b.Separator != "" {
+		return b.Separator
+	}
+	return " " + glyphs.crumb + " "
+}
+
+// layout returns the items to draw. If the whole path is too wide, the first,
+// last and highlighted items are kept, then as many of the others as fit, nearest
+// the end first, and each run of dropped items becomes one ellipsis. Crumbs past
+// Width are cut off by Render.
+func (b *Breadcrumb) layout() []crumb {
+	shown := make([]bool, len(b.Items))
+	for i := range shown {
+		shown[i] = true
+	}
+	if b.width(shown) > b.Width {
+		for i := range shown {
+			shown[i] = i == 0 || i == len(b.Items)-1 || i == b.Selected
+		}
+		for i := len(b.Items) - 2; i > 0; i-- {
+			if shown[i] {
+				continue
+			}
+			shown[i] = true
+			if b.width(shown) > b.Width {
+				shown[i] = false
+				break
+			}
+		}
+	}
+
+	var crumbs []crumb
+	x := 0
+	for i, item := range b.Items {
+		c := crumb{index: i, text: item}
+		if !shown[i] {
+			if i > 0 && !shown[i-1] {
+				continue // Same run of dropped items
+			}
+			c = crumb{index: -1, text: "…"}
+		}
+		if len(crumbs) > 0 {
+			x += DisplayWidth(b.separator())
+		}
+		c.x = x
+		crumbs = append(crumbs, c)
+		x += DisplayWidth(c.text)
+	}
+	return crumbs
+}
+
+// width returns the width of the path with only the items marked in shown, each
+// run of the others drawn as an ellipsis.
+func (b *Breadcrumb) width(shown []bool) int {
+	width, entries := 0, 0
+	for i, item := range b.Items {
+		switch {
+		case shown[i]:
+			width += DisplayWidth(item)
+		case i > 0 && !shown[i-1]:
+			continue // Same run of dropped items
+		default:
+			width += DisplayWidth("…")
+		}
+		entries++
+	}
+	if entries > 1 {
+		width += (entries - 1) * DisplayWidth(b.separator())
+	}
+	return width
+}
+
+// itemAt returns the index of the item drawn at column col, or -1.
+func (b *Breadcrumb) itemAt(col int) int {
+	for _, c := range b.layout() {
+		if c.index != -1 && col >= c.x && col < c.x+DisplayWidth(c.text) && col < b.Width {
+			return c.index
+		}
+	}
+	return -1
+}
+
+// HandleInput implements InputHandler: Left/Right and Home/End highlight an item,
+// and Enter navigates to it.
+func (b *Breadcrumb) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !b.IsActive {
+		return false, false, false
+	}
+	switch ev.Key {
+	case KeyLeft: // Previous item
+		b.Select(b.Selected - 1)
+	case KeyRight: // Next item
+		b.Select(b.Selected + 1)
+	case KeyHome: // First item
+		b.Select(0)
+	case KeyEnd: // Last item
+		b.Select(len(b.Items) - 1)
+	case KeyEnter: // Navigate to the highlighted item
+		b.Navigate()
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface (never needs cursor)
+func (b *Breadcrumb) NeedsCursor() bool {
+	return false
+}
+
+// GetCursorPosition implements CursorManager interface
+func (b *Breadcrumb) GetCursorPosition() (int, int, bool) {
+	return 0, 0, false
+}
+
+// Bounds implements Bounded.
+func (b *Breadcrumb) Bounds() (int, int, int, int) {
+	return b.X, b.Y, b.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (b *Breadcrumb) SetPosition(x, y int) {
+	b.X, b.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (b *Breadcrumb) SetSize(width, height int) {
+	b.Width = width
+}
+
+// SetEnabled implements Enabler.
+func (b *Breadcrumb) SetEnabled(enabled bool) {
+	b.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (b *Breadcrumb) IsEnabled() bool {
+	return !b.Disabled
+}
+
+// SetVisible implements Hider.
+func (b *Breadcrumb) SetVisible(visible bool) {
+	b.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (b *Breadcrumb) IsVisible() bool {
+	return !b.Hidden
+}
+
+// Render draws the path, the highlighted item in reverse video while focused.
+func (b *Breadcrumb) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	renderColor := themeColor(b.Color, b.theme().Content)
+	separatorColor := colors.Gray
+	if b.Disabled {
+		renderColor, separatorColor = b.disabledColor(), b.disabledColor()
+	}
+
+	buffer.WriteString(MoveCursorCmd(winY+b.Y, winX+b.X))
+	used := 0
+	for i, c := range b.layout() {
+		if i > 0 {
+			separator := truncateToWidth(b.separator(), b.Width-used)
+			buffer.WriteString(separatorColor + separator + colors.Reset)
+			used += DisplayWidth(separator)
+		}
+		if used >= b.Width {
+			break
+		}
+		text := truncateToWidth(c.text, b.Width-used)
+		switch {
+		case c.index == b.Selected && b.IsActive && !b.Disabled:
+			buffer.WriteString(themeColor(b.ActiveColor, b.theme().Active()) + ReverseVideo())
+		case c.index == -1:
+			buffer.WriteString(separatorColor)
+		default:
+			buffer.WriteString(renderColor)
+		}
+		buffer.WriteString(text + colors.Reset)
+		used += DisplayWidth(text)
+	}
+	if used < b.Width {
+		buffer.WriteString(strings.Repeat(" ", b.Width-used)) // Clear the rest, for a shorter path
+	}
+}
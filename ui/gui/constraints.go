@@ -0,0 +1,92 @@
+package gui
+
+// Anchor selects the edges of the window content area an element keeps its
+// distance to (see Constraints).
+type Anchor int
+
+const (
+	AnchorLeft Anchor = 1 << iota
+	AnchorTop
+	AnchorRight
+	AnchorBottom
+)
+
+// Constraints position and size an element relative to the window content area
+// instead of in fixed cells, so it adapts when the window is resized. They are
+// resolved before each Render; whatever they leave unset keeps the element's own
+// X, Y, width and height. For example, to keep a button 2 cells from the right
+// edge and 1 row from the bottom:
+//
+//	w.SetConstraints(okButton, Constraints{Anchor: AnchorRight | AnchorBottom, Right: 2, Bottom: 1})
+type Constraints struct {
+	Anchor                   Anchor // Edges to keep the distances below to
+	Left, Top, Right, Bottom int    // Distance in cells from each anchored edge
+	XPercent, YPercent       int    // Position in percent of the content size, on axes without an anchor (0 = keep)
+	WidthPercent             int    // Width in percent of the content width (0 = keep, or stretch between left and right anchors)
+	HeightPercent            int    // Height in percent of the content height (0 = keep, or stretch between top and bottom anchors)
+}
+
+// SetConstraints makes the window position (and, for elements that are Sizers,
+// size) element by c before each Render. It works for elements and layouts added
+// directly to the window; zero Constraints remove them.
+func (w *Window) SetConstraints(element Placeable, c Constraints) {
+	if c == (Constraints{}) {
+		delete(w.constraints, element)
+		return
+	}
+	if w.constraints == nil {
+		w.constraints = make(map[Placeable]Constraints)
+	}
+	w.constraints[element] = c
+}
+
+// resolveConstraints applies the constraints of every element for a content area
+// of width x height cells.
+func (w *Window) resolveConstraints(width, height int) {
+	for element, c := range w.constraints {
+		c.resolve(element, width, height)
+	}
+}
+
+// resolve positions and sizes element by c in a content area of width x height cells.
+func (c Constraints) resolve(element Placeable, width, height int) {
+	x, y, elementWidth, elementHeight := element.Bounds()
+	newWidth := c.span(elementWidth, width, c.WidthPercent, AnchorLeft|AnchorRight, c.Left, c.Right)
+	newHeight := c.span(elementHeight, height, c.HeightPercent, AnchorTop|AnchorBottom, c.Top, c.Bottom)
+	if sizer, ok := element.(Sizer); ok && (newWidth != elementWidth || newHeight != elementHeight) {
+		sizer.SetSize(newWidth, newHeight)
+		_, _, elementWidth, elementHeight = element.Bounds() // Some elements round or limit sizes
+	}
+	x = c.offset(x, elementWidth, width, c.XPercent, AnchorLeft, AnchorRight, c.Left, c.Right)
+	y = c.offset(y, elementHeight, height, c.YPercent, AnchorTop, AnchorBottom, c.Top, c.Bottom)
+	element.SetPosition(x, y)
+}
+
+// span returns the size along one axis: a percentage of the content size, the
+// room between both anchored edges, or size unchanged.
+func (c Constraints) span(size, contentSize, percent int, both Anchor, start, end int) int {
+	switch {
+	case percent > 0:
+		size = contentSize * percent / 100
+	case c.Anchor&both == both:
+		size = contentSize - start - end
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// offset returns the position along one axis: distance from the anchored start or
+// end edge, a percentage of the content size, or pos unchanged.
+func (c Constraints) offset(pos, size, contentSize, percent int, startAnchor, endAnchor Anchor, start, end int) int {
+	switch {
+	case c.Anchor&startAnchor != 0:
+		return start
+	case c.Anchor&endAnchor != 0:
+		return contentSize - end - size
+	case percent > 0:
+		return contentSize * percent / 100
+	}
+	return pos
+}
@@ -0,0 +1,249 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"window-go/colors"
+)
+
+const (
+	datePickerWidth  = 20 // 7 day columns of 2 cells, a space apart
+	datePickerHeight = 8  // Month title, weekday headers and up to 6 weeks
+)
+
+// DatePicker is a month calendar for picking a day. Left/Right move by a day and
+// Up/Down by a week (wrapping into the neighbouring months), PageUp/PageDown change
+// the month and Home/End jump to its first and last day. It always takes
+// datePickerWidth x datePickerHeight cells, however many weeks the month spans.
+type DatePicker struct {
+	X, Y         int                  // Position relative to window content area
+	Value        time.Time            // Selected day (midnight, in its location)
+	FirstWeekday time.Weekday         // Weekday of the first column (time.Sunday by default)
+	Color        string               // Color of the day cells
+	ActiveColor  string               // Color of the selected day while focused
+	HeaderColor  string               // Color of the month title and weekday headers
+	IsActive     bool                 // State for rendering/input handling
+	Disabled     bool                 // Disabled date pickers are dimmed and skipped by focus traversal
+	Hidden       bool                 // Hidden date pickers are not rendered and are skipped by focus traversal
+	OnChange     func(date time.Time) // Called when the selected day changes
+	themed
+}
+
+// NewDatePicker creates a DatePicker at (x, y) showing the month of value, with
+// value's day selected.
+func NewDatePicker(x, y int, value time.Time, color, activeColor string) *DatePicker {
+	return &DatePicker{
+		X:           x,
+		Y:           y,
+		Value:       dateOf(value),
+		Color:       color,
+		ActiveColor: activeColor,
+		HeaderColor: colors.BoldWhite,
+	}
+}
+
+// dateOf returns midnight of t's day, in t's location.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// daysIn returns the number of days in the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// SetValue selects the day of date, showing its month. OnChange is called if the
+// day changed.
+func (dp *DatePicker) SetValue(date time.Time) {
+	date = dateOf(date)
+	if date.Equal(dp.Value) {
+		return
+	}
+	dp.Value = date
+	if dp.OnChange != nil {
+		dp.OnChange(date)
+	}
+}
+
+// AddDays moves the selection by days (negative moves back), across months.
+func (dp *DatePicker) AddDays(days int) {
+	dp.SetValue(dp.Value.AddDate(0, 0, days))
+}
+
+// AddMonths moves the selection by months, keeping the day of the month where it
+// exists (Jan 31 + 1 month is the last day of February).
+func (dp *DatePicker) AddMonths(months int) {
+	first := time.Date(dp.Value.Year(), dp.Value.Month()+time.Month(months), 1, 0, 0, 0, 0, dp.Value.Location())
+	day := dp.Value.Day()
+	if last := daysIn(first); day > last {
+		day = last
+	}
+	dp.SetValue(first.AddDate(0, 0, day-1))
+}
+
+// MonthStart selects the first day of the shown month (Home).
+func (dp *DatePicker) MonthStart() {
+	dp.AddDays(1 - dp.Value.Day())
+}
+
+// MonthEnd selects the last day of the shown month (End).
+func (dp *DatePicker) MonthEnd() {
+	dp.AddDays(daysIn(dp.Value) - dp.Value.Day())
+}
+
+// SetActive sets the focus state of the date picker.
+func (dp *DatePicker) SetActive(active bool) {
+	dp.IsActive = active
+}
+
+// firstColumn returns the grid column of the first day of the shown month.
+func (dp *DatePicker) firstColumn() int {
+	first := time.Date(dp.Value.Year(), dp.Value.Month(), 1, 0, 0, 0, 0, dp.Value.Location())
+	return (int(first.Weekday()) - int(dp.FirstWeekday) + 7) % 7
+}
+
+// dayAt returns the day of the shown month at (x, y) relative to the picker, or
+// false if there is no day cell there.
+func (dp *DatePicker) dayAt(x, y int) (time.Time, bool) {
+	week, column := y-2, x/3
+	if week < 0 || week >= 6 || x < 0 || x >= datePickerWidth || x%3 == 2 {
+		return time.Time{}, false
+	}
+	day := week*7 + column - dp.firstColumn() + 1
+	if day < 1 || day > daysIn(dp.Value) {
+		return time.Time{}, false
+	}
+	return dp.Value.AddDate(0, 0, day-dp.Value.Day()), true
+}
+
+// HandleInput implements InputHandler: Left/Right move by a day, Up/Down by a week,
+// PageUp/PageDown by a month and Home/End to the first and last day of the month.
+func (dp *DatePicker) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !dp.IsActive {
+		return false, false, false
+	}
+	switch ev.Key {
+	case KeyLeft: // Previous day
+		dp.AddDays(-1)
+	case KeyRight: // Next day
+		dp.AddDays(1)
+	case KeyUp: // Previous week
+		dp.AddDays(-7)
+	case KeyDown: // Next week
+		dp.AddDays(7)
+	case KeyPageUp: // Previous month
+		dp.AddMonths(-1)
+	case KeyPageDown: // Next month
+		dp.AddMonths(1)
+	case KeyHome: // First day of the month
+		dp.MonthStart()
+	case KeyEnd: // Last day of the month
+		dp.MonthEnd()
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface (never needs cursor)
+func (dp *DatePicker) NeedsCursor() bool {
+	return false
+}
+
+// GetCursorPosition implements CursorManager interface
+func (dp *DatePicker) GetCursorPosition() (int, int, bool) {
+	return 0, 0, false
+}
+
+// Bounds implements Bounded.
+func (dp *DatePicker) Bounds() (int, int, int, int) {
+	return dp.X, dp.Y, datePickerWidth, datePickerHeight
+}
+
+// SetPosition implements Positioner.
+func (dp *DatePicker) SetPosition(x, y int) {
+	dp.X, dp.Y = x, y
+}
+
+// SetEnabled implements Enabler.
+func (dp *DatePicker) SetEnabled(enabled bool) {
+	dp.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (dp *DatePicker) IsEnabled() bool {
+	return !dp.Disabled
+}
+
+// SetVisible implements Hider.
+func (dp *DatePicker) SetVisible(visible bool) {
+	dp.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (dp *DatePicker) IsVisible() bool {
+	return !dp.Hidden
+}
+
+// Render draws the month title, the weekday headers and the day grid, with the
+// selected day highlighted and today underlined.
+func (dp *DatePicker) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + dp.X
+	absY := winY + dp.Y
+
+	renderColor := themeColor(dp.Color, dp.theme().Accent)
+	headerColor := dp.HeaderColor
+	selectedColor := dp.theme().Selection
+	if dp.Disabled {
+		renderColor, headerColor, selectedColor = dp.disabledColor(), dp.disabledColor(), dp.disabledColor()
+	} else if dp.IsActive {
+		selectedColor = themeColor(dp.ActiveColor, dp.theme().Active())
+	}
+
+	// Month title, centered over the grid
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(headerColor)
+	buffer.WriteString(alignText(dp.Value.Format("January 2006"), datePickerWidth, AlignCenter))
+	buffer.WriteString(colors.Reset)
+
+	// Weekday headers, starting at FirstWeekday
+	headers := make([]string, 7)
+	for i := range headers {
+		headers[i] = time.Weekday((int(dp.FirstWeekday) + i) % 7).String()[:2]
+	}
+	buffer.WriteString(MoveCursorCmd(absY+1, absX))
+	buffer.WriteString(headerColor + strings.Join(headers, " ") + colors.Reset)
+
+	// Day grid: six weeks, so the picker keeps its size from month to month
+	today := dateOf(time.Now().In(dp.Value.Location()))
+	days := daysIn(dp.Value)
+	day := 1 - dp.firstColumn()
+	for week := 0; week < 6; week++ {
+		buffer.WriteString(MoveCursorCmd(absY+2+week, absX))
+		buffer.WriteString(renderColor)
+		for column := 0; column < 7; column++ {
+			if column > 0 {
+				buffer.WriteString(" ")
+			}
+			if day < 1 || day > days {
+				buffer.WriteString("  ")
+				day++
+				continue
+			}
+			cell := fmt.Sprintf("%2d", day)
+			date := dp.Value.AddDate(0, 0, day-dp.Value.Day())
+			switch {
+			case day == dp.Value.Day():
+				buffer.WriteString(colors.Reset + selectedColor + cell + colors.Reset + renderColor)
+			case date.Equal(today):
+				buffer.WriteString(colors.Underline + cell + colors.Reset + renderColor)
+			default:
+				buffer.WriteString(cell)
+			}
+			day++
+		}
+		buffer.WriteString(colors.Reset)
+	}
+}
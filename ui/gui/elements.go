@@ -2,7 +2,13 @@ package gui
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 	"window-go/colors"
 )
 
@@ -17,19 +23,107 @@ type ZIndexer interface {
 	GetZIndex() int // Returns the z-index value of the element
 }
 
+// Bounded is implemented by elements that can report the area they occupy,
+// relative to the window content area. The window uses it for content scrolling.
+type Bounded interface {
+	Bounds() (x, y, width, height int)
+}
+
+// Enabler is implemented by elements that can be disabled. Disabled elements are
+// drawn with disabledColor() and skipped by the window's focus traversal.
+type Enabler interface {
+	SetEnabled(enabled bool)
+	IsEnabled() bool
+}
+
+// Hider is implemented by elements that can be hidden. Hidden elements are not
+// rendered and are skipped by the window's focus traversal.
+type Hider interface {
+	SetVisible(visible bool)
+	IsVisible() bool
+}
+
+// Positioner is implemented by elements that can be moved, e.g. by a layout
+// (see VBox, HBox and Grid).
+type Positioner interface {
+	SetPosition(x, y int) // Position relative to the window content area
+}
+
+// Sizer is implemented by elements whose size can be set, e.g. by constraints
+// (see Window.SetConstraints), in the terms Bounds reports it.
+type Sizer interface {
+	SetSize(width, height int)
+}
+
+// isVisible reports whether element is not hidden.
+func isVisible(element UIElement) bool {
+	if h, ok := element.(Hider); ok {
+		return h.IsVisible()
+	}
+	return true
+}
+
+// disabledColor() is the color used to draw disabled elements.
+var DisabledColor = colors.Gray
+
+// enableGroup tracks the elements a group (segment, radio group, ...) disabled, so
+// re-enabling the group restores only those. Elements that were disabled on their
+// own before the group was disabled stay disabled.
+type enableGroup struct {
+	disabled        bool
+	disabledByGroup []Enabler
+}
+
+// set enables or disables every member that implements Enabler.
+func (g *enableGroup) set(enabled bool, members []UIElement) {
+	if enabled != g.disabled {
+		return // Already in the requested state
+	}
+	g.disabled = !enabled
+
+	if !enabled {
+		g.disabledByGroup = nil
+		for _, member := range members {
+			if e, ok := member.(Enabler); ok && e.IsEnabled() {
+				e.SetEnabled(false)
+				g.disabledByGroup = append(g.disabledByGroup, e)
+			}
+		}
+		return
+	}
+
+	for _, e := range g.disabledByGroup {
+		e.SetEnabled(true)
+	}
+	g.disabledByGroup = nil
+}
+
 // --- Basic UI Elements ---
 
 // Label represents a simple text element.
 type Label struct {
-	Text  string
-	Color string
-	X, Y  int // Position relative to window content area
+	Text          string
+	Color         string
+	X, Y          int       // Position relative to window content area
+	GradientStart string    // Hex color of the first character, for gradient text (see GradientColor)
+	GradientEnd   string    // Hex color of the last character
+	Align         Alignment // Alignment of each line within the label's width
+	WrapWidth     int       // Width lines wrap at and are aligned in (0 = the rest of the content width)
+	themed
 }
 
 func NewLabel(text string, x, y int, color string) *Label {
 	return &Label{Text: text, X: x, Y: y, Color: color}
 }
 
+// GradientColor colors the label's text character by character, from the hex
+// color start to end. It returns the label to allow chaining after NewLabel.
+func (l *Label) GradientColor(start, end string) *Label {
+	l.GradientStart = start
+	l.GradientEnd = end
+	return l
+}
+
 func (l *Label) Render(buffer *strings.Builder, winX, winY int, contentWidth int) {
 	// Calculate absolute position for the start of the label
 	absX := winX + l.X
@@ -37,45 +131,65 @@ func (l *Label) Render(buffer *strings.Builder, winX, winY int, contentWidth int
 
 	// Calculate the maximum width available for this label within the content area
 	maxWidth := contentWidth - l.X
+	if l.WrapWidth > 0 && l.WrapWidth < maxWidth {
+		maxWidth = l.WrapWidth
+	}
 	if maxWidth < 1 {
 		maxWidth = 1 // Need at least 1 character width to render anything
 	}
+	padLines := l.Align != AlignLeft || l.WrapWidth > 0 // Fill each line to maxWidth
 
 	text := l.Text
 	lineIndex := 0
 
-	buffer.WriteString(l.Color) // Set color before rendering lines
+	color := themeColor(l.Color, l.theme().Content)
+	buffer.WriteString(color) // Set color before rendering lines
+
+	// Gradient text: one color per character of Text, advanced across wrapped lines
+	var gradient []string
+	if l.GradientStart != "" && l.GradientEnd != "" {
+		gradient = colors.GenerateGradient(l.GradientStart, l.GradientEnd, utf8.RuneCountInString(l.Text))
+	}
+	gradientPos := 0
 
 	for len(text) > 0 {
 		currentLineY := absY + lineIndex
 		buffer.WriteString(MoveCursorCmd(currentLineY, absX))
 
 		var lineText string
-		if len(text) <= maxWidth {
-			// Remaining text fits on one line
-			lineText = text
-			text = "" // No more text left
-		} else {
-			// Text needs wrapping
-			wrapIndex := -1
-			// Try to find a space to wrap at within maxWidth
-			possibleWrapPoint := text[:maxWidth]
-			wrapIndex = strings.LastIndex(possibleWrapPoint, " ")
-
-			if wrapIndex != -1 {
-				// Found a space, wrap there
-				lineText = text[:wrapIndex]
-				text = strings.TrimPrefix(text[wrapIndex:], " ") // Remove the space and continue
-			} else {
-				// No space found, force break at maxWidth
-				lineText = text[:maxWidth]
-				text = text[maxWidth:]
+		var droppedSpace bool
+		lineText, text, droppedSpace = wrapLine(text, maxWidth)
+
+		// Spaces in the label's color position the line within maxWidth
+		padding, leftPadding := 0, 0
+		if padLines {
+			padding = maxWidth - DisplayWidth(lineText)
+			switch l.Align {
+			case AlignCenter:
+				leftPadding = padding / 2
+			case AlignRight:
+				leftPadding = padding
 			}
 		}
+		buffer.WriteString(strings.Repeat(" ", leftPadding))
 
-		buffer.WriteString(lineText)
-		// Clear the rest of the line within the max width if needed (optional, depends on desired look)
-		// buffer.WriteString(strings.Repeat(" ", maxWidth-len(lineText)))
+		if gradient != nil {
+			for _, r := range lineText {
+				if gradientPos < len(gradient) {
+					buffer.WriteString(gradient[gradientPos])
+				}
+				buffer.WriteRune(r)
+				gradientPos++
+			}
+			if droppedSpace {
+				gradientPos++ // The space wrapped away still takes a gradient step
+			}
+		} else {
+			buffer.WriteString(lineText)
+		}
+		if padding > leftPadding {
+			buffer.WriteString(color + strings.Repeat(" ", padding-leftPadding))
+		}
 
 		lineIndex++ // Move to the next line for subsequent text
 	}
@@ -83,6 +197,64 @@ func (l *Label) Render(buffer *strings.Builder, winX, winY int, contentWidth int
 	buffer.WriteString(colors.Reset) // Reset color after rendering all lines
 }
 
+// wrapLine splits off the first line of text that fits in maxWidth columns, breaking
+// at the last space that fits (which is dropped) or, without one, at the last whole
+// character that fits. It returns the line, the remaining text, and whether a space
+// was dropped.
+func wrapLine(text string, maxWidth int) (string, string, bool) {
+	if DisplayWidth(text) <= maxWidth {
+		return text, "", false // Remaining text fits on one line
+	}
+
+	used := 0
+	cut := 0        // Byte index after the last character that fits
+	lastSpace := -1 // Byte index of the last space that fits
+	for i, r := range text {
+		w := DisplayWidth(string(r))
+		if used+w > maxWidth {
+			break
+		}
+		if r == ' ' {
+			lastSpace = i
+		}
+		used += w
+		cut = i + utf8.RuneLen(r)
+	}
+	// A space right after the last character that fits is also a fine break
+	if cut < len(text) && text[cut] == ' ' {
+		lastSpace = cut
+	}
+
+	if lastSpace != -1 {
+		return text[:lastSpace], text[lastSpace+1:], true
+	}
+	if cut == 0 {
+		// Not even one character fits (a wide character in a 1-column label): take it anyway
+		_, size := utf8.DecodeRuneInString(text)
+		cut = size
+	}
+	return text[:cut], text[cut:], false
+}
+
+// Bounds implements Bounded. Labels with a WrapWidth report the rows their text
+// wraps onto; others a single row, as their wrapping depends on the content width
+// they are rendered with.
+func (l *Label) Bounds() (int, int, int, int) {
+	if l.WrapWidth > 0 {
+		rows := 1
+		for _, rest, _ := wrapLine(l.Text, l.WrapWidth); rest != ""; rows++ {
+			_, rest, _ = wrapLine(rest, l.WrapWidth)
+		}
+		return l.X, l.Y, l.WrapWidth, rows
+	}
+	return l.X, l.Y, DisplayWidth(l.Text), 1
+}
+
+// SetPosition implements Positioner.
+func (l *Label) SetPosition(x, y int) {
+	l.X, l.Y = x, y
+}
+
 // Button represents a clickable button element.
 type Button struct {
 	Text           string
@@ -91,8 +263,17 @@ type Button struct {
 	HighlightColor string // Color when focused but not active
 	X, Y           int    // Position relative to window content area
 	Width          int
+	Align          Alignment   // Position of the text within Width (NewButton centers it)
+	Padding        int         // Blank cells kept at each end of Width
+	ShowBrackets   bool        // Draws "[" and "]" around the button (NewButton turns it on); off for flat buttons
+	Mnemonic       rune        // Optional accelerator, underlined in the text: Alt+key, or the key alone when no text field has focus, activates the button
 	Action         func() bool // Function to call when activated. Returns true to stop interaction loop.
+	ActionName     string      // Name Action is registered under with RegisterAction, for layouts
 	IsActive       bool        // State for rendering
+	Disabled       bool        // Disabled buttons are dimmed and skipped by focus traversal
+	Hidden         bool        // Hidden buttons are not rendered and are skipped by focus traversal
+	confirms       bool        // Action only opens a WithConfirm dialog, so it runs without leaving raw mode
+	themed
 }
 
 func NewButton(text string, x, y, width int, color, activeColor string, action func() bool) *Button {
@@ -104,6 +285,8 @@ func NewButton(text string, x, y, width int, color, activeColor string, action f
 		Color:          color,
 		ActiveColor:    activeColor,
 		HighlightColor: activeColor, // Default to activeColor if not specified
+		Align:          AlignCenter,
+		ShowBrackets:   true,
 		Action:         action,
 		IsActive:       false,
 	}
@@ -118,20 +301,38 @@ func (b *Button) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absY := winY + b.Y
 	buffer.WriteString(MoveCursorCmd(absY, absX))
 
-	renderColor := b.Color
-	if b.IsActive {
-		renderColor = b.ActiveColor
+	renderColor := themeColor(b.Color, b.theme().Accent)
+	if b.Disabled {
+		renderColor = b.disabledColor()
+	} else if b.IsActive {
+		renderColor = themeColor(b.ActiveColor, b.theme().Active())
 		buffer.WriteString(ReverseVideo()) // Indicate active state visually
 	} else if b.IsActive && b.HighlightColor != "" && b.HighlightColor != b.Color {
 		renderColor = b.HighlightColor
 	}
 	buffer.WriteString(renderColor)
 
-	// Basic button rendering (text centered within width)
-	padding := (b.Width - len(b.Text)) / 2
-	leftPad := strings.Repeat(" ", padding)
-	rightPad := strings.Repeat(" ", b.Width-len(b.Text)-padding)
-	buffer.WriteString(fmt.Sprintf("[%s%s%s]", leftPad, b.Text, rightPad))
+	// Text aligned within Width less the padding, measured in display columns
+	padding := b.Padding
+	if padding*2 > b.Width {
+		padding = b.Width / 2
+	}
+	label := strings.Repeat(" ", padding) + alignText(b.Text, b.Width-2*padding, b.Align) + strings.Repeat(" ", padding)
+	if b.Mnemonic != 0 {
+		// Underline the first occurrence of the mnemonic, then restore the button's style
+		restore := renderColor
+		if b.IsActive && !b.Disabled {
+			restore = ReverseVideo() + renderColor
+		}
+		if i := strings.IndexFunc(label, func(r rune) bool { return unicode.ToLower(r) == unicode.ToLower(b.Mnemonic) }); i >= 0 {
+			_, size := utf8.DecodeRuneInString(label[i:])
+			label = label[:i] + colors.Underline + label[i:i+size] + colors.Reset + restore + label[i+size:]
+		}
+	}
+	if b.ShowBrackets {
+		label = "[" + label + "]"
+	}
+	buffer.WriteString(label)
 
 	buffer.WriteString(colors.Reset) // Reset color and video attributes
 }
@@ -145,32 +346,199 @@ func (b *Button) GetCursorPosition() (int, int, bool) {
 	return 0, 0, false
 }
 
+// Bounds implements Bounded (the width includes the surrounding brackets, if shown).
+func (b *Button) Bounds() (int, int, int, int) {
+	if !b.ShowBrackets {
+		return b.X, b.Y, b.Width, 1
+	}
+	return b.X, b.Y, b.Width + 2, 1
+}
+
+// SetPosition implements Positioner.
+func (b *Button) SetPosition(x, y int) {
+	b.X, b.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (b *Button) SetSize(width, height int) {
+	if b.ShowBrackets {
+		width -= 2 // Bounds includes the brackets
+	}
+	b.Width = width
+}
+
+// SetEnabled implements Enabler.
+func (b *Button) SetEnabled(enabled bool) {
+	b.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (b *Button) IsEnabled() bool {
+	return !b.Disabled
+}
+
+// SetVisible implements Hider.
+func (b *Button) SetVisible(visible bool) {
+	b.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (b *Button) IsVisible() bool {
+	return !b.Hidden
+}
+
+// WithConfirm wraps the button's Action so that activating the button first shows
+// a modal Yes/No dialog in w, in the colors of its theme. The original action only
+// runs if the user picks "Yes", as if the button had been pressed; either way the
+// dialog is removed and focus returns to the button.
+// It returns the button to allow chaining after NewButton.
+func (b *Button) WithConfirm(w *Window, title, message string) *Button {
+	action := b.Action
+	b.confirms = true
+	b.Action = func() bool {
+		// Size the dialog to the window's content area
+		width := w.Width - 4
+		if width > 50 {
+			width = 50
+		}
+		if width < 20 {
+			width = 20
+		}
+		x := (w.Width - 2 - width) / 2
+		if x < 0 {
+			x = 0
+		}
+
+		var confirm *Prompt
+		closeConfirm := func() {
+			confirm.SetActive(false)
+			w.RemoveElement(confirm)
+			if index := w.focusableIndex(b); index != -1 {
+				w.setFocus(index)
+			}
+		}
+
+		theme := w.theme()
+		buttons := []*PromptButton{
+			NewPromptButton("Yes", theme.Accent, theme.Active(), func() bool {
+				closeConfirm()
+				w.pendingAction = action // Run by the input loop, like a button press
+				return false
+			}),
+			NewPromptButton("No", theme.Accent, theme.Active(), func() bool {
+				closeConfirm()
+				return false
+			}),
+		}
+		confirm = NewDialogPrompt(title, message, x, 1, width, theme.Background, theme.Border, theme.Title, theme.Content, buttons)
+		confirm.SelectedIdx = 1 // Default to "No" so a stray Enter is harmless
+
+		w.AddElement(confirm)
+		if index := w.focusableIndex(confirm); index != -1 {
+			w.setFocus(index)
+		}
+		return false
+	}
+	return b
+}
+
+// InputMode restricts the characters a TextBox accepts from the keyboard.
+type InputMode int
+
+const (
+	InputAny     InputMode = iota // Any printable character
+	InputNumeric                  // Digits, plus a leading minus sign
+	InputAlpha                    // Letters only
+)
+
+// runeClass groups runes for word-wise cursor movement: whitespace (0), word
+// characters such as letters, digits and '_' (1), and punctuation or symbols (2).
+// A run of punctuation counts as a word of its own, as in most editors.
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+		return 1
+	}
+	return 2
+}
+
+// wordLeft returns the rune index of the start of the word before pos (Ctrl+Left):
+// whitespace before pos is skipped, then the run of runes of the same class.
+func wordLeft(runes []rune, pos int) int {
+	for pos > 0 && runeClass(runes[pos-1]) == 0 {
+		pos--
+	}
+	if pos > 0 {
+		class := runeClass(runes[pos-1])
+		for pos > 0 && runeClass(runes[pos-1]) == class {
+			pos--
+		}
+	}
+	return pos
+}
+
+// wordRight returns the rune index of the start of the word after pos (Ctrl+Right):
+// the run of runes of the class at pos is skipped, then the whitespace after it.
+func wordRight(runes []rune, pos int) int {
+	if pos < len(runes) {
+		class := runeClass(runes[pos])
+		for pos < len(runes) && class != 0 && runeClass(runes[pos]) == class {
+			pos++
+		}
+	}
+	for pos < len(runes) && runeClass(runes[pos]) == 0 {
+		pos++
+	}
+	return pos
+}
+
 // TextBox represents an editable text input field.
 type TextBox struct {
-	Text        string
-	Color       string
-	ActiveColor string // Color when selected/active
-	X, Y        int    // Position relative to window content area
-	Width       int
-	IsActive    bool // State for rendering/input handling
-	CursorPos   int  // Position of the cursor within the text
-	IsPristine  bool // Flag to track if default text is present and untouched
-	cursorAbsX  int  // Absolute X position of cursor (set during Render)
-	cursorAbsY  int  // Absolute Y position of cursor (set during Render)
+	Text             string
+	Color            string
+	ActiveColor      string // Color when selected/active
+	X, Y             int    // Position relative to window content area
+	Width            int
+	IsActive         bool               // State for rendering/input handling
+	Disabled         bool               // Disabled textboxes are dimmed and skipped by focus traversal
+	Hidden           bool               // Hidden textboxes are not rendered and are skipped by focus traversal
+	ReadOnly         bool               // Read-only textboxes keep cursor movement but ignore typing and deleting
+	CursorPos        int                // Position of the cursor within the text, as a byte offset into Text
+	IsPristine       bool               // Flag to track if default text is present and untouched
+	CursorShape      CursorShape        // Cursor shape while the textbox is active (CursorDefault = the window's, see Window.SetCursorShape)
+	Placeholder      string             // Hint text shown (dimmed) while the textbox is empty
+	PlaceholderColor string             // Color for the placeholder text
+	Validator        func(string) error // Optional check run after each edit (see Validate)
+	ErrorColor       string             // Color used while the text fails validation
+	ErrorLabel       *Label             // Optional label that shows the validation error message
+	validationErr    error              // Result of the last validation
+	InputMode        InputMode          // Restricts which characters can be typed
+	AllowedRunes     func(rune) bool    // Optional filter for typed characters; overrides InputMode
+	ClampRange       bool               // Clamp numeric text to [Min, Max] when the box loses focus
+	Min, Max         int                // Range used when ClampRange is set
+	killBuffer       string             // Text removed by the last KillToEnd/KillLine, for Yank
+	overwrite        bool               // Typing replaces the character under the cursor (toggled with Insert)
+	cursorAbsX       int                // Absolute X position of cursor (set during Render)
+	cursorAbsY       int                // Absolute Y position of cursor (set during Render)
+	themed
 }
 
 // NewTextBox creates a new TextBox instance.
 func NewTextBox(initialText string, x, y, width int, color, activeColor string) *TextBox {
 	tb := &TextBox{
-		Text:        initialText,
-		X:           x,
-		Y:           y,
-		Width:       width,
-		Color:       color,
-		ActiveColor: activeColor,
-		IsActive:    false,
-		CursorPos:   len(initialText), // Cursor at the end initially
-		IsPristine:  true,             // Initially contains default text
+		Text:             initialText,
+		X:                x,
+		Y:                y,
+		Width:            width,
+		Color:            color,
+		ActiveColor:      activeColor,
+		IsActive:         false,
+		CursorPos:        len(initialText), // Cursor at the end initially
+		IsPristine:       true,             // Initially contains default text
+		PlaceholderColor: colors.Gray,
+		ErrorColor:       colors.Red,
 	}
 	// Clamp initial cursor position
 	if tb.CursorPos > len(tb.Text) {
@@ -179,6 +547,278 @@ func NewTextBox(initialText string, x, y, width int, color, activeColor string)
 	return tb
 }
 
+// handleEditKey applies an editing key (a printable character, Backspace, Delete,
+// Insert, Left, Right, Home, End, Ctrl+Left/Right or Ctrl+W/Ctrl+Backspace) to the textbox. It reports whether the text or cursor changed;
+// other keys are left to the caller. Typed text may be any UTF-8 sequence; the
+// cursor always moves by whole characters.
+func (tb *TextBox) handleEditKey(ev InputEvent) bool {
+	switch {
+	case ev.Key == KeyRune && ev.Mod == 0:
+		return tb.insertText(ev.Text, tb.overwrite)
+	case ev.IsCtrl('w'), ev.Key == KeyBackspace && ev.Mod == ModCtrl: // Ctrl+W or Ctrl+Backspace - Delete the previous word
+		return tb.DeleteWordBackward()
+	case ev.Key == KeyLeft && ev.Mod == ModCtrl: // Ctrl+Left
+		return tb.MoveWordLeft()
+	case ev.Key == KeyRight && ev.Mod == ModCtrl: // Ctrl+Right
+		return tb.MoveWordRight()
+	case ev.Mod != 0:
+		return false
+	case ev.Key == KeyBackspace: // Backspace (DEL)
+		if tb.CursorPos > 0 && !tb.ReadOnly {
+			_, size := utf8.DecodeLastRuneInString(tb.Text[:tb.CursorPos])
+			tb.Text = tb.Text[:tb.CursorPos-size] + tb.Text[tb.CursorPos:]
+			tb.CursorPos -= size
+			tb.IsPristine = false // Edited
+			tb.Validate()
+			return true
+		}
+	case ev.Key == KeyLeft: // Left Arrow
+		if tb.CursorPos > 0 {
+			_, size := utf8.DecodeLastRuneInString(tb.Text[:tb.CursorPos])
+			tb.CursorPos -= size
+			tb.IsPristine = false // Interacted
+			return true
+		}
+	case ev.Key == KeyRight: // Right Arrow
+		if tb.CursorPos < len(tb.Text) {
+			_, size := utf8.DecodeRuneInString(tb.Text[tb.CursorPos:])
+			tb.CursorPos += size
+			tb.IsPristine = false // Interacted
+			return true
+		}
+	case ev.Key == KeyHome: // Home - Jump to the start of the text
+		return tb.MoveToStart()
+	case ev.Key == KeyEnd: // End - Jump to the end of the text
+		return tb.MoveToEnd()
+	case ev.Key == KeyDelete: // Delete key
+		return tb.DeleteForward()
+	case ev.Key == KeyInsert: // Insert key - Toggle overwrite mode
+		tb.ToggleOverwrite()
+		return true
+	}
+	return false
+}
+
+// handleReadlineKey applies an Emacs/readline-style control key to the textbox:
+// Ctrl+A (start), Ctrl+E (end), Ctrl+K (kill to end), Ctrl+U (kill line),
+// Ctrl+D (delete forward) or Ctrl+Y (yank). It reports whether key was one of
+// them. WindowActions only passes keys here when Window.ReadlineKeys is set.
+func (tb *TextBox) handleReadlineKey(ev InputEvent) bool {
+	if ev.Key != KeyRune || ev.Mod != ModCtrl {
+		return false
+	}
+	switch ev.Rune {
+	case 'a': // Ctrl+A
+		tb.MoveToStart()
+	case 'e': // Ctrl+E
+		tb.MoveToEnd()
+	case 'k': // Ctrl+K
+		tb.KillToEnd()
+	case 'u': // Ctrl+U
+		tb.KillLine()
+	case 'd': // Ctrl+D
+		tb.DeleteForward()
+	case 'y': // Ctrl+Y
+		tb.Yank()
+	default:
+		return false
+	}
+	return true
+}
+
+// MoveToStart moves the cursor to the start of the text (Home, Ctrl+A).
+// It reports whether the cursor moved.
+func (tb *TextBox) MoveToStart() bool {
+	if tb.CursorPos <= 0 {
+		return false
+	}
+	tb.CursorPos = 0
+	tb.IsPristine = false // Interacted
+	return true
+}
+
+// MoveToEnd moves the cursor to the end of the text (End, Ctrl+E).
+// It reports whether the cursor moved.
+func (tb *TextBox) MoveToEnd() bool {
+	if tb.CursorPos >= len(tb.Text) {
+		return false
+	}
+	tb.CursorPos = len(tb.Text)
+	tb.IsPristine = false // Interacted
+	return true
+}
+
+// DeleteForward deletes the character at the cursor (Delete, Ctrl+D).
+// It reports whether anything was deleted.
+func (tb *TextBox) DeleteForward() bool {
+	if tb.CursorPos >= len(tb.Text) || tb.ReadOnly {
+		return false
+	}
+	_, size := utf8.DecodeRuneInString(tb.Text[tb.CursorPos:])
+	tb.Text = tb.Text[:tb.CursorPos] + tb.Text[tb.CursorPos+size:]
+	tb.IsPristine = false // Edited
+	tb.Validate()
+	return true
+}
+
+// KillToEnd deletes the text from the cursor to the end (Ctrl+K) and keeps it for
+// Yank. It returns the deleted text.
+func (tb *TextBox) KillToEnd() string {
+	if tb.CursorPos >= len(tb.Text) || tb.ReadOnly {
+		return ""
+	}
+	killed := tb.Text[tb.CursorPos:]
+	tb.Text = tb.Text[:tb.CursorPos]
+	tb.killBuffer = killed
+	tb.IsPristine = false // Edited
+	tb.Validate()
+	return killed
+}
+
+// KillLine deletes the whole text (Ctrl+U) and keeps it for Yank. It returns the
+// deleted text.
+func (tb *TextBox) KillLine() string {
+	if tb.Text == "" || tb.ReadOnly {
+		return ""
+	}
+	killed := tb.Text
+	tb.Text = ""
+	tb.CursorPos = 0
+	tb.killBuffer = killed
+	tb.IsPristine = false // Edited
+	tb.Validate()
+	return killed
+}
+
+// KilledText returns the text removed by the last KillToEnd or KillLine.
+func (tb *TextBox) KilledText() string {
+	return tb.killBuffer
+}
+
+// Yank inserts the killed text at the cursor (Ctrl+Y). It reports whether
+// anything was inserted.
+func (tb *TextBox) Yank() bool {
+	return tb.killBuffer != "" && tb.insertText(tb.killBuffer, false)
+}
+
+// ToggleOverwrite switches between inserting typed characters and overwriting the
+// ones under the cursor (Insert). The cursor is a block while overwriting.
+func (tb *TextBox) ToggleOverwrite() {
+	tb.overwrite = !tb.overwrite
+}
+
+// IsOverwrite reports whether typing overwrites the characters under the cursor.
+func (tb *TextBox) IsOverwrite() bool {
+	return tb.overwrite
+}
+
+// cursorRune returns the text as runes and the cursor's index among them.
+func (tb *TextBox) cursorRune() ([]rune, int) {
+	if tb.CursorPos > len(tb.Text) {
+		tb.CursorPos = len(tb.Text)
+	}
+	return []rune(tb.Text), utf8.RuneCountInString(tb.Text[:tb.CursorPos])
+}
+
+// MoveWordLeft moves the cursor to the start of the previous word (Ctrl+Left).
+// It reports whether the cursor moved.
+func (tb *TextBox) MoveWordLeft() bool {
+	runes, pos := tb.cursorRune()
+	if pos == 0 {
+		return false
+	}
+	tb.CursorPos = len(string(runes[:wordLeft(runes, pos)]))
+	tb.IsPristine = false // Interacted
+	return true
+}
+
+// MoveWordRight moves the cursor to the start of the next word (Ctrl+Right).
+// It reports whether the cursor moved.
+func (tb *TextBox) MoveWordRight() bool {
+	runes, pos := tb.cursorRune()
+	if pos == len(runes) {
+		return false
+	}
+	tb.CursorPos = len(string(runes[:wordRight(runes, pos)]))
+	tb.IsPristine = false // Interacted
+	return true
+}
+
+// DeleteWordBackward deletes the word before the cursor, and the whitespace
+// between it and the cursor (Ctrl+W, Ctrl+Backspace). It reports whether
+// anything was deleted.
+func (tb *TextBox) DeleteWordBackward() bool {
+	runes, pos := tb.cursorRune()
+	if pos == 0 || tb.ReadOnly {
+		return false
+	}
+	start := wordLeft(runes, pos)
+	tb.Text = string(runes[:start]) + string(runes[pos:])
+	tb.CursorPos = len(string(runes[:start]))
+	tb.IsPristine = false // Edited
+	tb.Validate()
+	return true
+}
+
+// insertText inserts text at the cursor and validates the result once; with
+// overwrite each rune replaces the one under the cursor. Runes rejected by
+// InputMode/AllowedRunes are dropped, and a ReadOnly box takes none. It reports
+// whether anything was inserted.
+func (tb *TextBox) insertText(text string, overwrite bool) bool {
+	if tb.ReadOnly {
+		return false
+	}
+	inserted := false
+	for _, r := range text {
+		if !tb.AcceptsRune(r) {
+			// Filtered out by the box's InputMode/AllowedRunes: ignore silently,
+			// leaving the text and pristine state untouched
+			continue
+		}
+		// If it's the first keypress in a pristine box, clear it first.
+		if tb.IsPristine {
+			tb.Text = ""
+			tb.CursorPos = 0
+			tb.IsPristine = false
+		}
+		if overwrite && tb.CursorPos < len(tb.Text) {
+			// Drop the character under the cursor, which r replaces
+			_, size := utf8.DecodeRuneInString(tb.Text[tb.CursorPos:])
+			tb.Text = tb.Text[:tb.CursorPos] + tb.Text[tb.CursorPos+size:]
+		}
+		// Insert character at cursor position
+		tb.Text = tb.Text[:tb.CursorPos] + string(r) + tb.Text[tb.CursorPos:]
+		tb.CursorPos += utf8.RuneLen(r)
+		inserted = true
+	}
+	if inserted {
+		tb.Validate()
+	}
+	return inserted
+}
+
+// Paste inserts pasted text at the cursor as a single edit. Line breaks and tabs
+// become spaces, and other control characters are dropped.
+func (tb *TextBox) Paste(text string) {
+	tb.insertText(pasteLine(text), false)
+}
+
+// HandleInput implements InputHandler: typing and the editing keys, and the
+// readline keys when the window's ReadlineKeys is set. Tab and Enter are left to
+// the window, which moves focus.
+func (tb *TextBox) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !tb.IsActive {
+		return false, false, false
+	}
+	if ev.readline && tb.handleReadlineKey(ev) { // Ctrl+A/E/K/U/D/Y edit like readline (see ReadlineKeys)
+		return true, true, false
+	}
+	if tb.handleEditKey(ev) { // Typing, Backspace/Delete and Left/Right are handled by the textbox
+		return true, true, false
+	}
+	return false, false, false
+}
+
 // NeedsCursor implements CursorManager interface
 func (tb *TextBox) NeedsCursor() bool {
 	return tb.IsActive // Only show cursor when the textbox is active
@@ -192,53 +832,210 @@ func (tb *TextBox) GetCursorPosition() (int, int, bool) {
 	return tb.cursorAbsX, tb.cursorAbsY, true
 }
 
+// GetCursorShape implements CursorShaper interface
+func (tb *TextBox) GetCursorShape() CursorShape {
+	if tb.overwrite {
+		return CursorBlinkingBlock
+	}
+	return tb.CursorShape
+}
+
+// Bounds implements Bounded.
+func (tb *TextBox) Bounds() (int, int, int, int) {
+	return tb.X, tb.Y, tb.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (tb *TextBox) SetPosition(x, y int) {
+	tb.X, tb.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (tb *TextBox) SetSize(width, height int) {
+	tb.Width = width
+}
+
+// SetEnabled implements Enabler.
+func (tb *TextBox) SetEnabled(enabled bool) {
+	tb.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (tb *TextBox) IsEnabled() bool {
+	return !tb.Disabled
+}
+
+// SetVisible implements Hider.
+func (tb *TextBox) SetVisible(visible bool) {
+	tb.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (tb *TextBox) IsVisible() bool {
+	return !tb.Hidden
+}
+
+// NewNumericTextBox creates a TextBox that only accepts digits (and a leading minus sign).
+// When min <= max, the value is clamped to that range when the box loses focus;
+// pass min > max to disable clamping.
+func NewNumericTextBox(initialText string, x, y, width int, color, activeColor string, min, max int) *TextBox {
+	tb := NewTextBox(initialText, x, y, width, color, activeColor)
+	tb.InputMode = InputNumeric
+	if min <= max {
+		tb.ClampRange = true
+		tb.Min = min
+		tb.Max = max
+	}
+	return tb
+}
+
+// AcceptsRune reports whether r may be typed at the current cursor position,
+// according to AllowedRunes or, if that is nil, the InputMode.
+func (tb *TextBox) AcceptsRune(r rune) bool {
+	if tb.AllowedRunes != nil {
+		return tb.AllowedRunes(r)
+	}
+	switch tb.InputMode {
+	case InputNumeric:
+		if r == '-' {
+			if tb.IsPristine {
+				return true // Typing replaces the default text
+			}
+			// A single minus sign, at the start of the number
+			return tb.CursorPos == 0 && !strings.HasPrefix(tb.Text, "-")
+		}
+		return unicode.IsDigit(r)
+	case InputAlpha:
+		return unicode.IsLetter(r)
+	}
+	return true
+}
+
+// clampToRange clamps numeric text to [Min, Max]. Text that isn't a number is left
+// as is (a Validator can flag it). It is called when the box loses focus.
+func (tb *TextBox) clampToRange() {
+	if !tb.ClampRange || tb.Text == "" {
+		return
+	}
+	value, err := strconv.Atoi(tb.Text)
+	if err != nil {
+		return
+	}
+	if value < tb.Min {
+		value = tb.Min
+	} else if value > tb.Max {
+		value = tb.Max
+	}
+	if clamped := strconv.Itoa(value); clamped != tb.Text {
+		tb.Text = clamped
+		tb.CursorPos = len(tb.Text)
+		tb.Validate()
+	}
+}
+
+// Validate runs the Validator (if any) against the current text, stores the result
+// and updates the ErrorLabel. WindowActions calls it after each edit.
+// It returns the validation error, or nil if the text is valid.
+func (tb *TextBox) Validate() error {
+	tb.validationErr = nil
+	if tb.Validator != nil {
+		tb.validationErr = tb.Validator(tb.Text)
+	}
+
+	if tb.ErrorLabel != nil {
+		if tb.validationErr != nil {
+			tb.ErrorLabel.Text = tb.validationErr.Error()
+			tb.ErrorLabel.Color = tb.ErrorColor
+		} else {
+			tb.ErrorLabel.Text = ""
+		}
+	}
+	return tb.validationErr
+}
+
+// IsValid validates the current text and reports whether it passed.
+// Button actions can use it to block submission of an invalid form.
+func (tb *TextBox) IsValid() bool {
+	return tb.Validate() == nil
+}
+
+// ValidationError returns the error from the last validation, or nil.
+func (tb *TextBox) ValidationError() error {
+	return tb.validationErr
+}
+
+// SetActive sets the focus state of the text box. Losing focus applies its
+// numeric range (see ClampRange).
+func (tb *TextBox) SetActive(active bool) {
+	tb.IsActive = active
+	if !active {
+		tb.clampToRange()
+	}
+}
+
 // Render draws the textbox element.
 func (tb *TextBox) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + tb.X
 	absY := winY + tb.Y
 	buffer.WriteString(MoveCursorCmd(absY, absX))
 
-	renderColor := tb.Color
-	if tb.IsActive {
-		renderColor = tb.ActiveColor
+	renderColor := themeColor(tb.Color, tb.theme().Accent)
+	if tb.Disabled {
+		renderColor = tb.disabledColor()
+	} else if tb.validationErr != nil && tb.ErrorColor != "" {
+		renderColor = tb.ErrorColor
+	} else if tb.IsActive {
+		renderColor = themeColor(tb.ActiveColor, tb.theme().Active())
+		if tb.ReadOnly {
+			renderColor += colors.Dim
+		}
 	}
 	buffer.WriteString(renderColor)
 
 	// --- Text Rendering with Scrolling ---
+	// Measured in display columns, so wide characters (CJK, emoji) take two
 	textLen := len(tb.Text)
-	viewStart := 0 // Index in tb.Text that corresponds to the start of the visible area
-
-	// Adjust viewStart based on cursor position to keep cursor visible
-	if tb.CursorPos >= tb.Width {
-		viewStart = tb.CursorPos - tb.Width + 1
-	}
-	if viewStart < 0 { // Should not happen with above logic, but safety check
-		viewStart = 0
+	cursorPos := tb.CursorPos
+	if cursorPos > textLen {
+		cursorPos = textLen
 	}
-	// Ensure viewStart doesn't go beyond possible text start
-	if viewStart > textLen {
-		viewStart = textLen
+	viewStart := 0 // Byte index in tb.Text that corresponds to the start of the visible area
+
+	// Advance viewStart by whole characters until the cursor fits inside the box
+	for viewStart < cursorPos && DisplayWidth(tb.Text[viewStart:cursorPos]) >= tb.Width {
+		_, size := utf8.DecodeRuneInString(tb.Text[viewStart:])
+		viewStart += size
 	}
 
-	viewEnd := viewStart + tb.Width
-	if viewEnd > textLen {
-		viewEnd = textLen
+	// Take the characters that fit in the box's width
+	viewEnd, visibleWidth := viewStart, 0
+	for viewEnd < textLen {
+		r, size := utf8.DecodeRuneInString(tb.Text[viewEnd:])
+		if visibleWidth+runeWidth(r) > tb.Width {
+			break
+		}
+		visibleWidth += runeWidth(r)
+		viewEnd += size
 	}
 
 	// Get the visible portion of the text
-	visibleText := ""
-	if viewStart < textLen {
-		visibleText = tb.Text[viewStart:viewEnd]
+	visibleText := tb.Text[viewStart:viewEnd]
+
+	// Render the visible text and padding. An empty box shows its placeholder instead;
+	// the placeholder is never part of Text, so the cursor stays at column 0.
+	if textLen == 0 && tb.Placeholder != "" {
+		buffer.WriteString(tb.PlaceholderColor) // Drawn over renderColor, keeping its background
+		buffer.WriteString(alignText(tb.Placeholder, tb.Width, AlignLeft))
+		buffer.WriteString(renderColor)
+	} else {
+		buffer.WriteString(visibleText)
+		buffer.WriteString(strings.Repeat(" ", tb.Width-visibleWidth))
 	}
-
-	// Render the visible text and padding
-	buffer.WriteString(visibleText)
-	buffer.WriteString(strings.Repeat(" ", tb.Width-len(visibleText)))
 	// --- End Text Rendering ---
 
 	// --- Cursor Position Calculation ---
 	// Calculate cursor position relative to the *start* of the textbox's absolute position
-	cursorRenderPos := tb.CursorPos - viewStart
+	cursorRenderPos := DisplayWidth(tb.Text[viewStart:cursorPos])
 
 	// Clamp the render position to be within the visible bounds of the textbox [0, tb.Width]
 	if cursorRenderPos < 0 {
@@ -267,6 +1064,9 @@ type CheckBox struct {
 	Checked     bool   // State of the checkbox
 	X, Y        int    // Position relative to window content area
 	IsActive    bool   // State for rendering/input handling
+	Disabled    bool   // Disabled checkboxes are dimmed and skipped by focus traversal
+	Hidden      bool   // Hidden checkboxes are not rendered and are skipped by focus traversal
+	themed
 }
 
 // NewCheckBox creates a new CheckBox instance.
@@ -282,15 +1082,22 @@ func NewCheckBox(label string, x, y int, initialChecked bool, color, activeColor
 	}
 }
 
+// SetActive sets the focus state of the check box.
+func (cb *CheckBox) SetActive(active bool) {
+	cb.IsActive = active
+}
+
 // Render draws the checkbox element.
 func (cb *CheckBox) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + cb.X
 	absY := winY + cb.Y
 	buffer.WriteString(MoveCursorCmd(absY, absX))
 
-	renderColor := cb.Color
-	if cb.IsActive {
-		renderColor = cb.ActiveColor
+	renderColor := themeColor(cb.Color, cb.theme().Accent)
+	if cb.Disabled {
+		renderColor = cb.disabledColor()
+	} else if cb.IsActive {
+		renderColor = themeColor(cb.ActiveColor, cb.theme().Active())
 		buffer.WriteString(ReverseVideo()) // Indicate active state visually
 	}
 	buffer.WriteString(renderColor)
@@ -304,6 +1111,15 @@ func (cb *CheckBox) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	buffer.WriteString(colors.Reset) // Reset color and video attributes
 }
 
+// HandleInput implements InputHandler: Enter toggles the checkbox.
+func (cb *CheckBox) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !cb.IsActive || ev.Key != KeyEnter {
+		return false, false, false
+	}
+	cb.Checked = !cb.Checked // Toggle state
+	return true, true, false
+}
+
 // NeedsCursor implements CursorManager interface (never needs cursor)
 func (cb *CheckBox) NeedsCursor() bool {
 	return false
@@ -313,11 +1129,185 @@ func (cb *CheckBox) GetCursorPosition() (int, int, bool) {
 	return 0, 0, false
 }
 
-// --- Spacer ---
+// Bounds implements Bounded.
+func (cb *CheckBox) Bounds() (int, int, int, int) {
+	return cb.X, cb.Y, 4 + DisplayWidth(cb.Label), 1
+}
 
-// Spacer represents a vertical empty space.
-type Spacer struct {
-	Height int // Number of empty rows
+// SetPosition implements Positioner.
+func (cb *CheckBox) SetPosition(x, y int) {
+	cb.X, cb.Y = x, y
+}
+
+// SetEnabled implements Enabler.
+func (cb *CheckBox) SetEnabled(enabled bool) {
+	cb.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (cb *CheckBox) IsEnabled() bool {
+	return !cb.Disabled
+}
+
+// SetVisible implements Hider.
+func (cb *CheckBox) SetVisible(visible bool) {
+	cb.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (cb *CheckBox) IsVisible() bool {
+	return !cb.Hidden
+}
+
+// --- ToggleSwitch ---
+
+// ToggleStyle selects how a ToggleSwitch draws its state.
+type ToggleStyle int
+
+const (
+	ToggleBracket ToggleStyle = iota // [ ON] / [OFF]
+	ToggleKnob                       // ━● / ●━
+)
+
+// ToggleSwitch is an on/off switch with a label, toggled by Enter or Space.
+type ToggleSwitch struct {
+	Label       string
+	On          bool        // State of the switch
+	OnColor     string      // Color of the switch when on
+	OffColor    string      // Color of the switch when off
+	ActiveColor string      // Color of the label when selected/active
+	Style       ToggleStyle // How the switch is drawn
+	X, Y        int         // Position relative to window content area
+	IsActive    bool        // State for rendering/input handling
+	Disabled    bool        // Disabled switches are dimmed and skipped by focus traversal
+	Hidden      bool        // Hidden switches are not rendered and are skipped by focus traversal
+	OnToggle    func(on bool)
+	themed
+}
+
+// NewToggleSwitch creates a new ToggleSwitch instance.
+func NewToggleSwitch(label string, x, y int, initialOn bool, onColor, offColor, activeColor string) *ToggleSwitch {
+	return &ToggleSwitch{
+		Label:       label,
+		X:           x,
+		Y:           y,
+		On:          initialOn,
+		OnColor:     onColor,
+		OffColor:    offColor,
+		ActiveColor: activeColor,
+		Style:       ToggleBracket,
+		IsActive:    false,
+	}
+}
+
+// Toggle flips the switch and calls OnToggle with the new state.
+func (ts *ToggleSwitch) Toggle() {
+	ts.On = !ts.On
+	if ts.OnToggle != nil {
+		ts.OnToggle(ts.On)
+	}
+}
+
+// switchText returns the drawn switch for the current state and style.
+func (ts *ToggleSwitch) switchText() string {
+	if ts.Style == ToggleKnob {
+		if ts.On {
+			return glyphs.knobTrack + glyphs.knob
+		}
+		return glyphs.knob + glyphs.knobTrack
+	}
+	if ts.On {
+		return "[ ON]"
+	}
+	return "[OFF]"
+}
+
+// SetActive sets the focus state of the switch.
+func (ts *ToggleSwitch) SetActive(active bool) {
+	ts.IsActive = active
+}
+
+// Render draws the toggle switch element.
+func (ts *ToggleSwitch) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + ts.X
+	absY := winY + ts.Y
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+
+	offColor := themeColor(ts.OffColor, ts.theme().Content)
+	switchColor := offColor
+	if ts.On {
+		switchColor = themeColor(ts.OnColor, ts.theme().Accent)
+	}
+	labelColor := offColor
+	if ts.Disabled {
+		switchColor = ts.disabledColor()
+		labelColor = ts.disabledColor()
+	} else if ts.IsActive {
+		labelColor = themeColor(ts.ActiveColor, ts.theme().Active())
+		buffer.WriteString(ReverseVideo()) // Indicate active state visually
+	}
+
+	buffer.WriteString(switchColor)
+	buffer.WriteString(ts.switchText())
+	buffer.WriteString(labelColor)
+	buffer.WriteString(" " + ts.Label)
+
+	buffer.WriteString(colors.Reset) // Reset color and video attributes
+}
+
+// HandleInput implements InputHandler: Enter or Space flips the switch.
+func (ts *ToggleSwitch) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !ts.IsActive || !(ev.Key == KeyEnter || ev.Key == KeyRune && ev.Mod == 0 && ev.Rune == ' ') {
+		return false, false, false
+	}
+	ts.Toggle()
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface (never needs cursor)
+func (ts *ToggleSwitch) NeedsCursor() bool {
+	return false
+}
+
+func (ts *ToggleSwitch) GetCursorPosition() (int, int, bool) {
+	return 0, 0, false
+}
+
+// Bounds implements Bounded.
+func (ts *ToggleSwitch) Bounds() (int, int, int, int) {
+	return ts.X, ts.Y, DisplayWidth(ts.switchText()) + 1 + DisplayWidth(ts.Label), 1
+}
+
+// SetPosition implements Positioner.
+func (ts *ToggleSwitch) SetPosition(x, y int) {
+	ts.X, ts.Y = x, y
+}
+
+// SetEnabled implements Enabler.
+func (ts *ToggleSwitch) SetEnabled(enabled bool) {
+	ts.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (ts *ToggleSwitch) IsEnabled() bool {
+	return !ts.Disabled
+}
+
+// SetVisible implements Hider.
+func (ts *ToggleSwitch) SetVisible(visible bool) {
+	ts.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (ts *ToggleSwitch) IsVisible() bool {
+	return !ts.Hidden
+}
+
+// --- Spacer ---
+
+// Spacer represents a vertical empty space.
+type Spacer struct {
+	Height int // Number of empty rows
 	X, Y   int // Position (X is usually ignored, Y marks the top)
 }
 
@@ -343,13 +1333,24 @@ func (s *Spacer) Render(buffer *strings.Builder, winX, winY int, contentWidth in
 	// buffer.WriteString(MoveCursorCmd(absY+s.Height, winX+s.X))
 }
 
+// Bounds implements Bounded.
+func (s *Spacer) Bounds() (int, int, int, int) {
+	return s.X, s.Y, 0, s.Height
+}
+
+// SetPosition implements Positioner.
+func (s *Spacer) SetPosition(x, y int) {
+	s.X, s.Y = x, y
+}
+
 // --- Radio Buttons ---
 
 // Forward declaration for RadioButton's reference
 type RadioGroup struct {
 	Buttons       []*RadioButton
 	SelectedIndex int
-	SelectedValue string // Or int, depending on your needs
+	SelectedValue string      // Or int, depending on your needs
+	enableState   enableGroup // Tracks buttons disabled via SetEnabled
 }
 
 // RadioButton represents a single option in a radio button group.
@@ -360,8 +1361,11 @@ type RadioButton struct {
 	ActiveColor string // Color when selected/active
 	X, Y        int    // Position relative to window content area
 	IsActive    bool   // State for rendering/input handling
+	Disabled    bool   // Disabled radio buttons are dimmed and skipped by focus traversal
+	Hidden      bool   // Hidden radio buttons are not rendered and are skipped by focus traversal
 	IsSelected  bool   // State of the radio button within its group
 	Group       *RadioGroup
+	themed
 }
 
 // NewRadioGroup creates a new RadioGroup.
@@ -408,15 +1412,37 @@ func (rg *RadioGroup) Select(selectedIndex int) {
 	}
 }
 
+// SetEnabled enables or disables every button in the group at once.
+// Re-enabling leaves buttons that were disabled individually disabled.
+func (rg *RadioGroup) SetEnabled(enabled bool) {
+	members := make([]UIElement, len(rg.Buttons))
+	for i, btn := range rg.Buttons {
+		members[i] = btn
+	}
+	rg.enableState.set(enabled, members)
+}
+
+// IsEnabled reports whether the group has not been disabled with SetEnabled.
+func (rg *RadioGroup) IsEnabled() bool {
+	return !rg.enableState.disabled
+}
+
+// SetActive sets the focus state of the radio button.
+func (rb *RadioButton) SetActive(active bool) {
+	rb.IsActive = active
+}
+
 // Render draws the radio button element.
 func (rb *RadioButton) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + rb.X
 	absY := winY + rb.Y
 	buffer.WriteString(MoveCursorCmd(absY, absX))
 
-	renderColor := rb.Color
-	if rb.IsActive {
-		renderColor = rb.ActiveColor
+	renderColor := themeColor(rb.Color, rb.theme().Accent)
+	if rb.Disabled {
+		renderColor = rb.disabledColor()
+	} else if rb.IsActive {
+		renderColor = themeColor(rb.ActiveColor, rb.theme().Active())
 		buffer.WriteString(ReverseVideo()) // Indicate active state visually
 	}
 	buffer.WriteString(renderColor)
@@ -431,6 +1457,21 @@ func (rb *RadioButton) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	buffer.WriteString(colors.Reset) // Reset color and video attributes
 }
 
+// HandleInput implements InputHandler: Enter selects the radio button in its group.
+func (rb *RadioButton) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !rb.IsActive || ev.Key != KeyEnter {
+		return false, false, false
+	}
+	// Find the index of the radio button within its group
+	for i, button := range rb.Group.Buttons {
+		if button == rb {
+			rb.Group.Select(i) // Select this button in its group
+			return true, true, false
+		}
+	}
+	return true, false, false
+}
+
 // NeedsCursor implements CursorManager interface (never needs cursor)
 func (rb *RadioButton) NeedsCursor() bool {
 	return false
@@ -440,17 +1481,111 @@ func (rb *RadioButton) GetCursorPosition() (int, int, bool) {
 	return 0, 0, false
 }
 
+// Bounds implements Bounded.
+func (rb *RadioButton) Bounds() (int, int, int, int) {
+	return rb.X, rb.Y, 4 + DisplayWidth(rb.Label), 1
+}
+
+// SetPosition implements Positioner.
+func (rb *RadioButton) SetPosition(x, y int) {
+	rb.X, rb.Y = x, y
+}
+
+// SetEnabled implements Enabler.
+func (rb *RadioButton) SetEnabled(enabled bool) {
+	rb.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (rb *RadioButton) IsEnabled() bool {
+	return !rb.Disabled
+}
+
+// SetVisible implements Hider.
+func (rb *RadioButton) SetVisible(visible bool) {
+	rb.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (rb *RadioButton) IsVisible() bool {
+	return !rb.Hidden
+}
+
 // --- Progress Bar ---
 
+// Orientation selects the direction a bar is drawn in.
+type Orientation int
+
+const (
+	Horizontal Orientation = iota // Left to right along a row
+	Vertical                      // Bottom to top up a column
+)
+
+// renderVerticalBar draws a bar of width columns filling bottom-up over height rows,
+// with the percentage on an extra row above or below it. fillColor returns the color
+// for the filled row k, counting from the bottom (0) up to filledRows-1.
+func renderVerticalBar(buffer *strings.Builder, absX, absY, width, height int, percentage float64, showPercentage, percentageAbove bool, unfilledColor string, fillColor func(k, filledRows int) string) {
+	barY := absY
+	barHeight := height
+	if showPercentage {
+		barHeight-- // Reserve a row for the percentage text
+		if percentageAbove {
+			barY++
+		}
+	}
+	if barHeight < 0 {
+		barHeight = 0
+	}
+
+	filledRows := int(float64(barHeight) * percentage)
+	for row := 0; row < barHeight; row++ {
+		buffer.WriteString(MoveCursorCmd(barY+row, absX))
+		k := barHeight - 1 - row // Row index counted from the bottom
+		if k < filledRows {
+			buffer.WriteString(fillColor(k, filledRows))
+			buffer.WriteString(strings.Repeat(glyphs.fill, width))
+		} else {
+			buffer.WriteString(unfilledColor)
+			buffer.WriteString(strings.Repeat(glyphs.empty, width))
+		}
+		buffer.WriteString(colors.Reset)
+	}
+
+	if showPercentage {
+		textY := barY + barHeight
+		if percentageAbove {
+			textY = absY
+		}
+		buffer.WriteString(MoveCursorCmd(textY, absX))
+		buffer.WriteString(fmt.Sprintf("%.0f%%", percentage*100))
+	}
+	buffer.WriteString(colors.Reset)
+}
+
+// IndeterminateMotion selects how the segment of an indeterminate ProgressBar moves.
+type IndeterminateMotion int
+
+const (
+	MotionBounce IndeterminateMotion = iota // Segment moves back and forth between the ends
+	MotionWrap                              // Segment runs off the right end and re-enters on the left
+)
+
 // ProgressBar represents a visual progress indicator.
 type ProgressBar struct {
-	Value          float64 // Current value
-	MaxValue       float64 // Maximum value (represents 100%)
-	Color          string  // Color of the filled portion
-	UnfilledColor  string  // Color of the unfilled portion
-	ShowPercentage bool    // Whether to display the percentage text
-	X, Y           int     // Position relative to window content area
-	Width          int     // Total width of the bar in characters
+	Value           float64             // Current value
+	MaxValue        float64             // Maximum value (represents 100%)
+	Color           string              // Color of the filled portion
+	UnfilledColor   string              // Color of the unfilled portion
+	ShowPercentage  bool                // Whether to display the percentage text
+	X, Y            int                 // Position relative to window content area
+	Width           int                 // Total width of the bar in characters (columns per row when Vertical)
+	Height          int                 // Rows of a Vertical bar, including the percentage row
+	Orientation     Orientation         // Horizontal (default) or Vertical
+	PercentageAbove bool                // Draw a Vertical bar's percentage above it instead of below
+	Indeterminate   bool                // Draw a moving segment instead of Value (for work of unknown size)
+	Motion          IndeterminateMotion // How the indeterminate segment moves
+	SegmentWidth    int                 // Width of the indeterminate segment (0 = a quarter of the bar)
+	animFrame       int                 // Position of the indeterminate animation, advanced by Pulse
 }
 
 // NewProgressBar creates a new ProgressBar instance.
@@ -491,17 +1626,100 @@ func (pb *ProgressBar) SetValue(value float64) {
 	}
 }
 
+// Pulse advances the moving segment of an indeterminate progress bar by one cell.
+func (pb *ProgressBar) Pulse() {
+	pb.animFrame++
+}
+
+// segmentStart returns where the indeterminate segment begins on a bar of barWidth
+// cells, for the current animation frame.
+func (pb *ProgressBar) segmentStart(barWidth, segWidth int) int {
+	if pb.Motion == MotionWrap {
+		return pb.animFrame % barWidth
+	}
+	travel := barWidth - segWidth
+	if travel <= 0 {
+		return 0
+	}
+	pos := pb.animFrame % (2 * travel)
+	if pos > travel {
+		pos = 2*travel - pos // On the way back
+	}
+	return pos
+}
+
+// renderIndeterminate draws the moving segment across the whole width of the bar.
+func (pb *ProgressBar) renderIndeterminate(buffer *strings.Builder) {
+	barWidth := pb.Width
+	if barWidth <= 0 {
+		return
+	}
+	segWidth := pb.SegmentWidth
+	if segWidth <= 0 {
+		segWidth = barWidth / 4
+	}
+	if segWidth < 1 {
+		segWidth = 1
+	} else if segWidth > barWidth {
+		segWidth = barWidth
+	}
+
+	start := pb.segmentStart(barWidth, segWidth)
+	for i := 0; i < barWidth; i++ {
+		if (i-start+barWidth)%barWidth < segWidth { // Inside the segment (which may wrap)
+			buffer.WriteString(colors.Reset)
+			buffer.WriteString(pb.Color)
+			buffer.WriteString(glyphs.fill)
+		} else {
+			buffer.WriteString(colors.Reset)
+			buffer.WriteString(pb.UnfilledColor)
+			buffer.WriteString(glyphs.empty)
+		}
+	}
+}
+
+// Bounds implements Bounded.
+func (pb *ProgressBar) Bounds() (int, int, int, int) {
+	if pb.Orientation == Vertical {
+		return pb.X, pb.Y, pb.Width, pb.Height
+	}
+	return pb.X, pb.Y, pb.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (pb *ProgressBar) SetPosition(x, y int) {
+	pb.X, pb.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (pb *ProgressBar) SetSize(width, height int) {
+	pb.Width, pb.Height = width, height
+}
+
 // Render draws the progress bar element.
 func (pb *ProgressBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + pb.X
 	absY := winY + pb.Y
 	buffer.WriteString(MoveCursorCmd(absY, absX))
 
+	// Indeterminate bars ignore Value and never show a percentage
+	if pb.Indeterminate {
+		pb.renderIndeterminate(buffer)
+		buffer.WriteString(colors.Reset)
+		return
+	}
+
 	percentage := 0.0
 	if pb.MaxValue > 0 {
 		percentage = pb.Value / pb.MaxValue
 	}
 
+	if pb.Orientation == Vertical {
+		renderVerticalBar(buffer, absX, absY, pb.Width, pb.Height, percentage, pb.ShowPercentage, pb.PercentageAbove, pb.UnfilledColor,
+			func(int, int) string { return pb.Color })
+		return
+	}
+
 	// Calculate the width available for the bar itself
 	barWidth := pb.Width
 	percentageText := ""
@@ -519,12 +1737,12 @@ func (pb *ProgressBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 	// Draw the filled part
 	buffer.WriteString(pb.Color)
-	buffer.WriteString(strings.Repeat("█", filledWidth)) // Use a block character for filled part
+	buffer.WriteString(strings.Repeat(glyphs.fill, filledWidth)) // Use a block character for filled part
 
 	// Draw the empty part (set unfilled color first)
-	buffer.WriteString(colors.Reset)                    // Reset to default before unfilled color
-	buffer.WriteString(pb.UnfilledColor)                // Set color for the empty part
-	buffer.WriteString(strings.Repeat("░", emptyWidth)) // Use a lighter shade or space for empty part
+	buffer.WriteString(colors.Reset)                             // Reset to default before unfilled color
+	buffer.WriteString(pb.UnfilledColor)                         // Set color for the empty part
+	buffer.WriteString(strings.Repeat(glyphs.empty, emptyWidth)) // Use a lighter shade or space for empty part
 
 	// Draw the percentage text if enabled
 	if pb.ShowPercentage {
@@ -542,14 +1760,17 @@ func (pb *ProgressBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 // GradientProgressBar represents a visual progress indicator with a gradient fill.
 type GradientProgressBar struct {
-	Value          float64 // Current value
-	MaxValue       float64 // Maximum value (represents 100%)
-	StartColorHex  string  // Hex string for the start of the gradient (e.g., "#FF0000")
-	EndColorHex    string  // Hex string for the end of the gradient (e.g., "#00FF00")
-	UnfilledColor  string  // Color of the unfilled portion
-	ShowPercentage bool    // Whether to display the percentage text
-	X, Y           int     // Position relative to window content area
-	Width          int     // Total width of the bar in characters
+	Value           float64     // Current value
+	MaxValue        float64     // Maximum value (represents 100%)
+	StartColorHex   string      // Hex string for the start of the gradient (e.g., "#FF0000")
+	EndColorHex     string      // Hex string for the end of the gradient (e.g., "#00FF00")
+	UnfilledColor   string      // Color of the unfilled portion
+	ShowPercentage  bool        // Whether to display the percentage text
+	X, Y            int         // Position relative to window content area
+	Width           int         // Total width of the bar in characters (columns per row when Vertical)
+	Height          int         // Rows of a Vertical bar, including the percentage row
+	Orientation     Orientation // Horizontal (default) or Vertical
+	PercentageAbove bool        // Draw a Vertical bar's percentage above it instead of below
 }
 
 // NewGradientProgressBar creates a new GradientProgressBar instance.
@@ -590,6 +1811,24 @@ func (gpb *GradientProgressBar) SetValue(value float64) {
 	}
 }
 
+// Bounds implements Bounded.
+func (gpb *GradientProgressBar) Bounds() (int, int, int, int) {
+	if gpb.Orientation == Vertical {
+		return gpb.X, gpb.Y, gpb.Width, gpb.Height
+	}
+	return gpb.X, gpb.Y, gpb.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (gpb *GradientProgressBar) SetPosition(x, y int) {
+	gpb.X, gpb.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (gpb *GradientProgressBar) SetSize(width, height int) {
+	gpb.Width, gpb.Height = width, height
+}
+
 // Render draws the gradient progress bar element.
 func (gpb *GradientProgressBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + gpb.X
@@ -601,6 +1840,19 @@ func (gpb *GradientProgressBar) Render(buffer *strings.Builder, winX, winY int,
 		percentage = gpb.Value / gpb.MaxValue
 	}
 
+	if gpb.Orientation == Vertical {
+		// Gradient steps run from the bottom row (start color) up to the top filled row
+		var gradient []string
+		renderVerticalBar(buffer, absX, absY, gpb.Width, gpb.Height, percentage, gpb.ShowPercentage, gpb.PercentageAbove, gpb.UnfilledColor,
+			func(k, filledRows int) string {
+				if gradient == nil {
+					gradient = colors.GenerateGradient(gpb.StartColorHex, gpb.EndColorHex, filledRows)
+				}
+				return gradient[k]
+			})
+		return
+	}
+
 	barWidth := gpb.Width
 	percentageText := ""
 	if gpb.ShowPercentage {
@@ -619,14 +1871,14 @@ func (gpb *GradientProgressBar) Render(buffer *strings.Builder, winX, winY int,
 		gradient := colors.GenerateGradient(gpb.StartColorHex, gpb.EndColorHex, filledWidth)
 		for i := 0; i < filledWidth; i++ {
 			buffer.WriteString(gradient[i])
-			buffer.WriteString("█") // Use a block character for filled part
+			buffer.WriteString(glyphs.fill) // Use a block character for filled part
 		}
 		buffer.WriteString(colors.Reset) // Reset after gradient
 	}
 
 	// Draw the empty part
 	buffer.WriteString(gpb.UnfilledColor)
-	buffer.WriteString(strings.Repeat("░", emptyWidth)) // Use a lighter shade or space for empty part
+	buffer.WriteString(strings.Repeat(glyphs.empty, emptyWidth)) // Use a lighter shade or space for empty part
 
 	// Draw the percentage text if enabled
 	if gpb.ShowPercentage {
@@ -637,129 +1889,540 @@ func (gpb *GradientProgressBar) Render(buffer *strings.Builder, winX, winY int,
 	buffer.WriteString(colors.Reset) // Ensure color is reset at the end
 }
 
-// --- ScrollBar ---
-
-// ScrollBar represents a vertical scrollbar element.
-type ScrollBar struct {
-	X, Y        int                // Position relative to window content area (top-left of the scrollbar)
-	Height      int                // Height of the scrollbar track in characters
-	Value       int                // Current value (e.g., top visible line index), 0-based
-	MaxValue    int                // Maximum value (e.g., total lines - visible lines), 0-based
-	Color       string             // Color of the scrollbar track and thumb
-	ActiveColor string             // Color when focused/active
-	IsActive    bool               // State for rendering/input handling
-	Visible     bool               // Controls whether the scrollbar is rendered
-	ContainerID string             // Identifier for the container this scrollbar controls (for future use)
-	thumbChar   string             // Character for the thumb
-	trackChar   string             // Character for the track
-	OnScroll    func(newValue int) // Callback function when value changes via SetValue
-}
-
-// NewScrollBar creates a new ScrollBar instance.
-// Value is the initial top visible line index.
-// MaxValue is the maximum possible top visible line index (e.g., total lines - viewport height).
-func NewScrollBar(x, y, height, value, maxValue int, color, activeColor, containerID string) *ScrollBar {
-	if height < 2 {
-		height = 2 // Minimum height for track + thumb
-	}
-	if value < 0 {
-		value = 0
-	}
-	if maxValue < 0 {
-		maxValue = 0
-	}
-	if value > maxValue {
-		value = maxValue
-	}
-	return &ScrollBar{
+// --- Slider ---
+
+// Slider lets the user pick a numeric value in a range with the arrow keys.
+type Slider struct {
+	Min, Max    float64             // Range of the slider
+	Step        float64             // Amount Left/Right change the value by
+	Value       float64             // Current value
+	X, Y        int                 // Position relative to window content area
+	Width       int                 // Total width in characters, including the value text
+	Color       string              // Color of the track and thumb
+	ActiveColor string              // Color when focused
+	ShowValue   bool                // Whether to display the current value after the track
+	IsActive    bool                // State for rendering/input handling
+	Disabled    bool                // Disabled sliders are dimmed and skipped by focus traversal
+	Hidden      bool                // Hidden sliders are not rendered and are skipped by focus traversal
+	OnChange    func(value float64) // Called when the value changes
+	themed
+}
+
+// NewSlider creates a new Slider instance.
+func NewSlider(x, y, width int, min, max, step, initialValue float64, color, activeColor string, showValue bool) *Slider {
+	if max <= min {
+		max = min + 100 // Default range if invalid
+	}
+	if step <= 0 {
+		step = (max - min) / 100
+	}
+	s := &Slider{
+		Min:         min,
+		Max:         max,
+		Step:        step,
 		X:           x,
 		Y:           y,
-		Height:      height,
-		Value:       value,
-		MaxValue:    maxValue,
+		Width:       width,
 		Color:       color,
 		ActiveColor: activeColor,
+		ShowValue:   showValue,
 		IsActive:    false,
-		Visible:     false, // Start hidden by default, container will make it visible
-		ContainerID: containerID,
-		thumbChar:   "█", // Block character for thumb
-		trackChar:   "│", // Line character for track
-		OnScroll:    nil, // Initialize callback to nil
 	}
+	s.Value = s.clamp(initialValue)
+	return s
 }
 
-// SetValue updates the scrollbar's current value, clamping it, and calls the OnScroll callback.
-func (sb *ScrollBar) SetValue(value int) {
-	oldValue := sb.Value
-	newValue := value
-	if newValue < 0 {
-		newValue = 0
-	} else if newValue > sb.MaxValue {
-		newValue = sb.MaxValue
+// clamp limits value to [Min, Max] and snaps it to the nearest step.
+func (s *Slider) clamp(value float64) float64 {
+	if s.Step > 0 {
+		value = s.Min + math.Round((value-s.Min)/s.Step)*s.Step
 	}
-
-	if newValue != oldValue {
-		sb.Value = newValue
-		// Call the callback if it's set
-		if sb.OnScroll != nil {
-			sb.OnScroll(sb.Value)
-		}
+	if value < s.Min {
+		return s.Min
+	} else if value > s.Max {
+		return s.Max
 	}
+	return value
 }
 
-// Render draws the scrollbar element.
-func (sb *ScrollBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
-	// Only render if visible
-	if !sb.Visible {
-		// If not visible, we might need to clear the area it would occupy
-		// This prevents artifacts if it was previously visible.
-		absX := winX + sb.X
-		absY := winY + sb.Y
-		for i := 0; i < sb.Height; i++ {
-			buffer.WriteString(MoveCursorCmd(absY+i, absX))
-			buffer.WriteString(" ") // Overwrite with space
-		}
+// SetValue updates the slider's value, clamping it between Min and Max.
+// OnChange is called if the value changed.
+func (s *Slider) SetValue(value float64) {
+	value = s.clamp(value)
+	if value == s.Value {
 		return
 	}
+	s.Value = value
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
 
-	absX := winX + sb.X
-	absY := winY + sb.Y
+// Increment raises the value by one Step.
+func (s *Slider) Increment() {
+	s.SetValue(s.Value + s.Step)
+}
 
-	renderColor := sb.Color
-	if sb.IsActive {
-		renderColor = sb.ActiveColor
-		// Optionally add reverse video or other indicators for active state
-		// buffer.WriteString(ReverseVideo())
+// Decrement lowers the value by one Step.
+func (s *Slider) Decrement() {
+	s.SetValue(s.Value - s.Step)
+}
+
+// SetActive sets the focus state of the slider.
+func (s *Slider) SetActive(active bool) {
+	s.IsActive = active
+}
+
+// valueText formats the value with as many decimals as Step has.
+func (s *Slider) valueText() string {
+	decimals := 0
+	stepText := strconv.FormatFloat(s.Step, 'f', -1, 64)
+	if dot := strings.IndexByte(stepText, '.'); dot != -1 {
+		decimals = len(stepText) - dot - 1
 	}
-	buffer.WriteString(renderColor)
+	return strconv.FormatFloat(s.Value, 'f', decimals, 64)
+}
 
-	// Calculate thumb position
-	thumbPos := 0 // Position relative to the top of the scrollbar (0 to Height-1)
-	if sb.MaxValue > 0 {
-		// Calculate position based on value percentage
-		percentage := float64(sb.Value) / float64(sb.MaxValue)
-		thumbPos = int(percentage * float64(sb.Height-1)) // Scale to fit height (minus 1 for 0-based index)
+// HandleInput implements InputHandler: Left/Right change the value by one step
+// and Home/End jump to the minimum and maximum.
+func (s *Slider) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !s.IsActive {
+		return false, false, false
 	}
-	// Clamp thumbPos just in case
-	if thumbPos < 0 {
-		thumbPos = 0
-	} else if thumbPos >= sb.Height {
-		thumbPos = sb.Height - 1
+	switch ev.Key {
+	case KeyLeft: // Decrease by one step
+		s.Decrement()
+	case KeyRight: // Increase by one step
+		s.Increment()
+	case KeyHome: // Jump to the minimum
+		s.SetValue(s.Min)
+	case KeyEnd: // Jump to the maximum
+		s.SetValue(s.Max)
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface (never needs cursor)
+func (s *Slider) NeedsCursor() bool {
+	return false
+}
+
+// GetCursorPosition implements CursorManager interface
+func (s *Slider) GetCursorPosition() (int, int, bool) {
+	return 0, 0, false
+}
+
+// Bounds implements Bounded.
+func (s *Slider) Bounds() (int, int, int, int) {
+	return s.X, s.Y, s.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (s *Slider) SetPosition(x, y int) {
+	s.X, s.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (s *Slider) SetSize(width, height int) {
+	s.Width = width
+}
+
+// SetEnabled implements Enabler.
+func (s *Slider) SetEnabled(enabled bool) {
+	s.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (s *Slider) IsEnabled() bool {
+	return !s.Disabled
+}
+
+// SetVisible implements Hider.
+func (s *Slider) SetVisible(visible bool) {
+	s.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (s *Slider) IsVisible() bool {
+	return !s.Hidden
+}
+
+// Render draws the slider track, its thumb and optionally the value.
+func (s *Slider) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + s.X
+	absY := winY + s.Y
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+
+	renderColor := themeColor(s.Color, s.theme().Accent)
+	if s.Disabled {
+		renderColor = s.disabledColor()
+	} else if s.IsActive {
+		renderColor = themeColor(s.ActiveColor, s.theme().Active())
+	}
+
+	// Calculate the width available for the track itself
+	trackWidth := s.Width
+	valueText := ""
+	if s.ShowValue {
+		valueText = " " + s.valueText()
+		trackWidth -= len(valueText)
+	}
+	if trackWidth < 1 {
+		trackWidth = 1
+	}
+
+	// Position the thumb along the track
+	fraction := (s.Value - s.Min) / (s.Max - s.Min)
+	thumbPos := int(math.Round(fraction * float64(trackWidth-1)))
+
+	buffer.WriteString(renderColor)
+	buffer.WriteString(strings.Repeat(glyphs.trackFilled, thumbPos)) // Track left of the thumb
+	buffer.WriteString(glyphs.fill)                                  // Thumb
+	buffer.WriteString(strings.Repeat(glyphs.empty, trackWidth-thumbPos-1))
+
+	if s.ShowValue {
+		buffer.WriteString(colors.Reset)
+		buffer.WriteString(valueText)
+	}
+
+	buffer.WriteString(colors.Reset) // Ensure color is reset at the end
+}
+
+// --- Spinner ---
+
+// Frame sets for NewSpinner and NewDotSpinner.
+var (
+	LineSpinnerFrames = []string{"|", "/", "-", "\\"}
+	DotSpinnerFrames  = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+)
+
+// Spinner is an activity indicator for work of unknown length. Each Tick shows
+// the next frame; StartAuto ticks it in the background.
+type Spinner struct {
+	Frames []string // Frames drawn in turn
+	Frame  int      // Index of the current frame
+	Label  string   // Optional text after the spinner
+	X, Y   int      // Position relative to window content area
+	Color  string   // Color of the spinner frame
+	stop   chan struct{}
+	done   chan struct{}
+	themed
+}
+
+// NewSpinner creates a new Spinner using the |/-\ frames.
+func NewSpinner(label string, x, y int, color string) *Spinner {
+	return &Spinner{
+		Frames: LineSpinnerFrames,
+		Label:  label,
+		X:      x,
+		Y:      y,
+		Color:  color,
+	}
+}
+
+// NewDotSpinner creates a new Spinner using braille dot frames.
+func NewDotSpinner(label string, x, y int, color string) *Spinner {
+	s := NewSpinner(label, x, y, color)
+	s.Frames = DotSpinnerFrames
+	return s
+}
+
+// Tick advances the spinner to its next frame.
+func (s *Spinner) Tick() {
+	if len(s.Frames) > 0 {
+		s.Frame = (s.Frame + 1) % len(s.Frames)
+	}
+}
+
+// StartAuto ticks the spinner every interval until Stop is called. A background
+// goroutine times the ticks; the input loop of w (WindowActions, or Run of the
+// WindowManager w was added to beforehand) applies them and re-renders, so the
+// spinner only moves while that loop waits for input. Calling it on a running
+// spinner restarts it with the new interval.
+func (s *Spinner) StartAuto(w *Window, interval time.Duration) {
+	s.Stop()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go func(ticks chan<- *Spinner, stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				select {
+				case ticks <- s: // Ticked by the input loop
+				case <-stop:
+					return
+				}
+			}
+		}
+	}(w.spinnerTicks(), s.stop, s.done)
+}
+
+// Stop cancels StartAuto and waits for its goroutine to exit.
+// It is safe to call on a spinner that isn't running.
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+	s.done = nil
+}
+
+// Bounds implements Bounded.
+func (s *Spinner) Bounds() (int, int, int, int) {
+	width := 1
+	if s.Label != "" {
+		width += 1 + DisplayWidth(s.Label)
+	}
+	return s.X, s.Y, width, 1
+}
+
+// SetPosition implements Positioner.
+func (s *Spinner) SetPosition(x, y int) {
+	s.X, s.Y = x, y
+}
+
+// Render draws the current frame and the label.
+func (s *Spinner) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + s.X
+	absY := winY + s.Y
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+
+	frame := " "
+	if len(s.Frames) > 0 {
+		frame = s.Frames[s.Frame%len(s.Frames)]
+	}
+	buffer.WriteString(themeColor(s.Color, s.theme().Accent))
+	buffer.WriteString(frame)
+	buffer.WriteString(colors.Reset)
+
+	if s.Label != "" {
+		buffer.WriteString(" " + s.Label)
+	}
+}
+
+// --- ScrollBar ---
+
+// ScrollBar represents a vertical (or, with Orientation Horizontal, horizontal) scrollbar element.
+type ScrollBar struct {
+	X, Y        int                // Position relative to window content area (top-left of the scrollbar)
+	Height      int                // Height of the scrollbar track in characters
+	Width       int                // Width of the track when Horizontal
+	Orientation Orientation        // Vertical (the default for NewScrollBar) or Horizontal
+	Value       int                // Current value (e.g., top visible line index), 0-based
+	MaxValue    int                // Maximum value (e.g., total lines - visible lines), 0-based
+	Color       string             // Color of the scrollbar track and thumb
+	ActiveColor string             // Color when focused/active
+	IsActive    bool               // State for rendering/input handling
+	Disabled    bool               // Disabled scrollbars are dimmed and skipped by focus traversal
+	Hidden      bool               // Hidden scrollbars are not rendered and are skipped by focus traversal
+	Visible     bool               // Controls whether the scrollbar is rendered
+	ContainerID string             // Identifier for the container this scrollbar controls (for future use)
+	thumbChar   string             // Character for the thumb
+	trackChar   string             // Character for the track
+	OnScroll    func(newValue int) // Callback function when value changes via SetValue
+	themed
+}
+
+// NewScrollBar creates a new ScrollBar instance.
+// Value is the initial top visible line index.
+// MaxValue is the maximum possible top visible line index (e.g., total lines - viewport height).
+func NewScrollBar(x, y, height, value, maxValue int, color, activeColor, containerID string) *ScrollBar {
+	if height < 2 {
+		height = 2 // Minimum height for track + thumb
+	}
+	if value < 0 {
+		value = 0
+	}
+	if maxValue < 0 {
+		maxValue = 0
+	}
+	if value > maxValue {
+		value = maxValue
+	}
+	return &ScrollBar{
+		X:           x,
+		Y:           y,
+		Height:      height,
+		Value:       value,
+		MaxValue:    maxValue,
+		Color:       color,
+		ActiveColor: activeColor,
+		IsActive:    false,
+		Visible:     false, // Start hidden by default, container will make it visible
+		ContainerID: containerID,
+		Orientation: Vertical,
+		thumbChar:   "█", // Block character for thumb
+		trackChar:   "│", // Line character for track
+		OnScroll:    nil, // Initialize callback to nil
+	}
+}
+
+// NewHorizontalScrollBar creates a new ScrollBar whose track runs along a row,
+// with Value as the leftmost visible column.
+func NewHorizontalScrollBar(x, y, width, value, maxValue int, color, activeColor, containerID string) *ScrollBar {
+	if width < 2 {
+		width = 2 // Minimum width for track + thumb
+	}
+	sb := NewScrollBar(x, y, 2, value, maxValue, color, activeColor, containerID)
+	sb.Height = 1
+	sb.Width = width
+	sb.Orientation = Horizontal
+	sb.trackChar = "─" // Line character for a horizontal track
+	return sb
+}
+
+// trackLength returns the number of cells in the track for the bar's orientation.
+func (sb *ScrollBar) trackLength() int {
+	if sb.Orientation == Horizontal {
+		return sb.Width
+	}
+	return sb.Height
+}
+
+// SetValue updates the scrollbar's current value, clamping it, and calls the OnScroll callback.
+func (sb *ScrollBar) SetValue(value int) {
+	oldValue := sb.Value
+	newValue := value
+	if newValue < 0 {
+		newValue = 0
+	} else if newValue > sb.MaxValue {
+		newValue = sb.MaxValue
+	}
+
+	if newValue != oldValue {
+		sb.Value = newValue
+		// Call the callback if it's set
+		if sb.OnScroll != nil {
+			sb.OnScroll(sb.Value)
+		}
+	}
+}
+
+// SetActive sets the focus state of the scrollbar.
+func (sb *ScrollBar) SetActive(active bool) {
+	sb.IsActive = active
+}
+
+// Render draws the scrollbar element.
+func (sb *ScrollBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	// Only render if visible
+	if !sb.Visible {
+		// If not visible, we might need to clear the area it would occupy
+		// This prevents artifacts if it was previously visible.
+		absX := winX + sb.X
+		absY := winY + sb.Y
+		if sb.Orientation == Horizontal {
+			buffer.WriteString(MoveCursorCmd(absY, absX))
+			buffer.WriteString(strings.Repeat(" ", sb.Width)) // Overwrite with spaces
+			return
+		}
+		for i := 0; i < sb.Height; i++ {
+			buffer.WriteString(MoveCursorCmd(absY+i, absX))
+			buffer.WriteString(" ") // Overwrite with space
+		}
+		return
+	}
+
+	absX := winX + sb.X
+	absY := winY + sb.Y
+
+	renderColor := themeColor(sb.Color, sb.theme().Accent)
+	if sb.Disabled {
+		renderColor = sb.disabledColor()
+	} else if sb.IsActive {
+		renderColor = themeColor(sb.ActiveColor, sb.theme().Active())
+		// Optionally add reverse video or other indicators for active state
+		// buffer.WriteString(ReverseVideo())
+	}
+	buffer.WriteString(renderColor)
+
+	// Calculate thumb position
+	length := sb.trackLength()
+	thumbPos := 0 // Position relative to the top (or left) of the scrollbar (0 to length-1)
+	if sb.MaxValue > 0 {
+		// Calculate position based on value percentage
+		percentage := float64(sb.Value) / float64(sb.MaxValue)
+		thumbPos = int(percentage * float64(length-1)) // Scale to fit the track (minus 1 for 0-based index)
+	}
+	// Clamp thumbPos just in case
+	if thumbPos < 0 {
+		thumbPos = 0
+	} else if thumbPos >= length {
+		thumbPos = length - 1
+	}
+
+	// ASCII mode swaps in plain characters for the thumb and track
+	thumbChar, trackChar := sb.thumbChar, sb.trackChar
+	if asciiMode {
+		thumbChar, trackChar = glyphs.scrollThumb, glyphs.scrollTrack
+		if sb.Orientation == Horizontal {
+			trackChar = glyphs.scrollTrackH
+		}
+	}
+
+	// A horizontal track is drawn along a single row
+	if sb.Orientation == Horizontal {
+		buffer.WriteString(MoveCursorCmd(absY, absX))
+		for i := 0; i < length; i++ {
+			if i == thumbPos {
+				buffer.WriteString(thumbChar) // Draw thumb
+			} else {
+				buffer.WriteString(trackChar) // Draw track
+			}
+		}
+		buffer.WriteString(colors.Reset) // Reset color
+		return
 	}
 
 	// Draw the scrollbar track and thumb
 	for i := 0; i < sb.Height; i++ {
 		buffer.WriteString(MoveCursorCmd(absY+i, absX))
 		if i == thumbPos {
-			buffer.WriteString(sb.thumbChar) // Draw thumb
+			buffer.WriteString(thumbChar) // Draw thumb
 		} else {
-			buffer.WriteString(sb.trackChar) // Draw track
+			buffer.WriteString(trackChar) // Draw track
 		}
 	}
 
 	buffer.WriteString(colors.Reset) // Reset color
 }
 
+// HandleInput implements InputHandler: while the scrollbar is visible, the arrow
+// keys along its orientation scroll by one, PageUp/PageDown by the track length
+// and Home/End to either end.
+func (sb *ScrollBar) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !sb.IsActive || !sb.Visible {
+		return false, false, false
+	}
+	horizontal := sb.Orientation == Horizontal
+	switch {
+	case ev.Key == KeyHome: // Scroll to the start
+		sb.SetValue(0)
+	case ev.Key == KeyEnd: // Scroll to the end
+		sb.SetValue(sb.MaxValue)
+	case ev.Key == KeyPageUp: // Scroll back by the track length
+		sb.SetValue(sb.Value - sb.trackLength())
+	case ev.Key == KeyPageDown: // Scroll forward by the track length
+		sb.SetValue(sb.Value + sb.trackLength())
+	case ev.Key == KeyLeft && horizontal, ev.Key == KeyUp && !horizontal: // Scroll left/up
+		sb.SetValue(sb.Value - 1)
+	case ev.Key == KeyRight && horizontal, ev.Key == KeyDown && !horizontal: // Scroll right/down
+		sb.SetValue(sb.Value + 1)
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
 // NeedsCursor implements CursorManager interface (never needs cursor)
 func (sb *ScrollBar) NeedsCursor() bool {
 	return false
@@ -769,28 +2432,99 @@ func (sb *ScrollBar) GetCursorPosition() (int, int, bool) {
 	return 0, 0, false
 }
 
+// Bounds implements Bounded.
+func (sb *ScrollBar) Bounds() (int, int, int, int) {
+	if sb.Orientation == Horizontal {
+		return sb.X, sb.Y, sb.Width, 1
+	}
+	return sb.X, sb.Y, 1, sb.Height
+}
+
+// SetPosition implements Positioner.
+func (sb *ScrollBar) SetPosition(x, y int) {
+	sb.X, sb.Y = x, y
+}
+
+// SetSize implements Sizer; only the length along its orientation changes.
+func (sb *ScrollBar) SetSize(width, height int) {
+	if sb.Orientation == Horizontal {
+		sb.Width = width
+	} else {
+		sb.Height = height
+	}
+}
+
+// SetEnabled implements Enabler.
+func (sb *ScrollBar) SetEnabled(enabled bool) {
+	sb.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (sb *ScrollBar) IsEnabled() bool {
+	return !sb.Disabled
+}
+
+// SetVisible implements Hider.
+func (sb *ScrollBar) SetVisible(visible bool) {
+	sb.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (sb *ScrollBar) IsVisible() bool {
+	return !sb.Hidden
+}
+
 // --- Container ---
 
+// RowRenderer returns the text of row index of a Container, at most width cells
+// wide; it may contain colors. selected is true for the highlighted row of a
+// focused container.
+type RowRenderer func(index int, selected bool, width int) string
+
 // Container represents a scrollable area for content.
 type Container struct {
 	X, Y                  int
 	Width, Height         int
-	Content               []string // Initially support only string content
+	Content               []string // Rows of text (see ItemRenderer for other items)
+	RowColors             []string // Optional color per row of Content (by index into the unfiltered content); empty entries use Color
+	Icons                 []string // Optional icon per row (indexed like RowColors), drawn in a leading column; empty entries leave it blank
 	scrollBar             *ScrollBar
+	hScrollBar            *ScrollBar // Horizontal scrollbar, shown on the bottom row when lines are wider than the view
 	needsScroll           bool
+	needsHScroll          bool
 	totalContentHeight    int
-	IsActive              bool                    // Tracks if the container itself has focus
-	HighlightedIndex      int                     // Index of the currently highlighted line in Content
-	SelectedIndex         int                     // Index of the actually selected item (via Enter)
-	Color                 string                  // Default background/text color (use window's if empty)
-	ActiveColor           string                  // Border/indicator color when active (unused for now, but good practice)
-	SelectionColor        string                  // Background/text color for the highlighted line
-	OnItemSelected        func(selectedIndex int) // Callback when an item is selected via Enter
-	cursorAbsX            int                     // Used for cursor position tracking
-	cursorAbsY            int                     // Used for cursor position tracking
-	lastConfirmedIndex    int                     // Index of the last item confirmed with Enter
-	hasConfirmedSelection bool                    // Whether any item has been confirmed with Enter
+	maxLineWidth          int                         // Width of the longest line, in runes
+	IsActive              bool                        // Tracks if the container itself has focus
+	Disabled              bool                        // Disabled containers are dimmed and skipped by focus traversal
+	Hidden                bool                        // Hidden containers are not rendered and are skipped by focus traversal
+	HighlightedIndex      int                         // Index of the currently highlighted line in Content
+	SelectedIndex         int                         // Index of the actually selected item (via Enter)
+	Color                 string                      // Default background/text color (use window's if empty)
+	ActiveColor           string                      // Border color when active (and bordered)
+	SelectionColor        string                      // Background/text color for the highlighted line
+	ZebraColors           [2]string                   // Optional backgrounds of even and odd rows, for readability; the highlight wins
+	Border                bool                        // Draw a frame around the rows; the content area is inset by one cell
+	BorderStyle           string                      // Box style of the frame from BoxTypes ("" = "single")
+	BorderColor           string                      // Frame color (the theme's border color if empty)
+	Title                 string                      // Shown centered in the top border when bordered
+	borderApplied         bool                        // Border setting the scroll state was last computed for
+	OnItemSelected        func(selectedIndex int)     // Callback when an item is selected via Enter
+	OnScroll              func(offset, maxOffset int) // Called when the scroll offset or its maximum changes (scrolling, SetContent, Filter)
+	ItemRenderer          RowRenderer                 // Optional: draws the rows (see SetItemCount) instead of Content
+	itemCount             int                         // Number of rows drawn by ItemRenderer
+	ScrollbarFocusable    bool                        // Opt-in: make the internal scrollbar a separate tab stop
+	cursorAbsX            int                         // Used for cursor position tracking
+	cursorAbsY            int                         // Used for cursor position tracking
+	lastConfirmedIndex    int                         // Index of the last item confirmed with Enter
+	hasConfirmedSelection bool                        // Whether any item has been confirmed with Enter
+	FilterQuery           string                      // Current filter; only rows containing it (case-insensitive) are shown
+	unfiltered            []string                    // Full content while a filter is applied
+	filterMap             []int                       // Original index of each shown row (nil when unfiltered)
+	filtering             bool                        // True while the filter input line captures keystrokes
+	reportedOffset        int                         // Scroll offset last passed to OnScroll
+	reportedMaxOffset     int                         // Maximum scroll offset last passed to OnScroll
 	// TODO: Add BgColor, ContentColor properties if needed explicitly for container
+	themed
 }
 
 // NewContainer creates a new Container instance.
@@ -813,6 +2547,7 @@ func NewContainer(x, y, width, height int, content []string) *Container {
 	// Initial MaxValue is 0, updateScrollState will fix it
 	scrollBar := NewScrollBar(sbX, sbY, sbHeight, 0, 0, colors.Gray, colors.BoldWhite, containerID)
 	scrollBar.Visible = false // Start hidden
+	hScrollBar := NewHorizontalScrollBar(0, height-1, width, 0, 0, colors.Gray, colors.BoldWhite, containerID+"_h")
 
 	c := &Container{
 		X:                     x,
@@ -820,8 +2555,10 @@ func NewContainer(x, y, width, height int, content []string) *Container {
 		Width:                 width,
 		Height:                height,
 		Content:               content,
+		unfiltered:            content,
 		scrollBar:             scrollBar, // Assign the created scrollbar
-		needsScroll:           false,     // Will be set by updateScrollState
+		hScrollBar:            hScrollBar,
+		needsScroll:           false, // Will be set by updateScrollState
 		IsActive:              false,
 		HighlightedIndex:      0,
 		SelectedIndex:         -1, // No actual selection initially, only highlighting
@@ -832,13 +2569,14 @@ func NewContainer(x, y, width, height int, content []string) *Container {
 		lastConfirmedIndex:    -1,  // No confirmed selection initially
 		hasConfirmedSelection: false,
 	}
+	scrollBar.OnScroll = func(int) { c.notifyScroll() } // Report scrolling through OnScroll
 
 	c.updateScrollState() // Calculate initial scroll state and visibility
 
 	// Ensure initial highlight is valid
-	if c.HighlightedIndex >= len(c.Content) && len(c.Content) > 0 {
-		c.HighlightedIndex = len(c.Content) - 1
-	} else if len(c.Content) == 0 {
+	if c.HighlightedIndex >= c.totalContentHeight && c.totalContentHeight > 0 {
+		c.HighlightedIndex = c.totalContentHeight - 1
+	} else if c.totalContentHeight == 0 {
 		c.HighlightedIndex = -1 // No highlight possible
 	}
 	// Ensure initial highlight is visible after state update
@@ -850,9 +2588,9 @@ func NewContainer(x, y, width, height int, content []string) *Container {
 // SelectHighlightedItem selects the currently highlighted item.
 // This should be called when the user presses Enter on a highlighted item.
 func (c *Container) SelectHighlightedItem() {
-	if c.HighlightedIndex >= 0 && c.HighlightedIndex < len(c.Content) {
-		c.SelectedIndex = c.HighlightedIndex
-		c.lastConfirmedIndex = c.HighlightedIndex
+	if c.HighlightedIndex >= 0 && c.HighlightedIndex < c.rowCount() {
+		c.SelectedIndex = c.originalIndex(c.HighlightedIndex) // Report indices into the unfiltered content
+		c.lastConfirmedIndex = c.SelectedIndex
 		c.hasConfirmedSelection = true
 
 		// Call the existing OnItemSelected callback if available
@@ -876,8 +2614,12 @@ func (c *Container) GetLastConfirmedItem() (int, string, bool) {
 		return -1, "", false
 	}
 
-	if c.lastConfirmedIndex >= 0 && c.lastConfirmedIndex < len(c.Content) {
-		return c.lastConfirmedIndex, c.Content[c.lastConfirmedIndex], true
+	if c.ItemRenderer != nil && c.lastConfirmedIndex >= 0 && c.lastConfirmedIndex < c.itemCount {
+		return c.lastConfirmedIndex, "", true // Items drawn by ItemRenderer have no text
+	}
+	content := c.allContent()
+	if c.lastConfirmedIndex >= 0 && c.lastConfirmedIndex < len(content) {
+		return c.lastConfirmedIndex, content[c.lastConfirmedIndex], true
 	}
 
 	// The content has changed and the last selection is no longer valid
@@ -895,8 +2637,31 @@ func (c *Container) ClearConfirmedSelection() {
 // updateScrollState calculates content height and determines if scrolling is needed.
 // It updates the internal scrollbar's visibility and properties.
 func (c *Container) updateScrollState() {
-	c.totalContentHeight = len(c.Content)
-	c.needsScroll = c.totalContentHeight > c.Height
+	c.borderApplied = c.Border
+	c.totalContentHeight = c.rowCount()
+	c.maxLineWidth = 0
+	if c.ItemRenderer == nil { // Rendered items are drawn to fit the width
+		for _, line := range c.Content {
+			if width := DisplayWidth(line); width > c.maxLineWidth {
+				c.maxLineWidth = width
+			}
+		}
+	}
+
+	// Each scrollbar takes space from the other direction, so decide them together
+	// (the filter line, when shown, takes the bottom row)
+	height := c.innerHeight() - c.filterRows()
+	c.needsScroll = c.totalContentHeight > height
+	textWidth := c.innerWidth() - c.iconColumnWidth()
+	if c.needsScroll {
+		textWidth--
+	}
+	c.needsHScroll = c.maxLineWidth > textWidth
+	if c.needsHScroll && !c.needsScroll && c.totalContentHeight > c.visibleHeight() {
+		c.needsScroll = true
+		textWidth--
+	}
+	viewHeight := c.visibleHeight()
 
 	// Adjust HighlightedIndex if it's now out of bounds
 	if c.HighlightedIndex >= c.totalContentHeight {
@@ -909,8 +2674,10 @@ func (c *Container) updateScrollState() {
 
 	// Update scrollbar visibility and MaxValue
 	c.scrollBar.Visible = c.needsScroll // Set visibility based on need
+	c.scrollBar.X = c.innerWidth() - 1  // Last column of the content area, just inside any border
+	c.scrollBar.Height = viewHeight
 	if c.needsScroll {
-		sbMaxValue := c.totalContentHeight - c.Height
+		sbMaxValue := c.totalContentHeight - viewHeight
 		if sbMaxValue < 0 {
 			sbMaxValue = 0
 		}
@@ -921,12 +2688,40 @@ func (c *Container) updateScrollState() {
 		c.scrollBar.MaxValue = 0
 		c.scrollBar.SetValue(0) // Reset scroll value if not needed
 	}
+	c.notifyScroll() // The maximum may have changed without the offset
+
+	// Same for the horizontal scrollbar, whose value is the first visible column
+	c.hScrollBar.Visible = c.needsHScroll
+	c.hScrollBar.X = c.iconColumnWidth() // Under the text, not the icons
+	c.hScrollBar.Y = height - 1
+	c.hScrollBar.Width = textWidth
+	if c.needsHScroll {
+		c.hScrollBar.MaxValue = c.maxLineWidth - textWidth
+		c.hScrollBar.SetValue(c.hScrollBar.Value)
+	} else {
+		c.hScrollBar.MaxValue = 0
+		c.hScrollBar.SetValue(0)
+	}
 
 	// Ensure highlight is visible after potential scrollbar update
 	c.ensureHighlightVisible()
 }
 
+// notifyScroll calls OnScroll if the scroll offset or its maximum changed since
+// it was last called.
+func (c *Container) notifyScroll() {
+	offset, maxOffset := c.scrollBar.Value, c.scrollBar.MaxValue
+	if offset == c.reportedOffset && maxOffset == c.reportedMaxOffset {
+		return
+	}
+	c.reportedOffset, c.reportedMaxOffset = offset, maxOffset
+	if c.OnScroll != nil {
+		c.OnScroll(offset, maxOffset)
+	}
+}
+
 // SetContent updates the container's content and recalculates scrolling state.
+// The maximum scroll offset may change, and with it the offset, calling OnScroll.
 func (c *Container) SetContent(content []string) {
 	// Check if the last confirmed selection is still valid with the new content
 	if c.hasConfirmedSelection && (c.lastConfirmedIndex < 0 || c.lastConfirmedIndex >= len(content)) {
@@ -934,10 +2729,162 @@ func (c *Container) SetContent(content []string) {
 		c.SelectedIndex = -1
 	}
 
-	c.Content = content
+	c.unfiltered = content
+	c.applyFilter()       // Keep showing only the rows matching the current filter
 	c.updateScrollState() // This will also adjust HighlightedIndex if needed
 }
 
+// SetItemCount sets the number of rows drawn by ItemRenderer and recalculates the
+// scrolling state, keeping the highlight on an existing row. Like SetContent, it
+// may call OnScroll.
+func (c *Container) SetItemCount(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if c.hasConfirmedSelection && c.lastConfirmedIndex >= n {
+		c.hasConfirmedSelection = false // The selection is no longer valid
+		c.SelectedIndex = -1
+	}
+	c.itemCount = n
+	c.updateScrollState()
+	if c.HighlightedIndex == -1 && n > 0 {
+		c.HighlightedIndex = 0
+		c.ensureHighlightVisible()
+	}
+}
+
+// iconColumnWidth returns the width of the icon column: the widest icon plus a
+// space, or 0 without icons.
+func (c *Container) iconColumnWidth() int {
+	width := 0
+	for _, icon := range c.Icons {
+		if w := DisplayWidth(icon); w > width {
+			width = w
+		}
+	}
+	if width == 0 {
+		return 0
+	}
+	return width + 1
+}
+
+// rowCount returns the number of rows shown: the item count with an ItemRenderer,
+// otherwise the (filtered) Content rows.
+func (c *Container) rowCount() int {
+	if c.ItemRenderer != nil {
+		return c.itemCount
+	}
+	return len(c.Content)
+}
+
+// Filter shows only the rows containing query (case-insensitive); an empty query
+// shows every row again. The full content is kept, and GetHighlightedIndex,
+// SelectedIndex and OnItemSelected keep reporting indices into it.
+// The highlighted row stays highlighted if it still matches. Rows drawn by an
+// ItemRenderer have no text to match and are not filtered.
+func (c *Container) Filter(query string) {
+	if c.ItemRenderer != nil {
+		return
+	}
+	if c.filterMap == nil {
+		c.unfiltered = c.Content // Content may have been assigned directly
+	}
+	highlighted := c.GetHighlightedIndex()
+	c.FilterQuery = query
+	c.applyFilter()
+
+	c.HighlightedIndex = c.viewIndex(highlighted)
+	if c.HighlightedIndex == -1 && len(c.Content) > 0 {
+		c.HighlightedIndex = 0
+	}
+	c.updateScrollState()
+}
+
+// applyFilter rebuilds the shown rows from the full content and FilterQuery.
+func (c *Container) applyFilter() {
+	if c.FilterQuery == "" {
+		c.Content = c.unfiltered
+		c.filterMap = nil
+		return
+	}
+	query := strings.ToLower(c.FilterQuery)
+	c.Content = make([]string, 0)
+	c.filterMap = make([]int, 0)
+	for i, line := range c.unfiltered {
+		if strings.Contains(strings.ToLower(StripANSI(line)), query) { // Match the visible text only
+			c.Content = append(c.Content, line)
+			c.filterMap = append(c.filterMap, i)
+		}
+	}
+}
+
+// allContent returns the full, unfiltered content.
+func (c *Container) allContent() []string {
+	if c.filterMap == nil {
+		return c.Content
+	}
+	return c.unfiltered
+}
+
+// originalIndex maps an index into the shown rows to an index into the full content.
+func (c *Container) originalIndex(viewIndex int) int {
+	if c.filterMap == nil || viewIndex < 0 {
+		return viewIndex
+	}
+	if viewIndex >= len(c.filterMap) {
+		return -1
+	}
+	return c.filterMap[viewIndex]
+}
+
+// viewIndex maps an index into the full content to an index into the shown rows,
+// or -1 if that row is filtered out.
+func (c *Container) viewIndex(originalIndex int) int {
+	if c.filterMap == nil {
+		return originalIndex
+	}
+	for i, index := range c.filterMap {
+		if index == originalIndex {
+			return i
+		}
+	}
+	return -1
+}
+
+// StartFilterInput opens the filter line, so typed keys edit FilterQuery until
+// Enter (keep the filter) or Escape (clear it). WindowActions binds it to '/'.
+func (c *Container) StartFilterInput() {
+	if c.ItemRenderer != nil {
+		return // Rendered items can't be filtered
+	}
+	c.filtering = true
+	c.updateScrollState() // The filter line takes a row
+}
+
+// StopFilterInput closes the filter line; clear also removes the filter.
+func (c *Container) StopFilterInput(clear bool) {
+	c.filtering = false
+	if clear {
+		c.Filter("")
+	} else {
+		c.updateScrollState()
+	}
+}
+
+// IsFilterInputActive reports whether the filter line is capturing keystrokes.
+func (c *Container) IsFilterInputActive() bool {
+	return c.filtering
+}
+
+// filterRows returns the number of rows used by the filter line (shown while
+// typing a filter or while one is applied).
+func (c *Container) filterRows() int {
+	if c.filtering || c.FilterQuery != "" {
+		return 1
+	}
+	return 0
+}
+
 // GetScrollOffset returns the current vertical scroll offset (top visible line index).
 // Returns 0 if scrolling is not needed or the scrollbar doesn't exist.
 func (c *Container) GetScrollOffset() int {
@@ -955,15 +2902,99 @@ func (c *Container) ensureHighlightVisible() {
 	}
 
 	scrollOffset := c.scrollBar.Value
-	bottomVisibleIndex := scrollOffset + c.Height - 1
+	viewHeight := c.visibleHeight()
+	bottomVisibleIndex := scrollOffset + viewHeight - 1
 
 	if c.HighlightedIndex < scrollOffset {
 		// Highlight is above the view, scroll up
 		c.scrollBar.SetValue(c.HighlightedIndex)
 	} else if c.HighlightedIndex > bottomVisibleIndex {
 		// Highlight is below the view, scroll down
-		c.scrollBar.SetValue(c.HighlightedIndex - c.Height + 1)
+		c.scrollBar.SetValue(c.HighlightedIndex - viewHeight + 1)
+	}
+}
+
+// inset returns how far the content area is inset from the container's edges:
+// one cell for the border, if any.
+func (c *Container) inset() int {
+	if c.Border {
+		return 1
 	}
+	return 0
+}
+
+// innerWidth returns the width of the content area, inside any border.
+func (c *Container) innerWidth() int {
+	if width := c.Width - 2*c.inset(); width > 1 {
+		return width
+	}
+	return 1
+}
+
+// innerHeight returns the height of the content area, inside any border.
+func (c *Container) innerHeight() int {
+	if height := c.Height - 2*c.inset(); height > 1 {
+		return height
+	}
+	return 1
+}
+
+// renderBorder draws the container's frame with its top-left corner at (absX, absY),
+// in ActiveColor while the container has focus.
+func (c *Container) renderBorder(buffer *strings.Builder, absX, absY int) {
+	box := frameBox(c.BorderStyle) // Falls back to "single"
+	color := themeColor(c.BorderColor, c.theme().Border)
+	if c.Disabled {
+		color = c.disabledColor()
+	} else if c.IsActive && c.ActiveColor != "" {
+		color = c.ActiveColor
+	}
+	innerWidth := c.Width - 2
+	if innerWidth < 0 {
+		innerWidth = 0
+	}
+
+	top := strings.Repeat(box.Horizontal, innerWidth)
+	if title := truncateToWidth(c.Title, innerWidth-2); title != "" {
+		title = " " + title + " "
+		left := (innerWidth - DisplayWidth(title)) / 2
+		top = strings.Repeat(box.Horizontal, left) + title + strings.Repeat(box.Horizontal, innerWidth-left-DisplayWidth(title))
+	}
+	buffer.WriteString(color)
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(box.TopLeft + top + box.TopRight)
+	for row := 1; row < c.Height-1; row++ {
+		buffer.WriteString(MoveCursorCmd(absY+row, absX))
+		buffer.WriteString(box.Vertical)
+		buffer.WriteString(MoveCursorCmd(absY+row, absX+c.Width-1))
+		buffer.WriteString(box.Vertical)
+	}
+	buffer.WriteString(MoveCursorCmd(absY+c.Height-1, absX))
+	buffer.WriteString(box.BottomLeft + strings.Repeat(box.Horizontal, innerWidth) + box.BottomRight)
+	buffer.WriteString(colors.Reset)
+}
+
+// visibleHeight returns the number of content lines shown, excluding the
+// horizontal scrollbar row when it is needed.
+func (c *Container) visibleHeight() int {
+	height := c.innerHeight() - c.filterRows()
+	if c.needsHScroll {
+		height--
+	}
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// ScrollLeft scrolls the content one column left, if it is wider than the view.
+func (c *Container) ScrollLeft() {
+	c.hScrollBar.SetValue(c.hScrollBar.Value - 1)
+}
+
+// ScrollRight scrolls the content one column right, if it is wider than the view.
+func (c *Container) ScrollRight() {
+	c.hScrollBar.SetValue(c.hScrollBar.Value + 1)
 }
 
 // ensureSelectionVisible kept for backward compatibility, now delegates to ensureHighlightVisible
@@ -987,6 +3018,22 @@ func (c *Container) HighlightPrevious() {
 	}
 }
 
+// HighlightFirst highlights the first item (Home).
+func (c *Container) HighlightFirst() {
+	if c.totalContentHeight > 0 {
+		c.HighlightedIndex = 0
+		c.ensureHighlightVisible()
+	}
+}
+
+// HighlightLast highlights the last item (End).
+func (c *Container) HighlightLast() {
+	if c.totalContentHeight > 0 {
+		c.HighlightedIndex = c.totalContentHeight - 1
+		c.ensureHighlightVisible()
+	}
+}
+
 // SelectNext kept for backward compatibility, now delegates to HighlightNext
 func (c *Container) SelectNext() {
 	c.HighlightNext()
@@ -997,35 +3044,161 @@ func (c *Container) SelectPrevious() {
 	c.HighlightPrevious()
 }
 
-// GetSelectedIndex returns the index of the actually selected item (via Enter).
-// Returns -1 if no item is selected.
+// GetSelectedIndex returns the index of the actually selected item (via Enter),
+// in the unfiltered content. Returns -1 if no item is selected.
 func (c *Container) GetSelectedIndex() int {
 	return c.SelectedIndex
 }
 
-// GetHighlightedIndex returns the index of the currently highlighted item.
-// Returns -1 if no item is highlighted (e.g., empty container).
+// GetHighlightedIndex returns the index of the currently highlighted item, in the
+// unfiltered content. Returns -1 if no item is highlighted (e.g., empty container).
 func (c *Container) GetHighlightedIndex() int {
-	return c.HighlightedIndex
+	return c.originalIndex(c.HighlightedIndex)
+}
+
+// HandleInput implements InputHandler: Up/Down, Home/End and PageUp/PageDown move
+// the highlight, Left/Right scroll wide content, Enter calls OnItemSelected and
+// / opens the filter line, which takes the keys while open.
+func (c *Container) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !c.IsActive {
+		return false, false, false
+	}
+	if c.IsFilterInputActive() {
+		return c.handleFilterInput(ev)
+	}
+	switch ev.Key {
+	case KeyUp: // Select previous item
+		c.SelectPrevious()
+	case KeyDown: // Select next item
+		c.SelectNext()
+	case KeyLeft: // Scroll wide content left
+		c.ScrollLeft()
+	case KeyRight: // Scroll wide content right
+		c.ScrollRight()
+	case KeyHome: // Highlight the first item
+		c.HighlightFirst()
+	case KeyEnd: // Highlight the last item
+		c.HighlightLast()
+	case KeyPageUp: // Move the highlight up one page
+		for i := 0; i < c.visibleHeight(); i++ {
+			c.HighlightPrevious()
+		}
+	case KeyPageDown: // Move the highlight down one page
+		for i := 0; i < c.visibleHeight(); i++ {
+			c.HighlightNext()
+		}
+	case KeyEnter: // Trigger the item selection callback
+		if c.OnItemSelected != nil && c.SelectedIndex >= 0 {
+			c.OnItemSelected(c.SelectedIndex)
+		}
+	case KeyRune:
+		if ev.Mod != 0 || ev.Rune != '/' {
+			return false, false, false
+		}
+		c.StartFilterInput() // Open the filter line
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// handleFilterInput handles a key while the filter line is open: typing edits the
+// query, Enter keeps the filter and Escape clears it. Other keys are ignored,
+// except Ctrl+C, which is left to the window.
+func (c *Container) handleFilterInput(ev InputEvent) (bool, bool, bool) {
+	switch {
+	case ev.Key == KeyEnter: // Keep the filter and return to the list
+		c.StopFilterInput(false)
+	case ev.Key == KeyEscape: // Clear the filter
+		c.StopFilterInput(true)
+	case ev.Key == KeyBackspace: // Remove the last character of the query
+		if runes := []rune(c.FilterQuery); len(runes) > 0 {
+			c.Filter(string(runes[:len(runes)-1]))
+		}
+	case ev.Key == KeyRune && ev.Mod == 0:
+		c.Filter(c.FilterQuery + ev.Text)
+	case ev.IsCtrl('c'): // Quit
+		return false, false, false
+	default:
+		return true, false, false
+	}
+	return true, true, false
 }
 
 // NeedsCursor implements CursorManager interface
 func (c *Container) NeedsCursor() bool {
-	return false // Containers never need a cursor visible
+	return c.IsActive && c.filtering // Only the filter line shows a cursor
 }
 
 // GetCursorPosition implements CursorManager interface
 func (c *Container) GetCursorPosition() (int, int, bool) {
+	if c.NeedsCursor() {
+		// End of the filter query on the bottom row
+		return c.cursorAbsX + 1 + len([]rune(c.FilterQuery)), c.cursorAbsY + c.innerHeight() - 1, true
+	}
 	return c.cursorAbsX, c.cursorAbsY, false // Position known but not needed
 }
 
+// Bounds implements Bounded.
+func (c *Container) Bounds() (int, int, int, int) {
+	return c.X, c.Y, c.Width, c.Height
+}
+
+// SetPosition implements Positioner.
+func (c *Container) SetPosition(x, y int) {
+	c.X, c.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (c *Container) SetSize(width, height int) {
+	c.Width, c.Height = width, height
+	c.updateScrollState()
+}
+
+// SetEnabled implements Enabler.
+func (c *Container) SetEnabled(enabled bool) {
+	c.Disabled = !enabled
+	c.scrollBar.Disabled = !enabled // The internal scrollbars follow the container
+	c.hScrollBar.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (c *Container) IsEnabled() bool {
+	return !c.Disabled
+}
+
+// SetVisible implements Hider.
+func (c *Container) SetVisible(visible bool) {
+	c.Hidden = !visible
+	c.scrollBar.Hidden = !visible
+	c.hScrollBar.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (c *Container) IsVisible() bool {
+	return !c.Hidden
+}
+
+// SetActive sets the focus state of the container.
+func (c *Container) SetActive(active bool) {
+	c.IsActive = active
+}
+
 // Render draws the container and its visible content.
 func (c *Container) Render(buffer *strings.Builder, winX, winY int, _ int) {
-	absX := winX + c.X // Absolute X of the container's top-left corner
-	absY := winY + c.Y // Absolute Y of the container's top-left corner
+	if c.Border != c.borderApplied {
+		c.updateScrollState() // The border was set after the rows were laid out
+	}
+	if c.Border {
+		c.renderBorder(buffer, winX+c.X, winY+c.Y)
+	}
+	absX := winX + c.X + c.inset() // Absolute X of the content area's top-left corner
+	absY := winY + c.Y + c.inset() // Absolute Y of the content area's top-left corner
+	innerWidth := c.innerWidth()
 
 	// Determine the width available *specifically for text content*
-	textContentWidth := c.Width
+	iconWidth := c.iconColumnWidth()
+	textContentWidth := innerWidth - iconWidth
 	// Use scrollBar.Visible to decide if width needs reduction
 	if c.scrollBar.Visible {
 		textContentWidth--
@@ -1041,90 +3214,671 @@ func (c *Container) Render(buffer *strings.Builder, winX, winY int, _ int) {
 		scrollOffset = c.scrollBar.Value
 	}
 
+	hOffset := 0 // First visible column
+	if c.hScrollBar.Visible {
+		hOffset = c.hScrollBar.Value
+	}
+
 	// Render visible lines of string content
-	for i := 0; i < c.Height; i++ {
+	for i := 0; i < c.visibleHeight(); i++ {
 		contentIndex := i + scrollOffset
 		lineY := absY + i // Absolute Y for the current line
 
 		// Move cursor to the start of the line within the container
 		buffer.WriteString(MoveCursorCmd(lineY, absX))
 
-		// Determine line color
-		lineColor := c.Color // Use container's default or inherit window's
+		// Determine line color
+		lineColor := themeColor(c.Color, c.theme().Content) // Use container's default or the window's theme
+		if original := c.originalIndex(contentIndex); original >= 0 && original < len(c.RowColors) && c.RowColors[original] != "" {
+			lineColor = c.RowColors[original] // Per-row color, kept out of the text so truncation only counts visible characters
+		}
+		if contentIndex < c.rowCount() {
+			lineColor += stripeColor(c.ZebraColors, contentIndex) // After the row color, so the stripe sets the background
+		}
+		if c.Disabled {
+			lineColor = c.disabledColor()
+		}
+
+		// Only highlight the currently highlighted item (modified)
+		selected := c.IsActive && contentIndex == c.HighlightedIndex && contentIndex < c.rowCount()
+		if selected {
+			lineColor = themeColor(c.SelectionColor, c.theme().Selection) // Use selection color if active and highlighted
+		}
+		buffer.WriteString(lineColor) // Apply line color
+
+		if iconWidth > 0 {
+			// Icon column, padded so the text of every row starts at the same column
+			icon := ""
+			if original := c.originalIndex(contentIndex); contentIndex < c.rowCount() && original >= 0 && original < len(c.Icons) {
+				icon = c.Icons[original]
+			}
+			buffer.WriteString(alignText(icon, iconWidth, AlignLeft))
+		}
+
+		if contentIndex >= 0 && contentIndex < c.rowCount() {
+			var line string
+			if c.ItemRenderer != nil {
+				line = c.ItemRenderer(contentIndex, selected, textContentWidth) // Still truncated below, in case it's too wide
+			} else {
+				line = c.Content[contentIndex]
+			}
+			currentWidth := 0
+			col := 0
+			var truncatedLine strings.Builder
+			// Build the line from the first visible column, respecting textContentWidth.
+			// Escape sequences embedded in the line are copied through but take no width.
+			for i := 0; i < len(line); {
+				if n := ansiSequenceLen(line, i); n > 0 {
+					truncatedLine.WriteString(line[i : i+n])
+					i += n
+					continue
+				}
+				r, size := utf8.DecodeRuneInString(line[i:])
+				i += size
+				runeWidth := DisplayWidth(string(r))
+				col += runeWidth
+				if col <= hOffset {
+					continue // Scrolled off to the left
+				}
+				if currentWidth+runeWidth <= textContentWidth {
+					truncatedLine.WriteRune(r)
+					currentWidth += runeWidth
+				} else {
+					break // Stop adding runes if width exceeded
+				}
+			}
+			buffer.WriteString(truncatedLine.String())
+
+			// Clear the rest of the line *within the text content area only* with the current line color
+			// (re-applied, as the line's own color codes may have changed it)
+			padding := textContentWidth - currentWidth
+			if padding > 0 {
+				buffer.WriteString(colors.Reset + lineColor)
+				buffer.WriteString(strings.Repeat(" ", padding))
+			}
+		} else {
+			// Render empty line within the text content area with the current line color
+			buffer.WriteString(strings.Repeat(" ", textContentWidth))
+		}
+		buffer.WriteString(colors.Reset) // Reset color after each line to prevent spillover
+	} // End of line rendering loop
+
+	// Render the scrollbar. When hidden, the lines above already cover its column,
+	// so it is skipped rather than letting it blank out the last visible character.
+	// Pass the container's absolute top-left (absX, absY) as the origin.
+	if c.scrollBar.Visible {
+		renderElement(c.scrollBar, c.windowTheme, buffer, absX, absY, innerWidth) // Pass the content area's abs origin
+	}
+	if c.hScrollBar.Visible {
+		renderElement(c.hScrollBar, c.windowTheme, buffer, absX, absY, innerWidth) // Only drawn when needed, as it shares the last row with content
+	}
+
+	// Filter line on the bottom row
+	if c.filterRows() > 0 {
+		filterText := "/" + c.FilterQuery
+		if runes := []rune(filterText); len(runes) > innerWidth {
+			filterText = string(runes[len(runes)-innerWidth:]) // Keep the end of the query in view
+		}
+		buffer.WriteString(MoveCursorCmd(absY+c.innerHeight()-1, absX))
+		if c.filtering {
+			buffer.WriteString(themeColor(c.ActiveColor, c.theme().Active()))
+		} else {
+			buffer.WriteString(colors.Gray)
+		}
+		buffer.WriteString(filterText)
+		buffer.WriteString(strings.Repeat(" ", innerWidth-len([]rune(filterText))))
+		buffer.WriteString(colors.Reset)
+	}
+
+	c.cursorAbsX = absX // Store position for cursor management (even though not shown)
+	c.cursorAbsY = absY
+}
+
+// GetScrollbar returns the internal scrollbar if it exists.
+// This allows the window to make the scrollbar focusable.
+// NOTE: We are changing focus logic, so this might not be needed by Window anymore.
+// The container uses the scrollbar's OnScroll; set the container's OnScroll instead.
+func (c *Container) GetScrollbar() *ScrollBar {
+	return c.scrollBar
+}
+
+// GetHorizontalScrollbar returns the internal horizontal scrollbar.
+func (c *Container) GetHorizontalScrollbar() *ScrollBar {
+	return c.hScrollBar
+}
+
+// --- Table ---
+
+// Alignment controls how text is positioned within a fixed-width cell.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// Table displays rows of cells in aligned columns under a header row.
+// Rows can be highlighted with the arrow keys and selected with Enter, like Container.
+type Table struct {
+	X, Y             int
+	Width, Height    int                // Height includes the header and separator rows
+	Headers          []string           // Column headers; the number of headers sets the column count
+	Rows             [][]string         // Row cells; missing cells render empty, extra cells are ignored
+	Alignments       []Alignment        // Per-column alignment; missing entries default to AlignLeft
+	IsActive         bool               // Tracks if the table has focus
+	Disabled         bool               // Disabled tables are dimmed and skipped by focus traversal
+	Hidden           bool               // Hidden tables are not rendered and are skipped by focus traversal
+	HighlightedIndex int                // Index of the currently highlighted row
+	SelectedIndex    int                // Index of the row last selected via Enter
+	Color            string             // Row text color (use window's if empty)
+	HeaderColor      string             // Header text color
+	SeparatorColor   string             // Color of the column separators and header line
+	SelectionColor   string             // Background/text color for the highlighted row
+	ZebraColors      [2]string          // Optional backgrounds of even and odd rows, for readability; the highlight wins
+	OnRowSelected    func(rowIndex int) // Callback when a row is selected via Enter
+	scrollOffset     int                // Index of the first visible row
+	cursorAbsX       int                // Used for cursor position tracking
+	cursorAbsY       int                // Used for cursor position tracking
+	themed
+}
+
+// stripeColor returns the zebra background of row: zebra[0] for even rows and
+// zebra[1] for odd ones. Rows keep their stripe while scrolling.
+func stripeColor(zebra [2]string, row int) string {
+	return zebra[row%2]
+}
+
+// NewTable creates a new Table instance.
+func NewTable(x, y, width, height int, headers []string, rows [][]string) *Table {
+	// Ensure room for the header, the separator and at least one row
+	if height < 3 {
+		height = 3
+	}
+
+	t := &Table{
+		X:                x,
+		Y:                y,
+		Width:            width,
+		Height:           height,
+		Headers:          headers,
+		Rows:             rows,
+		IsActive:         false,
+		HighlightedIndex: 0,
+		SelectedIndex:    -1,
+		Color:            "",
+		HeaderColor:      colors.BoldWhite,
+		SeparatorColor:   colors.Gray,
+		SelectionColor:   colors.BgBlue + colors.BoldWhite,
+	}
+	if len(rows) == 0 {
+		t.HighlightedIndex = -1
+	}
+	return t
+}
+
+// SetRows replaces the table rows, keeping the highlight within range.
+func (t *Table) SetRows(rows [][]string) {
+	t.Rows = rows
+	if t.HighlightedIndex >= len(rows) {
+		t.HighlightedIndex = len(rows) - 1
+	} else if t.HighlightedIndex < 0 && len(rows) > 0 {
+		t.HighlightedIndex = 0
+	}
+	if t.SelectedIndex >= len(rows) {
+		t.SelectedIndex = -1
+	}
+	t.ensureHighlightVisible()
+}
+
+// SetAlignment sets the alignment of a single column.
+func (t *Table) SetAlignment(col int, align Alignment) {
+	if col < 0 || col >= len(t.Headers) {
+		return
+	}
+	for len(t.Alignments) <= col {
+		t.Alignments = append(t.Alignments, AlignLeft)
+	}
+	t.Alignments[col] = align
+}
+
+// visibleRows returns the number of data rows that fit below the header.
+func (t *Table) visibleRows() int {
+	rows := t.Height - 2
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// ensureHighlightVisible adjusts the scroll offset if the highlighted row is out of view.
+func (t *Table) ensureHighlightVisible() {
+	visible := t.visibleRows()
+	if t.HighlightedIndex < 0 {
+		t.scrollOffset = 0
+		return
+	}
+	if t.HighlightedIndex < t.scrollOffset {
+		t.scrollOffset = t.HighlightedIndex
+	} else if t.HighlightedIndex >= t.scrollOffset+visible {
+		t.scrollOffset = t.HighlightedIndex - visible + 1
+	}
+	maxOffset := len(t.Rows) - visible
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if t.scrollOffset > maxOffset {
+		t.scrollOffset = maxOffset
+	}
+}
+
+// HighlightNext highlights the next row (doesn't select it).
+func (t *Table) HighlightNext() {
+	if t.HighlightedIndex < len(t.Rows)-1 {
+		t.HighlightedIndex++
+		t.ensureHighlightVisible()
+	}
+}
+
+// HighlightPrevious highlights the previous row (doesn't select it).
+func (t *Table) HighlightPrevious() {
+	if t.HighlightedIndex > 0 {
+		t.HighlightedIndex--
+		t.ensureHighlightVisible()
+	}
+}
+
+// HighlightFirst highlights the first row (Home).
+func (t *Table) HighlightFirst() {
+	if len(t.Rows) > 0 {
+		t.HighlightedIndex = 0
+		t.ensureHighlightVisible()
+	}
+}
+
+// HighlightLast highlights the last row (End).
+func (t *Table) HighlightLast() {
+	if len(t.Rows) > 0 {
+		t.HighlightedIndex = len(t.Rows) - 1
+		t.ensureHighlightVisible()
+	}
+}
+
+// SelectHighlightedRow selects the highlighted row and calls OnRowSelected.
+// This is called when the user presses Enter on the table.
+func (t *Table) SelectHighlightedRow() {
+	if t.HighlightedIndex >= 0 && t.HighlightedIndex < len(t.Rows) {
+		t.SelectedIndex = t.HighlightedIndex
+		if t.OnRowSelected != nil {
+			t.OnRowSelected(t.SelectedIndex)
+		}
+	}
+}
+
+// GetHighlightedIndex returns the index of the highlighted row, or -1 if there are no rows.
+func (t *Table) GetHighlightedIndex() int {
+	return t.HighlightedIndex
+}
+
+// GetSelectedIndex returns the index of the row last selected via Enter, or -1.
+func (t *Table) GetSelectedIndex() int {
+	return t.SelectedIndex
+}
+
+// cell returns the text of a cell, or "" if the row has no such column.
+func (t *Table) cell(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// SortByColumn reorders the rows by the values in column col. Cells that are both
+// numbers are compared numerically, everything else as strings. The sort is stable,
+// and the highlighted and selected rows follow their data to the new positions.
+func (t *Table) SortByColumn(col int, ascending bool) {
+	if col < 0 || col >= len(t.Headers) {
+		return
+	}
+
+	order := make([]int, len(t.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := t.cell(t.Rows[order[i]], col), t.cell(t.Rows[order[j]], col)
+		if ascending {
+			return compareCells(a, b) < 0
+		}
+		return compareCells(a, b) > 0
+	})
+
+	sorted := make([][]string, len(t.Rows))
+	highlighted, selected := -1, -1
+	for newIndex, oldIndex := range order {
+		sorted[newIndex] = t.Rows[oldIndex]
+		if oldIndex == t.HighlightedIndex {
+			highlighted = newIndex
+		}
+		if oldIndex == t.SelectedIndex {
+			selected = newIndex
+		}
+	}
+	t.Rows = sorted
+	t.HighlightedIndex = highlighted
+	t.SelectedIndex = selected
+	t.ensureHighlightVisible()
+}
+
+// compareCells compares two cell values, numerically when both parse as numbers.
+func compareCells(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// columnWidths returns the display width of each column: the widest of its header
+// and cells, shrinking the trailing columns if the total exceeds the table width.
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.Headers))
+	for col, header := range t.Headers {
+		widths[col] = DisplayWidth(header)
+		for _, row := range t.Rows {
+			if w := DisplayWidth(t.cell(row, col)); w > widths[col] {
+				widths[col] = w
+			}
+		}
+	}
+
+	if t.Width <= 0 {
+		return widths
+	}
+	// Columns are separated by " │ " (3 columns wide)
+	available := t.Width - 3*(len(widths)-1)
+	for col := range widths {
+		if widths[col] > available {
+			widths[col] = available
+		}
+		if widths[col] < 0 {
+			widths[col] = 0
+		}
+		available -= widths[col]
+	}
+	return widths
+}
+
+// totalWidth returns the rendered width of the table for the given column widths.
+func (t *Table) totalWidth(widths []int) int {
+	if t.Width > 0 {
+		return t.Width
+	}
+	total := 3 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	if total < 0 {
+		total = 0
+	}
+	return total
+}
+
+// alignText truncates or pads s to exactly cellWidth display columns.
+func alignText(s string, cellWidth int, align Alignment) string {
+	var truncated strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := DisplayWidth(string(r))
+		if used+rw > cellWidth {
+			break
+		}
+		truncated.WriteRune(r)
+		used += rw
+	}
+
+	padding := cellWidth - used
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + truncated.String()
+	case AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + truncated.String() + strings.Repeat(" ", padding-left)
+	default:
+		return truncated.String() + strings.Repeat(" ", padding)
+	}
+}
+
+// alignment returns the configured alignment for a column.
+func (t *Table) alignment(col int) Alignment {
+	if col < len(t.Alignments) {
+		return t.Alignments[col]
+	}
+	return AlignLeft
+}
+
+// HandleInput implements InputHandler: Up/Down, Home/End and PageUp/PageDown move
+// the highlight, and Enter selects the highlighted row (calling OnRowSelected).
+func (t *Table) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !t.IsActive {
+		return false, false, false
+	}
+	switch ev.Key {
+	case KeyUp: // Highlight previous row
+		t.HighlightPrevious()
+	case KeyDown: // Highlight next row
+		t.HighlightNext()
+	case KeyHome: // Highlight the first row
+		t.HighlightFirst()
+	case KeyEnd: // Highlight the last row
+		t.HighlightLast()
+	case KeyPageUp: // Move the highlight up one page
+		for i := 0; i < t.visibleRows(); i++ {
+			t.HighlightPrevious()
+		}
+	case KeyPageDown: // Move the highlight down one page
+		for i := 0; i < t.visibleRows(); i++ {
+			t.HighlightNext()
+		}
+	case KeyEnter: // Select the highlighted row
+		t.SelectHighlightedRow()
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface
+func (t *Table) NeedsCursor() bool {
+	return false // Tables never need a cursor visible
+}
+
+// GetCursorPosition implements CursorManager interface
+func (t *Table) GetCursorPosition() (int, int, bool) {
+	return t.cursorAbsX, t.cursorAbsY, false // Position known but not needed
+}
+
+// Bounds implements Bounded.
+func (t *Table) Bounds() (int, int, int, int) {
+	return t.X, t.Y, t.totalWidth(t.columnWidths()), t.Height
+}
+
+// SetPosition implements Positioner.
+func (t *Table) SetPosition(x, y int) {
+	t.X, t.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (t *Table) SetSize(width, height int) {
+	t.Width, t.Height = width, height
+}
+
+// SetEnabled implements Enabler.
+func (t *Table) SetEnabled(enabled bool) {
+	t.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (t *Table) IsEnabled() bool {
+	return !t.Disabled
+}
+
+// SetVisible implements Hider.
+func (t *Table) SetVisible(visible bool) {
+	t.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (t *Table) IsVisible() bool {
+	return !t.Hidden
+}
+
+// SetActive sets the focus state of the table.
+func (t *Table) SetActive(active bool) {
+	t.IsActive = active
+}
+
+// Render draws the header, the separator line and the visible rows.
+func (t *Table) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + t.X
+	absY := winY + t.Y
 
-		// Only highlight the currently highlighted item (modified)
-		if c.IsActive && contentIndex == c.HighlightedIndex && contentIndex < len(c.Content) {
-			lineColor = c.SelectionColor // Use selection color if active and highlighted
-		}
-		buffer.WriteString(lineColor) // Apply line color
+	widths := t.columnWidths()
+	tableWidth := t.totalWidth(widths)
 
-		if contentIndex >= 0 && contentIndex < len(c.Content) {
-			line := c.Content[contentIndex]
-			currentWidth := 0
-			truncatedLine := ""
-			// Build the line rune by rune, respecting textContentWidth
-			for _, r := range line {
-				// Assuming standard width characters for now
-				runeWidth := 1
-				if currentWidth+runeWidth <= textContentWidth {
-					truncatedLine += string(r)
-					currentWidth += runeWidth
-				} else {
-					break // Stop adding runes if width exceeded
-				}
+	// renderRow writes one line of cells, filling any leftover width with spaces
+	renderRow := func(cells []string, color string) {
+		buffer.WriteString(color)
+		used := 0
+		for col, w := range widths {
+			if col > 0 {
+				buffer.WriteString(t.SeparatorColor)
+				buffer.WriteString(" " + glyphs.vertical + " ")
+				buffer.WriteString(colors.Reset)
+				buffer.WriteString(color)
+				used += 3
 			}
-			buffer.WriteString(truncatedLine)
+			buffer.WriteString(alignText(t.cell(cells, col), w, t.alignment(col)))
+			used += w
+		}
+		if used < tableWidth {
+			buffer.WriteString(strings.Repeat(" ", tableWidth-used))
+		}
+		buffer.WriteString(colors.Reset)
+	}
 
-			// Clear the rest of the line *within the text content area only* with the current line color
-			padding := textContentWidth - currentWidth
-			if padding > 0 {
-				buffer.WriteString(strings.Repeat(" ", padding))
-			}
-		} else {
-			// Render empty line within the text content area with the current line color
-			buffer.WriteString(strings.Repeat(" ", textContentWidth))
+	// Header row
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	renderRow(t.Headers, themeColor(t.HeaderColor, t.theme().Title))
+
+	// Header separator line, with junctions under the column separators
+	buffer.WriteString(MoveCursorCmd(absY+1, absX))
+	buffer.WriteString(t.SeparatorColor)
+	used := 0
+	for col, w := range widths {
+		if col > 0 {
+			buffer.WriteString(glyphs.horizontal + glyphs.cross + glyphs.horizontal)
+			used += 3
 		}
-		buffer.WriteString(colors.Reset) // Reset color after each line to prevent spillover
-	} // End of line rendering loop
+		buffer.WriteString(strings.Repeat(glyphs.horizontal, w))
+		used += w
+	}
+	if used < tableWidth {
+		buffer.WriteString(strings.Repeat(glyphs.horizontal, tableWidth-used))
+	}
+	buffer.WriteString(colors.Reset)
 
-	// Render the scrollbar (it handles its own visibility check)
-	// Pass the container's absolute top-left (absX, absY) as the origin.
-	c.scrollBar.Render(buffer, absX, absY, c.Width) // Pass container's abs origin
+	// Visible rows
+	for i := 0; i < t.visibleRows(); i++ {
+		rowIndex := i + t.scrollOffset
+		buffer.WriteString(MoveCursorCmd(absY+2+i, absX))
 
-	c.cursorAbsX = absX // Store position for cursor management (even though not shown)
-	c.cursorAbsY = absY
-}
+		if rowIndex >= len(t.Rows) {
+			buffer.WriteString(themeColor(t.Color, t.theme().Content))
+			buffer.WriteString(strings.Repeat(" ", tableWidth))
+			buffer.WriteString(colors.Reset)
+			continue
+		}
 
-// GetScrollbar returns the internal scrollbar if it exists.
-// This allows the window to make the scrollbar focusable.
-// NOTE: We are changing focus logic, so this might not be needed by Window anymore.
-func (c *Container) GetScrollbar() *ScrollBar {
-	return c.scrollBar
+		rowColor := themeColor(t.Color, t.theme().Content) + stripeColor(t.ZebraColors, rowIndex)
+		if t.Disabled {
+			rowColor = t.disabledColor()
+		} else if t.IsActive && rowIndex == t.HighlightedIndex {
+			rowColor = themeColor(t.SelectionColor, t.theme().Selection)
+		}
+		renderRow(t.Rows[rowIndex], rowColor)
+	}
+
+	t.cursorAbsX = absX
+	t.cursorAbsY = absY
 }
 
 // --- TextArea ---
 
 // TextArea represents a multi-line text input area with scrolling.
 type TextArea struct {
-	X, Y           int      // Position relative to window content area
-	Width, Height  int      // Dimensions of the text area
-	Color          string   // Default text color
-	ActiveColor    string   // Color when active (e.g., border or cursor)
-	IsActive       bool     // State for rendering/input handling
-	Lines          []string // Content stored as lines
-	cursorLine     int      // Cursor's line index (0-based)
-	cursorCol      int      // Cursor's column index (rune-based, 0-based) within the line
-	viewTopLine    int      // Index of the topmost visible line
-	scrollBar      *ScrollBar
-	needsScroll    bool
-	maxChars       int    // Optional maximum character limit (0 for unlimited)
-	wordCount      int    // Current word count
-	charCount      int    // Current character count
-	cursorAbsX     int    // Absolute X position of cursor (set during Render)
-	cursorAbsY     int    // Absolute Y position of cursor (set during Render)
-	showWordCount  bool   // Flag to control word count visibility
-	showCharCount  bool   // Flag to control char count visibility
-	bottomLineText string // Text to display on the bottom line (word/char count)
+	X, Y             int      // Position relative to window content area
+	Width, Height    int      // Dimensions of the text area
+	Color            string   // Default text color
+	ActiveColor      string   // Color when active (e.g., border or cursor)
+	IsActive         bool     // State for rendering/input handling
+	Disabled         bool     // Disabled text areas are dimmed and skipped by focus traversal
+	Hidden           bool     // Hidden text areas are not rendered and are skipped by focus traversal
+	ReadOnly         bool     // Read-only text areas can be navigated, scrolled and selected but not edited
+	Lines            []string // Content stored as lines
+	cursorLine       int      // Cursor's line index (0-based)
+	cursorCol        int      // Cursor's column index (rune-based, 0-based) within the line
+	viewTopLine      int      // Index of the topmost visible row (a logical line, or a wrapped row with WordWrap)
+	scrollBar        *ScrollBar
+	hScrollBar       *ScrollBar // Horizontal scrollbar, shown above the status line when lines are wider than the view
+	needsScroll      bool
+	needsHScroll     bool
+	maxChars         int         // Optional maximum character limit (0 for unlimited)
+	wordCount        int         // Current word count
+	charCount        int         // Current character count
+	cursorAbsX       int         // Absolute X position of cursor (set during Render)
+	cursorAbsY       int         // Absolute Y position of cursor (set during Render)
+	showWordCount    bool        // Flag to control word count visibility
+	showCharCount    bool        // Flag to control char count visibility
+	bottomLineText   string      // Text to display on the bottom line (word/char count)
+	CursorShape      CursorShape // Cursor shape while the text area is active (CursorDefault = the window's, see Window.SetCursorShape)
+	WordWrap         bool        // Soft-wrap long lines onto multiple rows instead of cutting them off
+	viewLeftCol      int         // First visible display column (horizontal scroll, when WordWrap is off)
+	ShowColumnOffset bool        // Show the horizontal scroll offset on the status line
+	SelectionColor   string      // Color for selected text
+	hasSelection     bool        // Whether a selection is active
+	selStartLine     int         // Selection anchor line (where Shift+Arrow selection began)
+	selStartCol      int         // Selection anchor column
+	selEndLine       int         // Selection end line (follows the cursor)
+	selEndCol        int         // Selection end column
+
+	OnChange     func(text string) // Called with the new text after each edit (typing, deleting, pasting, SetText)
+	LimitColor   string            // Color of the counts once maxChars stopped typing (colors.BoldRed if empty)
+	limitReached bool              // A keystroke or paste was cut off by maxChars
+	MatchColor   string            // Color of the matches of Find (black on yellow if empty)
+	findQuery    string            // Query of the last Find
+	matches      []Pos             // Starts of the matches of findQuery, in text order
+	matchIndex   int               // Match last selected by FindNext/FindPrev
+	searchBox    *TextBox          // Query field of the find bar (see StartSearch)
+
+	AcceptTab         bool // Tab inserts a tab instead of moving focus (Shift+Tab still moves focus)
+	TabWidth          int  // Columns between tab stops (4 if 0)
+	InsertTabAsSpaces bool // With AcceptTab, Tab inserts spaces up to the next tab stop instead of a tab
+	overwrite         bool // Typing replaces the character under the cursor (toggled with Insert)
+	themed
+}
+
+// textAreaRow is one display row of a TextArea: the runes [start, end) of a logical line.
+type textAreaRow struct {
+	line       int // Index into Lines
+	start, end int // Rune range of the line shown on this row
 }
 
 // NewTextArea creates a new TextArea instance.
@@ -1152,24 +3906,27 @@ func NewTextArea(initialText string, x, y, width, height, maxChars int, color, a
 	containerID := fmt.Sprintf("textarea_%d_%d_scrollbar", x, y)
 	scrollBar := NewScrollBar(sbX, sbY, sbHeight, 0, 0, colors.Gray, colors.BoldWhite, containerID)
 	scrollBar.Visible = false // Start hidden
+	hScrollBar := NewHorizontalScrollBar(0, height-2, width, 0, 0, colors.Gray, colors.BoldWhite, containerID+"_h")
 
 	ta := &TextArea{
-		X:             x,
-		Y:             y,
-		Width:         width,
-		Height:        height,
-		Color:         color,
-		ActiveColor:   activeColor,
-		IsActive:      false,
-		Lines:         lines,
-		cursorLine:    0, // Start at the beginning
-		cursorCol:     0,
-		viewTopLine:   0,
-		scrollBar:     scrollBar,
-		needsScroll:   false,
-		maxChars:      maxChars,
-		showWordCount: showWordCount,
-		showCharCount: showCharCount,
+		X:              x,
+		Y:              y,
+		Width:          width,
+		Height:         height,
+		Color:          color,
+		ActiveColor:    activeColor,
+		IsActive:       false,
+		SelectionColor: colors.BgBlue + colors.BoldWhite,
+		Lines:          lines,
+		cursorLine:     0, // Start at the beginning
+		cursorCol:      0,
+		viewTopLine:    0,
+		scrollBar:      scrollBar,
+		hScrollBar:     hScrollBar,
+		needsScroll:    false,
+		maxChars:       maxChars,
+		showWordCount:  showWordCount,
+		showCharCount:  showCharCount,
 	}
 
 	// Set the scrollbar's OnScroll callback to update the viewTopLine
@@ -1201,6 +3958,9 @@ func (ta *TextArea) calculateCounts() {
 	}
 
 	ta.wordCount = totalWords
+	if ta.maxChars <= 0 || ta.charCount < ta.maxChars {
+		ta.limitReached = false // There is room again
+	}
 
 	// Update bottom line text
 	parts := []string{}
@@ -1217,17 +3977,178 @@ func (ta *TextArea) calculateCounts() {
 	ta.bottomLineText = strings.Join(parts, " | ")
 }
 
+// textChanged updates the counts after an edit and calls OnChange.
+func (ta *TextArea) textChanged() {
+	ta.calculateCounts()
+	ta.refreshMatches()
+	if ta.OnChange != nil {
+		ta.OnChange(ta.GetText())
+	}
+}
+
+// GetWordCount returns the number of words in the text.
+func (ta *TextArea) GetWordCount() int {
+	return ta.wordCount
+}
+
+// GetCharCount returns the number of characters in the text, counting each line break as one.
+func (ta *TextArea) GetCharCount() int {
+	return ta.charCount
+}
+
+// wrapWidth returns the row width used for word wrapping. One column is always
+// reserved for the scrollbar, so wrapping doesn't depend on whether it is shown.
+func (ta *TextArea) wrapWidth() int {
+	if ta.Width-1 < 1 {
+		return 1
+	}
+	return ta.Width - 1
+}
+
+// displayRows returns the rows the text occupies on screen: one per logical line,
+// or several per line when WordWrap is on.
+func (ta *TextArea) displayRows() []textAreaRow {
+	rows := make([]textAreaRow, 0, len(ta.Lines))
+	for lineIndex, line := range ta.Lines {
+		runes := []rune(line)
+		if !ta.WordWrap || len(runes) == 0 {
+			rows = append(rows, textAreaRow{line: lineIndex, start: 0, end: len(runes)})
+			continue
+		}
+
+		width := ta.wrapWidth()
+		for start := 0; start < len(runes); {
+			// Take the runes that fit in a row, in display columns (at least one rune)
+			end, used := start, 0
+			for end < len(runes) && (end == start || used+ta.charWidth(runes[end], used) <= width) {
+				used += ta.charWidth(runes[end], used)
+				end++
+			}
+			if end >= len(runes) {
+				rows = append(rows, textAreaRow{line: lineIndex, start: start, end: len(runes)})
+				break
+			}
+			// Break after the last space that fits; hard-break words longer than a row
+			for i := end - 1; i > start; i-- {
+				if runes[i] == ' ' {
+					end = i + 1
+					break
+				}
+			}
+			rows = append(rows, textAreaRow{line: lineIndex, start: start, end: end})
+			start = end
+		}
+	}
+	return rows
+}
+
+// charWidth returns the number of terminal columns r occupies at display column col
+// of its row: wide characters take two, and a tab reaches the next tab stop.
+func (ta *TextArea) charWidth(r rune, col int) int {
+	if r == '\t' {
+		tabWidth := ta.TabWidth
+		if tabWidth <= 0 {
+			tabWidth = 4
+		}
+		return tabWidth - col%tabWidth
+	}
+	return runeWidth(r)
+}
+
+// columnWidth returns the number of terminal columns the runes [start, end) of
+// a line occupy; wide characters count as two, and tabs reach the next tab stop
+// (counted from start, which begins a row).
+func (ta *TextArea) columnWidth(line, start, end int) int {
+	if line < 0 || line >= len(ta.Lines) {
+		return 0
+	}
+	runes := []rune(ta.Lines[line])
+	if end > len(runes) {
+		end = len(runes)
+	}
+	width := 0
+	for i := start; i < end; i++ {
+		width += ta.charWidth(runes[i], width)
+	}
+	return width
+}
+
+// cursorScreenCol converts the rune column returned by cursorRow into a display
+// column within the row.
+func (ta *TextArea) cursorScreenCol(rows []textAreaRow, rowIndex, col int) int {
+	if rowIndex < 0 || rowIndex >= len(rows) {
+		return col
+	}
+	row := rows[rowIndex]
+	return ta.columnWidth(row.line, row.start, row.start+col)
+}
+
+// cursorRow returns the index of the display row containing the cursor and the
+// cursor's column within that row, in runes.
+func (ta *TextArea) cursorRow(rows []textAreaRow) (int, int) {
+	for i, row := range rows {
+		if row.line != ta.cursorLine {
+			continue
+		}
+		isLastRowOfLine := i == len(rows)-1 || rows[i+1].line != row.line
+		if ta.cursorCol < row.end || (isLastRowOfLine && ta.cursorCol <= row.end) {
+			return i, ta.cursorCol - row.start
+		}
+	}
+	return ta.cursorLine, ta.cursorCol // Fallback: cursor outside the text
+}
+
+// moveCursorRows moves the cursor delta display rows up or down, keeping its column
+// within the row where possible. Used for Up/Down when WordWrap is on.
+func (ta *TextArea) moveCursorRows(delta int) {
+	rows := ta.displayRows()
+	current, col := ta.cursorRow(rows)
+	target := current + delta
+	if target < 0 || target >= len(rows) {
+		return
+	}
+
+	row := rows[target]
+	ta.cursorLine = row.line
+	ta.cursorCol = row.start + col
+	isLastRowOfLine := target == len(rows)-1 || rows[target+1].line != row.line
+	if ta.cursorCol > row.end || (!isLastRowOfLine && ta.cursorCol >= row.end) {
+		// Stay on the target row: its end belongs to the next row unless it ends the line
+		ta.cursorCol = row.end
+		if !isLastRowOfLine && row.end > row.start {
+			ta.cursorCol = row.end - 1
+		}
+	}
+	ta.ensureCursorVisible()
+}
+
 // updateScrollState determines if scrolling is needed and updates the scrollbar.
 func (ta *TextArea) updateScrollState() {
-	contentHeight := len(ta.Lines)
-	// Height available for text lines (excluding bottom count line)
-	visibleHeight := ta.Height - 1
+	contentHeight := len(ta.displayRows())
+
+	// A horizontal scrollbar is needed when a line (plus the cursor after it) is wider
+	// than the view; it takes a text row, so decide it before the vertical one settles
+	ta.needsHScroll = false
+	ta.needsScroll = contentHeight > ta.textRenderHeight()
+	if !ta.WordWrap && ta.maxLineLength() >= ta.textRenderWidth() {
+		ta.needsHScroll = true
+		ta.needsScroll = contentHeight > ta.textRenderHeight()
+	}
+
+	// Height available for text lines (excluding bottom count line and horizontal scrollbar)
+	visibleHeight := ta.textRenderHeight()
 	if visibleHeight < 1 {
 		visibleHeight = 1
 	}
 
-	ta.needsScroll = contentHeight > visibleHeight
 	ta.scrollBar.Visible = ta.needsScroll
+	ta.hScrollBar.Visible = ta.needsHScroll
+	ta.hScrollBar.Y = ta.Height - 2
+	ta.hScrollBar.Width = ta.textRenderWidth()
+	ta.hScrollBar.MaxValue = 0
+	if ta.needsHScroll {
+		ta.hScrollBar.MaxValue = ta.maxLineLength() - ta.textRenderWidth() + 1
+	}
 
 	if ta.needsScroll {
 		sbMaxValue := contentHeight - visibleHeight
@@ -1247,69 +4168,132 @@ func (ta *TextArea) updateScrollState() {
 	}
 }
 
-// ensureCursorVisible adjusts viewTopLine so the cursor is visible.
+// textRenderWidth returns the number of columns available for text,
+// excluding the scrollbar column when it is shown.
+func (ta *TextArea) textRenderWidth() int {
+	width := ta.Width
+	if ta.needsScroll {
+		width-- // Make space for the scrollbar
+	}
+	if width < 0 {
+		width = 0
+	}
+	return width
+}
+
+// textRenderHeight returns the number of rows available for text, excluding the
+// status line and the horizontal scrollbar row when it is shown.
+func (ta *TextArea) textRenderHeight() int {
+	height := ta.Height - 1
+	if ta.needsHScroll {
+		height--
+	}
+	if height < 0 {
+		height = 0
+	}
+	return height
+}
+
+// maxLineLength returns the display width of the longest line, in columns.
+func (ta *TextArea) maxLineLength() int {
+	longest := 0
+	for i, line := range ta.Lines {
+		if length := ta.columnWidth(i, 0, len(line)); length > longest {
+			longest = length
+		}
+	}
+	return longest
+}
+
+// ensureCursorVisible adjusts viewTopLine (and viewLeftCol) so the cursor is visible.
 func (ta *TextArea) ensureCursorVisible() {
-	visibleHeight := ta.Height - 1
+	// Horizontal scroll: keep the cursor column inside the text width.
+	// Wrapped rows always fit, so there is nothing to scroll.
+	cursorCol := ta.columnWidth(ta.cursorLine, 0, ta.cursorCol) // In display columns
+	if ta.WordWrap {
+		ta.viewLeftCol = 0
+	} else if width := ta.textRenderWidth(); cursorCol < ta.viewLeftCol {
+		ta.viewLeftCol = cursorCol
+	} else if width > 0 && cursorCol >= ta.viewLeftCol+width {
+		ta.viewLeftCol = cursorCol - width + 1
+	}
+
+	visibleHeight := ta.textRenderHeight()
 	if visibleHeight < 1 {
 		visibleHeight = 1
 	}
 	bottomVisibleLine := ta.viewTopLine + visibleHeight - 1
+	cursorRow, _ := ta.cursorRow(ta.displayRows())
 
-	if ta.cursorLine < ta.viewTopLine {
+	if cursorRow < ta.viewTopLine {
 		// Cursor is above the view
-		ta.viewTopLine = ta.cursorLine
+		ta.viewTopLine = cursorRow
 		ta.scrollBar.SetValue(ta.viewTopLine)
-	} else if ta.cursorLine > bottomVisibleLine {
+	} else if cursorRow > bottomVisibleLine {
 		// Cursor is below the view
-		ta.viewTopLine = ta.cursorLine - visibleHeight + 1
+		ta.viewTopLine = cursorRow - visibleHeight + 1
 		ta.scrollBar.SetValue(ta.viewTopLine)
 	}
 }
 
+// SetActive sets the focus state of the text area.
+func (ta *TextArea) SetActive(active bool) {
+	ta.IsActive = active
+}
+
 // Render draws the TextArea element.
 func (ta *TextArea) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + ta.X
 	absY := winY + ta.Y
-	renderColor := ta.Color
-	if ta.IsActive {
-		renderColor = ta.ActiveColor
-		// Optionally draw a border or change background when active
+	renderColor := themeColor(ta.Color, ta.theme().Accent)
+	if ta.Disabled {
+		renderColor = ta.disabledColor()
+	} else if ta.IsActive {
+		renderColor = themeColor(ta.ActiveColor, ta.theme().Active())
+		if ta.ReadOnly {
+			renderColor += colors.Dim
+		}
 	}
 	buffer.WriteString(renderColor)
 
 	// --- Render Text Content ---
-	textRenderWidth := ta.Width
-	if ta.needsScroll {
-		textRenderWidth-- // Make space for the scrollbar
-	}
-	if textRenderWidth < 0 {
-		textRenderWidth = 0
-	}
+	textRenderWidth := ta.textRenderWidth()
 	// Height available for text lines
-	visibleHeight := ta.Height - 1
+	visibleHeight := ta.textRenderHeight()
 	if visibleHeight < 0 {
 		visibleHeight = 0
 	}
 
+	rows := ta.displayRows()
 	for i := 0; i < visibleHeight; i++ {
-		lineIndex := ta.viewTopLine + i
+		rowIndex := ta.viewTopLine + i
 		currentLineY := absY + i
 		buffer.WriteString(MoveCursorCmd(currentLineY, absX))
 
-		if lineIndex >= 0 && lineIndex < len(ta.Lines) {
-			line := ta.Lines[lineIndex]
-			// Basic line rendering (no horizontal scrolling or wrapping yet)
-			visibleLine := ""
-			runes := []rune(line)
-			if len(runes) > textRenderWidth {
-				// Naive truncation for now
-				visibleLine = string(runes[:textRenderWidth])
-			} else {
-				visibleLine = line
+		if rowIndex >= 0 && rowIndex < len(rows) {
+			row := rows[rowIndex]
+			runes := []rune(ta.Lines[row.line])
+			// Skip the horizontally scrolled-off columns (viewLeftCol is 0 with WordWrap)
+			start, col := row.start, 0
+			for start < row.end && col < ta.viewLeftCol {
+				col += ta.charWidth(runes[start], col)
+				start++
+			}
+			// A wide character cut by the left edge leaves a blank cell
+			used := col - ta.viewLeftCol
+			if used > textRenderWidth {
+				used = textRenderWidth
+			}
+			buffer.WriteString(strings.Repeat(" ", used))
+			// Rows longer than the view are cut off at the right edge
+			end := start
+			for end < row.end && used+ta.charWidth(runes[end], ta.viewLeftCol+used) <= textRenderWidth {
+				used += ta.charWidth(runes[end], ta.viewLeftCol+used)
+				end++
 			}
-			buffer.WriteString(visibleLine)
+			ta.writeRowText(buffer, row.line, start, end, col, renderColor)
 			// Clear rest of the line within the text area width
-			buffer.WriteString(strings.Repeat(" ", textRenderWidth-len([]rune(visibleLine))))
+			buffer.WriteString(strings.Repeat(" ", textRenderWidth-used))
 		} else {
 			// Empty line within the text area
 			buffer.WriteString(strings.Repeat(" ", textRenderWidth))
@@ -1320,29 +4304,36 @@ func (ta *TextArea) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 	// --- Render ScrollBar ---
 	// Pass absolute coordinates of the TextArea's top-left corner
-	// The scrollbar's X, Y are relative to this origin.
-	ta.scrollBar.Render(buffer, absX, absY, ta.Width)
+	// The scrollbar's X, Y are relative to this origin. A hidden scrollbar's
+	// column holds text, drawn above, so it isn't cleared.
+	if ta.scrollBar.Visible {
+		renderElement(ta.scrollBar, ta.windowTheme, buffer, absX, absY, ta.Width)
+	}
+	if ta.hScrollBar.Visible {
+		// The horizontal scrollbar follows the cursor-driven viewLeftCol
+		ta.hScrollBar.Value = ta.viewLeftCol
+		if ta.hScrollBar.Value > ta.hScrollBar.MaxValue {
+			ta.hScrollBar.Value = ta.hScrollBar.MaxValue
+		}
+		renderElement(ta.hScrollBar, ta.windowTheme, buffer, absX, absY, ta.Width)
+	}
 	// --- End ScrollBar ---
 
-	// --- Render Bottom Line (Word Count/Char Count) ---
+	// --- Render Bottom Line (Word Count/Char Count, or the find bar) ---
 	bottomLineY := absY + ta.Height - 1
-	buffer.WriteString(MoveCursorCmd(bottomLineY, absX))
-	buffer.WriteString(colors.Gray) // Use gray color for the status line
-	countText := ta.bottomLineText
-	countRunes := []rune(countText)
-	if len(countRunes) > ta.Width {
-		countText = string(countRunes[:ta.Width])
+	if ta.IsSearching() {
+		ta.renderSearchBar(buffer, absX, bottomLineY)
+	} else {
+		ta.renderCounts(buffer, absX, bottomLineY)
 	}
-	buffer.WriteString(countText)
-	// Clear rest of bottom line
-	buffer.WriteString(strings.Repeat(" ", ta.Width-len([]rune(countText))))
-	buffer.WriteString(colors.Reset)
 	// --- End Bottom Line ---
 
 	// --- Calculate Cursor Position ---
-	// This needs refinement based on horizontal scrolling/wrapping if implemented
-	cursorScreenLine := ta.cursorLine - ta.viewTopLine
-	cursorScreenCol := ta.cursorCol // Assuming no horizontal scroll/wrap for now
+	// Work in display rows, so wrapped lines place the cursor on the right row
+	cursorRow, cursorRuneCol := ta.cursorRow(rows)
+	cursorScreenCol := ta.cursorScreenCol(rows, cursorRow, cursorRuneCol)
+	cursorScreenLine := cursorRow - ta.viewTopLine
+	cursorScreenCol -= ta.viewLeftCol // Account for horizontal scrolling
 
 	// Clamp cursor screen position to be within the visible text area bounds
 	if cursorScreenLine < 0 {
@@ -1352,8 +4343,9 @@ func (ta *TextArea) Render(buffer *strings.Builder, winX, winY int, _ int) {
 		cursorScreenLine = visibleHeight - 1
 		// Place cursor at the end of the last visible line if scrolled off bottom
 		lastVisibleLineIdx := ta.viewTopLine + visibleHeight - 1
-		if lastVisibleLineIdx >= 0 && lastVisibleLineIdx < len(ta.Lines) {
-			lastLineLen := len([]rune(ta.Lines[lastVisibleLineIdx]))
+		if lastVisibleLineIdx >= 0 && lastVisibleLineIdx < len(rows) {
+			lastRow := rows[lastVisibleLineIdx]
+			lastLineLen := ta.columnWidth(lastRow.line, lastRow.start, lastRow.end)
 			if cursorScreenCol > lastLineLen {
 				cursorScreenCol = lastLineLen
 			}
@@ -1366,10 +4358,11 @@ func (ta *TextArea) Render(buffer *strings.Builder, winX, winY int, _ int) {
 		}
 	}
 
-	// Clamp column based on current line length and visible width
+	// Clamp column based on current row length (past the scrolled-off columns) and visible width
 	currentLineLen := 0
-	if ta.cursorLine >= 0 && ta.cursorLine < len(ta.Lines) {
-		currentLineLen = len([]rune(ta.Lines[ta.cursorLine]))
+	if cursorRow >= 0 && cursorRow < len(rows) {
+		row := rows[cursorRow]
+		currentLineLen = ta.columnWidth(row.line, row.start, row.end) - ta.viewLeftCol
 	}
 	if cursorScreenCol > currentLineLen {
 		cursorScreenCol = currentLineLen // Don't go past end of line
@@ -1385,6 +4378,164 @@ func (ta *TextArea) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	// --- End Cursor Position Calculation ---
 }
 
+// renderCounts draws the status line at row absY: the word/char counts and the
+// horizontal scroll offset.
+func (ta *TextArea) renderCounts(buffer *strings.Builder, absX, absY int) {
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	if ta.limitReached {
+		buffer.WriteString(themeColor(ta.LimitColor, colors.BoldRed)) // maxChars stopped the last edit
+	} else {
+		buffer.WriteString(colors.Gray) // Use gray color for the status line
+	}
+	countText := ta.bottomLineText
+	if ta.ShowColumnOffset && ta.viewLeftCol > 0 {
+		offsetText := fmt.Sprintf("Col +%d", ta.viewLeftCol)
+		if countText != "" {
+			countText += " | "
+		}
+		countText += offsetText
+	}
+	countRunes := []rune(countText)
+	if len(countRunes) > ta.Width {
+		countText = string(countRunes[:ta.Width])
+	}
+	buffer.WriteString(countText)
+	// Clear rest of bottom line
+	buffer.WriteString(strings.Repeat(" ", ta.Width-len([]rune(countText))))
+	buffer.WriteString(colors.Reset)
+}
+
+// writeRowText writes the runes [start, end) of a line, starting at display column
+// screenCol of the row, drawing selected runes in SelectionColor and matches of Find in
+// MatchColor (the selection wins). Both are kept in line/column terms, so they
+// survive scrolling. Tabs are written as spaces up to the next tab stop.
+func (ta *TextArea) writeRowText(buffer *strings.Builder, line, start, end, screenCol int, renderColor string) {
+	runes := []rune(ta.Lines[line])
+	highlight := "" // Color of the current run of runes, "" for renderColor
+	for col := start; col < end; col++ {
+		runHighlight := ""
+		if ta.isSelected(line, col) {
+			runHighlight = themeColor(ta.SelectionColor, ta.theme().Selection)
+		} else if ta.isMatch(line, col) {
+			runHighlight = themeColor(ta.MatchColor, colors.BgYellow+colors.Black)
+		}
+		if runHighlight != highlight {
+			if highlight != "" {
+				buffer.WriteString(colors.Reset)
+				buffer.WriteString(renderColor)
+			}
+			buffer.WriteString(runHighlight)
+			highlight = runHighlight
+		}
+		if runes[col] == '\t' {
+			width := ta.charWidth('\t', screenCol)
+			buffer.WriteString(strings.Repeat(" ", width))
+			screenCol += width
+		} else {
+			buffer.WriteRune(runes[col])
+			screenCol += runeWidth(runes[col])
+		}
+	}
+	if highlight != "" {
+		buffer.WriteString(colors.Reset)
+		buffer.WriteString(renderColor)
+	}
+}
+
+// HandleInput implements InputHandler: typing and the editing keys, cursor
+// movement (Ctrl+Left/Right by word, Ctrl+Home/End to either end of the text),
+// Shift+Arrow to select, Ctrl+F to open the find bar, which takes the keys while
+// open, and Tab with AcceptTab.
+func (ta *TextArea) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !ta.IsActive {
+		return false, false, false
+	}
+	if ta.IsSearching() && ta.handleSearchKey(ev) { // The find bar takes the keys while open (see StartSearch)
+		return true, true, false
+	}
+	switch {
+	case ev.Key == KeyRune && ev.Mod == 0:
+		// Insert the typed characters (UTF-8, possibly several runes) at the cursor
+		for _, r := range ev.Text {
+			ta.InsertChar(r)
+		}
+	case ev.Key == KeyBackspace && ev.Mod == 0:
+		ta.DeleteChar()
+	case ev.Key == KeyBackspace, ev.IsCtrl('w'): // Ctrl+Backspace or Ctrl+W - Delete the previous word
+		ta.DeleteWordBackward()
+	case ev.Key == KeyDelete:
+		ta.DeleteForward()
+	case ev.Key == KeyInsert: // Toggle overwrite mode
+		ta.ToggleOverwrite()
+	case ev.Key == KeyEnter: // Insert newline
+		ta.InsertChar('\n')
+	case ev.Key == KeyTab && ta.AcceptTab && !ta.ReadOnly: // Insert a tab (otherwise Tab moves focus)
+		ta.InsertTab()
+	case ev.IsCtrl('f'): // Open the find bar
+		ta.StartSearch()
+	case ev.Key == KeyHome || ev.Key == KeyEnd || ev.Key == KeyPageUp || ev.Key == KeyPageDown:
+		ta.ClearSelection()
+		switch {
+		case ev.Key == KeyHome && ev.Mod == ModCtrl: // Start of the text
+			ta.MoveCursorToStart()
+		case ev.Key == KeyEnd && ev.Mod == ModCtrl: // End of the text
+			ta.MoveCursorToEnd()
+		case ev.Key == KeyHome: // Start of the line
+			ta.MoveCursorLineStart()
+		case ev.Key == KeyEnd: // End of the line
+			ta.MoveCursorLineEnd()
+		case ev.Key == KeyPageUp:
+			ta.PageUp()
+		case ev.Key == KeyPageDown:
+			ta.PageDown()
+		}
+	case ev.Mod == ModShift: // Shift+Arrow - Extend the selection
+		switch ev.Key {
+		case KeyLeft:
+			ta.ExtendSelection(ta.MoveCursorLeft)
+		case KeyRight:
+			ta.ExtendSelection(ta.MoveCursorRight)
+		case KeyUp:
+			ta.ExtendSelection(ta.MoveCursorUp)
+		case KeyDown:
+			ta.ExtendSelection(ta.MoveCursorDown)
+		default:
+			return false, false, false
+		}
+	case ev.Mod == ModCtrl: // Ctrl+Arrow - Move by word
+		switch ev.Key {
+		case KeyLeft:
+			ta.ClearSelection()
+			ta.MoveWordLeft()
+		case KeyRight:
+			ta.ClearSelection()
+			ta.MoveWordRight()
+		default:
+			return false, false, false
+		}
+	case ev.Mod == 0:
+		switch ev.Key {
+		case KeyLeft:
+			ta.ClearSelection()
+			ta.MoveCursorLeft()
+		case KeyRight:
+			ta.ClearSelection()
+			ta.MoveCursorRight()
+		case KeyUp:
+			ta.ClearSelection()
+			ta.MoveCursorUp()
+		case KeyDown:
+			ta.ClearSelection()
+			ta.MoveCursorDown()
+		default:
+			return false, false, false
+		}
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
 // NeedsCursor implements CursorManager interface
 func (ta *TextArea) NeedsCursor() bool {
 	return ta.IsActive
@@ -1395,21 +4546,20 @@ func (ta *TextArea) GetCursorPosition() (int, int, bool) {
 	if !ta.NeedsCursor() {
 		return 0, 0, false
 	}
+	if ta.IsSearching() {
+		return ta.searchBox.GetCursorPosition() // In the find bar
+	}
 	// Check if the calculated cursor position is within the visible text area
-	visibleHeight := ta.Height - 1
+	visibleHeight := ta.textRenderHeight()
 	if visibleHeight < 0 {
 		visibleHeight = 0
 	}
-	textRenderWidth := ta.Width
-	if ta.needsScroll {
-		textRenderWidth--
-	}
-	if textRenderWidth < 0 {
-		textRenderWidth = 0
-	}
+	textRenderWidth := ta.textRenderWidth()
 
-	cursorScreenLine := ta.cursorLine - ta.viewTopLine
-	cursorScreenCol := ta.cursorCol // Simplified check for now
+	rows := ta.displayRows()
+	cursorRow, cursorRuneCol := ta.cursorRow(rows)
+	cursorScreenLine := cursorRow - ta.viewTopLine
+	cursorScreenCol := ta.cursorScreenCol(rows, cursorRow, cursorRuneCol) - ta.viewLeftCol
 
 	isCursorVisible := cursorScreenLine >= 0 && cursorScreenLine < visibleHeight &&
 		cursorScreenCol >= 0 && cursorScreenCol <= textRenderWidth // Allow cursor at end of width
@@ -1417,6 +4567,155 @@ func (ta *TextArea) GetCursorPosition() (int, int, bool) {
 	return ta.cursorAbsX, ta.cursorAbsY, isCursorVisible
 }
 
+// GetCursorShape implements CursorShaper interface
+func (ta *TextArea) GetCursorShape() CursorShape {
+	if ta.overwrite {
+		return CursorBlinkingBlock
+	}
+	return ta.CursorShape
+}
+
+// Bounds implements Bounded.
+func (ta *TextArea) Bounds() (int, int, int, int) {
+	return ta.X, ta.Y, ta.Width, ta.Height
+}
+
+// SetPosition implements Positioner.
+func (ta *TextArea) SetPosition(x, y int) {
+	ta.X, ta.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (ta *TextArea) SetSize(width, height int) {
+	ta.Width, ta.Height = width, height
+	ta.updateScrollState()
+}
+
+// SetEnabled implements Enabler.
+func (ta *TextArea) SetEnabled(enabled bool) {
+	ta.Disabled = !enabled
+	ta.hScrollBar.Disabled = !enabled
+}
+
+// IsEnabled implements Enabler.
+func (ta *TextArea) IsEnabled() bool {
+	return !ta.Disabled
+}
+
+// SetVisible implements Hider.
+func (ta *TextArea) SetVisible(visible bool) {
+	ta.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (ta *TextArea) IsVisible() bool {
+	return !ta.Hidden
+}
+
+// --- Selection Methods ---
+
+// selectionRange returns the selection bounds in document order.
+// ok is false if there is no (non-empty) selection.
+func (ta *TextArea) selectionRange() (startLine, startCol, endLine, endCol int, ok bool) {
+	if !ta.hasSelection {
+		return 0, 0, 0, 0, false
+	}
+	startLine, startCol = ta.selStartLine, ta.selStartCol
+	endLine, endCol = ta.selEndLine, ta.selEndCol
+	if endLine < startLine || (endLine == startLine && endCol < startCol) {
+		startLine, startCol, endLine, endCol = endLine, endCol, startLine, startCol
+	}
+	if startLine == endLine && startCol == endCol {
+		return 0, 0, 0, 0, false
+	}
+	return startLine, startCol, endLine, endCol, true
+}
+
+// isSelected reports whether the rune at line/col is inside the selection.
+func (ta *TextArea) isSelected(line, col int) bool {
+	startLine, startCol, endLine, endCol, ok := ta.selectionRange()
+	if !ok || line < startLine || line > endLine {
+		return false
+	}
+	if line == startLine && col < startCol {
+		return false
+	}
+	if line == endLine && col >= endCol {
+		return false
+	}
+	return true
+}
+
+// ExtendSelection runs move (e.g. ta.MoveCursorLeft) and extends the selection to
+// the new cursor position, starting a selection at the old position if needed.
+// WindowActions uses it for Shift+Arrow keys.
+func (ta *TextArea) ExtendSelection(move func()) {
+	if !ta.hasSelection {
+		ta.hasSelection = true
+		ta.selStartLine, ta.selStartCol = ta.cursorLine, ta.cursorCol
+	}
+	move()
+	ta.selEndLine, ta.selEndCol = ta.cursorLine, ta.cursorCol
+}
+
+// HasSelection reports whether any text is selected.
+func (ta *TextArea) HasSelection() bool {
+	_, _, _, _, ok := ta.selectionRange()
+	return ok
+}
+
+// ClearSelection removes the selection without changing the text.
+func (ta *TextArea) ClearSelection() {
+	ta.hasSelection = false
+}
+
+// GetSelectedText returns the selected text, with lines joined by newlines.
+func (ta *TextArea) GetSelectedText() string {
+	startLine, startCol, endLine, endCol, ok := ta.selectionRange()
+	if !ok {
+		return ""
+	}
+	if startLine == endLine {
+		return string([]rune(ta.Lines[startLine])[startCol:endCol])
+	}
+	parts := []string{string([]rune(ta.Lines[startLine])[startCol:])}
+	parts = append(parts, ta.Lines[startLine+1:endLine]...)
+	parts = append(parts, string([]rune(ta.Lines[endLine])[:endCol]))
+	return strings.Join(parts, "\n")
+}
+
+// CopySelection returns the selected text so the app can keep it (e.g. as a clipboard).
+// The selection is left in place.
+func (ta *TextArea) CopySelection() string {
+	return ta.GetSelectedText()
+}
+
+// DeleteSelection removes the selected text and puts the cursor where it began.
+// It returns false if nothing was selected or the text area is ReadOnly.
+func (ta *TextArea) DeleteSelection() bool {
+	if ta.ReadOnly {
+		return false // Keep the selection for copying
+	}
+	startLine, startCol, endLine, endCol, ok := ta.selectionRange()
+	ta.hasSelection = false
+	if !ok {
+		return false
+	}
+
+	before := string([]rune(ta.Lines[startLine])[:startCol])
+	after := string([]rune(ta.Lines[endLine])[endCol:])
+	ta.Lines = append(ta.Lines[:startLine+1], ta.Lines[endLine+1:]...)
+	ta.Lines[startLine] = before + after
+	ta.cursorLine = startLine
+	ta.cursorCol = startCol
+
+	ta.clampCursorCol()
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
+	return true
+}
+
 // --- Text Manipulation Methods ---
 
 // clampCursorCol ensures cursor column is valid for the current line.
@@ -1446,17 +4745,21 @@ func (ta *TextArea) clampCursorCol() {
 	}
 }
 
-// InsertChar inserts a rune at the cursor position.
+// InsertChar inserts a rune at the cursor position, replacing any selection. In
+// overwrite mode (see ToggleOverwrite) it replaces the character under the cursor
+// instead, except for newlines.
 func (ta *TextArea) InsertChar(r rune) {
-	if ta.IsActive {
-		if ta.maxChars > 0 && ta.charCount >= ta.maxChars && r != '\n' {
-			return
-		}
+	if ta.IsActive && !ta.ReadOnly {
+		hadSelection := ta.DeleteSelection()
 		if ta.cursorLine < 0 || ta.cursorLine >= len(ta.Lines) {
 			ta.clampCursorCol()
 		}
-
 		currentLineRunes := []rune(ta.Lines[ta.cursorLine])
+		replace := ta.overwrite && !hadSelection && r != '\n' && ta.cursorCol < len(currentLineRunes)
+		if ta.maxChars > 0 && ta.charCount >= ta.maxChars && r != '\n' && !replace {
+			ta.limitReached = true // Show why typing stopped
+			return
+		}
 
 		if r == '\n' {
 			textAfterCursor := string(currentLineRunes[ta.cursorCol:])
@@ -1465,24 +4768,107 @@ func (ta *TextArea) InsertChar(r rune) {
 			ta.Lines = append(ta.Lines[:nextLineIndex], append([]string{textAfterCursor}, ta.Lines[nextLineIndex:]...)...)
 			ta.cursorLine = nextLineIndex
 			ta.cursorCol = 0
+		} else if replace {
+			currentLineRunes[ta.cursorCol] = r
+			ta.Lines[ta.cursorLine] = string(currentLineRunes)
+			ta.cursorCol++
 		} else {
 			newLine := string(currentLineRunes[:ta.cursorCol]) + string(r) + string(currentLineRunes[ta.cursorCol:])
 			ta.Lines[ta.cursorLine] = newLine
 			ta.cursorCol++
 		}
 
-		ta.clampCursorCol()
-		ta.calculateCounts()
-		ta.updateScrollState()
-		ta.ensureCursorVisible()
-	} else {
-		return // Ignore input if not active
+		ta.clampCursorCol()
+		ta.textChanged()
+		ta.updateScrollState()
+		ta.ensureCursorVisible()
+	} else {
+		return // Ignore input if not active
+	}
+}
+
+// ToggleOverwrite switches between inserting typed characters and overwriting the
+// ones under the cursor (Insert). The cursor is a block while overwriting.
+func (ta *TextArea) ToggleOverwrite() {
+	ta.overwrite = !ta.overwrite
+}
+
+// IsOverwrite reports whether typing overwrites the characters under the cursor.
+func (ta *TextArea) IsOverwrite() bool {
+	return ta.overwrite
+}
+
+// InsertTab inserts a tab at the cursor, replacing any selection; with
+// InsertTabAsSpaces it inserts spaces up to the next tab stop instead. WindowActions
+// calls it for Tab when AcceptTab is set.
+func (ta *TextArea) InsertTab() {
+	if !ta.InsertTabAsSpaces {
+		ta.InsertChar('\t')
+		return
+	}
+	if !ta.IsActive || ta.ReadOnly {
+		return // Ignore input if not active
+	}
+	ta.DeleteSelection()
+	rows := ta.displayRows()
+	row, col := ta.cursorRow(rows)
+	ta.Paste(strings.Repeat(" ", ta.charWidth('\t', ta.cursorScreenCol(rows, row, col))))
+}
+
+// Paste inserts pasted text at the cursor as a single edit, replacing the selection.
+// Line breaks (\n, \r\n or \r) start new lines, tabs become spaces and other control
+// characters are dropped. Text beyond the maxChars limit is cut off.
+func (ta *TextArea) Paste(text string) {
+	if !ta.IsActive || ta.ReadOnly {
+		return // Ignore input if not active
+	}
+	ta.DeleteSelection()
+	ta.clampCursorCol()
+
+	budget := -1 // Characters that may still be added (-1 = unlimited)
+	if ta.maxChars > 0 {
+		budget = ta.maxChars - ta.charCount
+	}
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text), "\n")
+	for i, line := range lines {
+		line = pasteLine(line)
+		if budget >= 0 {
+			runes := []rune(line)
+			if len(runes) > budget {
+				runes = runes[:budget]
+				ta.limitReached = true
+			}
+			budget -= len(runes)
+			line = string(runes)
+		}
+		lines[i] = line
+	}
+
+	// Splice the pasted lines in at the cursor
+	current := []rune(ta.Lines[ta.cursorLine])
+	before, after := string(current[:ta.cursorCol]), string(current[ta.cursorCol:])
+	last := len(lines) - 1
+	ta.cursorCol = len([]rune(lines[last]))
+	if last == 0 {
+		ta.cursorCol += len([]rune(before))
 	}
+	lines[0] = before + lines[0]
+	lines[last] += after
+	ta.Lines = append(ta.Lines[:ta.cursorLine], append(lines, ta.Lines[ta.cursorLine+1:]...)...)
+	ta.cursorLine += last
+
+	ta.clampCursorCol()
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
 }
 
-// DeleteChar deletes the character before the cursor (Backspace).
+// DeleteChar deletes the character before the cursor (Backspace), or the selection.
 func (ta *TextArea) DeleteChar() {
-	if ta.IsActive {
+	if ta.IsActive && !ta.ReadOnly {
+		if ta.DeleteSelection() {
+			return
+		}
 		if ta.cursorLine == 0 && ta.cursorCol == 0 {
 			return
 		}
@@ -1507,7 +4893,7 @@ func (ta *TextArea) DeleteChar() {
 		}
 
 		ta.clampCursorCol()
-		ta.calculateCounts()
+		ta.textChanged()
 		ta.updateScrollState()
 		ta.ensureCursorVisible()
 	} else {
@@ -1515,9 +4901,12 @@ func (ta *TextArea) DeleteChar() {
 	}
 }
 
-// DeleteForward deletes the character after the cursor (Delete).
+// DeleteForward deletes the character after the cursor (Delete), or the selection.
 func (ta *TextArea) DeleteForward() {
-	if ta.IsActive {
+	if ta.IsActive && !ta.ReadOnly {
+		if ta.DeleteSelection() {
+			return
+		}
 		if ta.cursorLine < 0 || ta.cursorLine >= len(ta.Lines) {
 			ta.clampCursorCol()
 		}
@@ -1540,7 +4929,7 @@ func (ta *TextArea) DeleteForward() {
 		}
 
 		ta.clampCursorCol()
-		ta.calculateCounts()
+		ta.textChanged()
 		ta.updateScrollState()
 		ta.ensureCursorVisible()
 	} else {
@@ -1582,8 +4971,12 @@ func (ta *TextArea) MoveCursorRight() {
 	ta.ensureCursorVisible()
 }
 
-// MoveCursorUp moves the cursor one line up.
+// MoveCursorUp moves the cursor one line up (one display row with WordWrap).
 func (ta *TextArea) MoveCursorUp() {
+	if ta.WordWrap {
+		ta.moveCursorRows(-1)
+		return
+	}
 	if ta.cursorLine > 0 {
 		ta.cursorLine--
 		ta.clampCursorCol()
@@ -1591,8 +4984,12 @@ func (ta *TextArea) MoveCursorUp() {
 	}
 }
 
-// MoveCursorDown moves the cursor one line down.
+// MoveCursorDown moves the cursor one line down (one display row with WordWrap).
 func (ta *TextArea) MoveCursorDown() {
+	if ta.WordWrap {
+		ta.moveCursorRows(1)
+		return
+	}
 	if ta.cursorLine < len(ta.Lines)-1 {
 		ta.cursorLine++
 		ta.clampCursorCol()
@@ -1600,6 +4997,106 @@ func (ta *TextArea) MoveCursorDown() {
 	}
 }
 
+// MoveWordLeft moves the cursor to the start of the previous word (Ctrl+Left),
+// or to the end of the previous line from the start of a line.
+func (ta *TextArea) MoveWordLeft() {
+	ta.clampCursorCol()
+	if ta.cursorCol == 0 {
+		ta.MoveCursorLeft()
+		return
+	}
+	ta.cursorCol = wordLeft([]rune(ta.Lines[ta.cursorLine]), ta.cursorCol)
+	ta.ensureCursorVisible()
+}
+
+// MoveWordRight moves the cursor to the start of the next word (Ctrl+Right),
+// or to the start of the next line from the end of a line.
+func (ta *TextArea) MoveWordRight() {
+	ta.clampCursorCol()
+	runes := []rune(ta.Lines[ta.cursorLine])
+	if ta.cursorCol >= len(runes) {
+		ta.MoveCursorRight()
+		return
+	}
+	ta.cursorCol = wordRight(runes, ta.cursorCol)
+	ta.ensureCursorVisible()
+}
+
+// DeleteWordBackward deletes the word before the cursor (Ctrl+W, Ctrl+Backspace),
+// or the selection. At the start of a line it joins the line to the previous one.
+func (ta *TextArea) DeleteWordBackward() {
+	if !ta.IsActive || ta.ReadOnly {
+		return // Ignore input if not active
+	}
+	if ta.DeleteSelection() {
+		return
+	}
+	ta.clampCursorCol()
+	if ta.cursorCol == 0 {
+		ta.DeleteChar()
+		return
+	}
+	runes := []rune(ta.Lines[ta.cursorLine])
+	start := wordLeft(runes, ta.cursorCol)
+	ta.Lines[ta.cursorLine] = string(runes[:start]) + string(runes[ta.cursorCol:])
+	ta.cursorCol = start
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
+}
+
+// MoveCursorLineStart moves the cursor to the start of its line (Home).
+func (ta *TextArea) MoveCursorLineStart() {
+	ta.clampCursorCol()
+	ta.cursorCol = 0
+	ta.ensureCursorVisible()
+}
+
+// MoveCursorLineEnd moves the cursor to the end of its line (End).
+func (ta *TextArea) MoveCursorLineEnd() {
+	ta.clampCursorCol()
+	ta.cursorCol = len([]rune(ta.Lines[ta.cursorLine]))
+	ta.ensureCursorVisible()
+}
+
+// MoveCursorToStart moves the cursor to the start of the text (Ctrl+Home).
+func (ta *TextArea) MoveCursorToStart() {
+	ta.cursorLine = 0
+	ta.cursorCol = 0
+	ta.clampCursorCol()
+	ta.ensureCursorVisible()
+}
+
+// MoveCursorToEnd moves the cursor to the end of the text (Ctrl+End).
+func (ta *TextArea) MoveCursorToEnd() {
+	ta.cursorLine = len(ta.Lines) - 1
+	ta.clampCursorCol()
+	ta.cursorCol = len([]rune(ta.Lines[ta.cursorLine]))
+	ta.ensureCursorVisible()
+}
+
+// PageUp moves the cursor up by the number of visible rows (PageUp).
+func (ta *TextArea) PageUp() {
+	for i := 0; i < ta.pageSize(); i++ {
+		ta.MoveCursorUp()
+	}
+}
+
+// PageDown moves the cursor down by the number of visible rows (PageDown).
+func (ta *TextArea) PageDown() {
+	for i := 0; i < ta.pageSize(); i++ {
+		ta.MoveCursorDown()
+	}
+}
+
+// pageSize returns the number of rows PageUp/PageDown move.
+func (ta *TextArea) pageSize() int {
+	if height := ta.textRenderHeight(); height > 1 {
+		return height
+	}
+	return 1
+}
+
 // MoveCursor is a general handler (can be used if input library provides deltas)
 func (ta *TextArea) MoveCursor(deltaLine, deltaCol int) {
 	targetLine := ta.cursorLine + deltaLine
@@ -1640,7 +5137,16 @@ func (ta *TextArea) SetText(text string) {
 	ta.cursorLine = 0
 	ta.cursorCol = 0
 	ta.viewTopLine = 0
-	ta.calculateCounts()
+	ta.viewLeftCol = 0
+	ta.hasSelection = false
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
+}
+
+// SetWordWrap turns word wrapping on or off and recalculates scrolling.
+func (ta *TextArea) SetWordWrap(wrap bool) {
+	ta.WordWrap = wrap
 	ta.updateScrollState()
 	ta.ensureCursorVisible()
 }
@@ -1663,11 +5169,36 @@ type MenuItem struct {
 	Width       int         // Width of this item
 	X, Y        int         // Position relative to parent menu
 	Parent      *Menu       // Reference to parent menu (nil for top-level items)
+	Shortcut    string      // Optional accelerator shown in submenus, e.g. "Ctrl+S" (see MenuBar.TriggerShortcut)
+	Disabled    bool        // Disabled items are drawn grey, skipped by selection and never run
+	Separator   bool        // Separator rows (see Menu.AddSeparator) group items and can't be selected
+	Checkable   bool        // Activating the item toggles Checked instead of running Action
+	Checked     bool        // Drawn with a ✓ (or ● for radio items) prefix
+	RadioGroup  string      // Checkable items sharing a group in the same menu are mutually exclusive
+
+	// OnToggle runs after a checkable item is toggled, with the new checked
+	// state (returns true to close menu). Action is used if OnToggle is nil.
+	OnToggle func(checked bool) bool
+}
+
+// selectable reports whether the item can be selected and activated.
+func (item *MenuItem) selectable() bool {
+	return !item.Disabled && !item.Separator
+}
+
+// WithShortcut sets the item's accelerator key, e.g. "Ctrl+S", "Alt+X" or "F5".
+// It returns the item to allow chaining after NewMenuItem.
+func (item *MenuItem) WithShortcut(shortcut string) *MenuItem {
+	item.Shortcut = shortcut
+	if item.Parent != nil {
+		item.Parent.recalculateSize() // Make room for the shortcut
+	}
+	return item
 }
 
 // NewMenuItem creates a new menu item with the given text and action
 func NewMenuItem(text string, color, activeColor string, action func() bool) *MenuItem {
-	displayWidth := getStringDisplayWidth(text)
+	displayWidth := DisplayWidth(text)
 	return &MenuItem{
 		Text:        text,
 		Color:       color,
@@ -1678,6 +5209,68 @@ func NewMenuItem(text string, color, activeColor string, action func() bool) *Me
 	}
 }
 
+// NewCheckMenuItem creates a checkable menu item, e.g. a "Show Line Numbers" view toggle.
+func NewCheckMenuItem(text string, color, activeColor string, checked bool, onToggle func(checked bool) bool) *MenuItem {
+	item := NewMenuItem(text, color, activeColor, nil)
+	item.Checkable = true
+	item.Checked = checked
+	item.OnToggle = onToggle
+	return item
+}
+
+// NewRadioMenuItem creates a checkable menu item that unchecks the other items of
+// group in its menu when checked.
+func NewRadioMenuItem(text, group string, color, activeColor string, checked bool, onToggle func(checked bool) bool) *MenuItem {
+	item := NewCheckMenuItem(text, color, activeColor, checked, onToggle)
+	item.RadioGroup = group
+	return item
+}
+
+// checkGlyph returns the prefix shown before a checkable item's text.
+func (item *MenuItem) checkGlyph() string {
+	switch {
+	case !item.Checked:
+		return " "
+	case item.RadioGroup != "":
+		return glyphs.radio
+	default:
+		return glyphs.check
+	}
+}
+
+// activate toggles a checkable item and runs its callback, returning true if the
+// menu should close.
+func (item *MenuItem) activate() bool {
+	if !item.Checkable {
+		if item.Action != nil {
+			return item.Action()
+		}
+		return false
+	}
+
+	if item.RadioGroup == "" {
+		item.Checked = !item.Checked
+	} else {
+		// Radio items stay checked; checking one unchecks the rest of its group
+		if item.Parent != nil {
+			for _, other := range item.Parent.Items {
+				if other.RadioGroup == item.RadioGroup {
+					other.Checked = false
+				}
+			}
+		}
+		item.Checked = true
+	}
+
+	if item.OnToggle != nil {
+		return item.OnToggle(item.Checked)
+	}
+	if item.Action != nil {
+		return item.Action()
+	}
+	return false
+}
+
 // Menu represents a menu containing menu items
 type Menu struct {
 	Items       []*MenuItem
@@ -1689,7 +5282,10 @@ type Menu struct {
 	SelectedIdx int    // Index of currently selected item
 	IsOpen      bool   // Whether this menu is currently open
 	IsTopLevel  bool   // Whether this is a top-level menu (in menu bar) or submenu
+	Shadow      bool   // Draws a drop shadow below and right of the submenu
+	ShadowColor string // Background color of the shadow (empty = dark gray)
 	zIndex      int    // Z-index for submenus
+	themed
 }
 
 // GetZIndex implements ZIndexer interface for Menu
@@ -1752,7 +5348,13 @@ func (m *Menu) recalculateSize() {
 		// Submenu width is based on the widest item plus borders
 		width := 0
 		for _, item := range m.Items {
-			displayWidth := getStringDisplayWidth(item.Text)
+			displayWidth := DisplayWidth(item.Text)
+			if m.hasCheckable() {
+				displayWidth += 2 // Check glyph and a space
+			}
+			if item.Shortcut != "" {
+				displayWidth += 2 + DisplayWidth(item.Shortcut) // Gap before the shortcut
+			}
 			if displayWidth+2 > width { // +2 for padding
 				width = displayWidth + 2
 			}
@@ -1762,6 +5364,21 @@ func (m *Menu) recalculateSize() {
 	}
 }
 
+// hasCheckable reports whether any item of the menu is checkable.
+func (m *Menu) hasCheckable() bool {
+	for _, item := range m.Items {
+		if item.Checkable {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSeparator adds a horizontal line between groups of items in a submenu.
+func (m *Menu) AddSeparator() {
+	m.AddItem(&MenuItem{Separator: true})
+}
+
 // AddSubMenu adds a submenu item to this menu
 func (m *Menu) AddSubMenu(text string, color, activeColor string) *Menu {
 	// Create the submenu
@@ -1788,9 +5405,15 @@ func (m *Menu) SelectNext() {
 		m.Items[m.SelectedIdx].IsActive = false
 	}
 
-	// Select next item
-	m.SelectedIdx = (m.SelectedIdx + 1) % len(m.Items)
-	m.Items[m.SelectedIdx].IsActive = true
+	// Select the next selectable item, wrapping around
+	for i := 0; i < len(m.Items); i++ {
+		m.SelectedIdx = (m.SelectedIdx + 1) % len(m.Items)
+		if m.Items[m.SelectedIdx].selectable() {
+			m.Items[m.SelectedIdx].IsActive = true
+			return
+		}
+	}
+	m.SelectedIdx = -1 // Nothing can be selected
 }
 
 // SelectPrevious selects the previous item in the menu
@@ -1804,12 +5427,27 @@ func (m *Menu) SelectPrevious() {
 		m.Items[m.SelectedIdx].IsActive = false
 	}
 
-	// Select previous item
-	m.SelectedIdx--
+	// Select the previous selectable item, wrapping around
 	if m.SelectedIdx < 0 {
-		m.SelectedIdx = len(m.Items) - 1
+		m.SelectedIdx = 0 // Start from the end
+	}
+	for i := 0; i < len(m.Items); i++ {
+		m.SelectedIdx = (m.SelectedIdx - 1 + len(m.Items)) % len(m.Items)
+		if m.Items[m.SelectedIdx].selectable() {
+			m.Items[m.SelectedIdx].IsActive = true
+			return
+		}
+	}
+	m.SelectedIdx = -1 // Nothing can be selected
+}
+
+// selectFirst selects the first selectable item (used when the menu opens).
+func (m *Menu) selectFirst() {
+	for _, item := range m.Items {
+		item.IsActive = false
 	}
-	m.Items[m.SelectedIdx].IsActive = true
+	m.SelectedIdx = -1
+	m.SelectNext()
 }
 
 // ActivateSelected activates the currently selected item
@@ -1819,8 +5457,8 @@ func (m *Menu) ActivateSelected() bool {
 	}
 
 	item := m.Items[m.SelectedIdx]
-	if item == nil {
-		return false
+	if item == nil || !item.selectable() {
+		return false // Disabled items and separators never run
 	}
 
 	// If item has submenu, open it
@@ -1837,19 +5475,12 @@ func (m *Menu) ActivateSelected() bool {
 		}
 
 		item.SubMenu.IsOpen = true
-		item.SubMenu.SelectedIdx = 0
-		if len(item.SubMenu.Items) > 0 {
-			item.SubMenu.Items[0].IsActive = true
-		}
+		item.SubMenu.selectFirst()
 		return false // Opening a submenu doesn't close menus
 	}
 
-	// Otherwise, execute the action if defined
-	if item.Action != nil {
-		return item.Action()
-	}
-
-	return false
+	// Otherwise, toggle the item and/or execute its action
+	return item.activate()
 }
 
 // CloseSubMenus recursively closes all open submenus
@@ -1893,40 +5524,65 @@ func (m *Menu) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 			// Render submenu if active
 			if item.SubMenu != nil && item.SubMenu.IsOpen {
-				item.SubMenu.Render(buffer, winX, winY, 0)
+				renderElement(item.SubMenu, m.windowTheme, buffer, winX, winY, 0)
 			}
 		}
 	} else {
 		// Render submenu with border
+		if m.Shadow {
+			renderShadow(buffer, absX, absY, m.Width, m.Height, m.ShadowColor)
+		}
+		box := frameBox("single")
 		buffer.WriteString(m.BorderColor)
 
 		// Top border
 		buffer.WriteString(MoveCursorCmd(absY, absX))
-		buffer.WriteString("┌" + strings.Repeat("─", m.Width-2) + "┐")
+		buffer.WriteString(box.TopLeft + strings.Repeat(box.Horizontal, m.Width-2) + box.TopRight)
 
 		// Menu items
+		checkable := m.hasCheckable() // Reserve a column for check glyphs
 		for i, item := range m.Items {
 			itemY := absY + i + 1
 
+			if item.Separator {
+				buffer.WriteString(MoveCursorCmd(itemY, absX))
+				buffer.WriteString(m.BorderColor)
+				buffer.WriteString(glyphs.teeLeft + strings.Repeat(box.Horizontal, m.Width-2) + glyphs.teeRight)
+				continue
+			}
+
 			// Left border
 			buffer.WriteString(MoveCursorCmd(itemY, absX))
-			buffer.WriteString("│")
+			buffer.WriteString(box.Vertical)
 
 			// Item text with appropriate color
-			if item.IsActive {
+			if item.Disabled {
+				buffer.WriteString(m.disabledColor())
+			} else if item.IsActive {
 				buffer.WriteString(item.ActiveColor)
 				buffer.WriteString(ReverseVideo())
 			} else {
 				buffer.WriteString(item.Color)
 			}
 
-			// Pad item text to fill menu width, using proper display width
-			displayWidth := getStringDisplayWidth(item.Text)
+			// Pad item text to fill menu width, using proper display width;
+			// the shortcut (if any) is right-aligned before the border
+			displayWidth := DisplayWidth(item.Text)
 			paddedText := " " + item.Text
+			if checkable {
+				paddedText = " " + item.checkGlyph() + " " + item.Text
+				displayWidth += 2
+			}
 			padding := m.Width - 3 - displayWidth
+			if item.Shortcut != "" {
+				padding -= DisplayWidth(item.Shortcut) + 1
+			}
 			if padding > 0 {
 				paddedText += strings.Repeat(" ", padding)
 			}
+			if item.Shortcut != "" {
+				paddedText += item.Shortcut + " "
+			}
 
 			buffer.WriteString(paddedText)
 			buffer.WriteString(colors.Reset)
@@ -1934,21 +5590,21 @@ func (m *Menu) Render(buffer *strings.Builder, winX, winY int, _ int) {
 			// Right border with submenu indicator if applicable
 			buffer.WriteString(m.BorderColor)
 			if item.SubMenu != nil {
-				buffer.WriteString("▶")
+				buffer.WriteString(glyphs.submenuArrow)
 			} else {
-				buffer.WriteString("│")
+				buffer.WriteString(box.Vertical)
 			}
 		}
 
 		// Bottom border
 		buffer.WriteString(MoveCursorCmd(absY+m.Height-1, absX))
-		buffer.WriteString("└" + strings.Repeat("─", m.Width-2) + "┘")
+		buffer.WriteString(box.BottomLeft + strings.Repeat(box.Horizontal, m.Width-2) + box.BottomRight)
 		buffer.WriteString(colors.Reset)
 
 		// Render any open submenu
 		for _, item := range m.Items {
 			if item.SubMenu != nil && item.SubMenu.IsOpen {
-				item.SubMenu.Render(buffer, winX, winY, 0)
+				renderElement(item.SubMenu, m.windowTheme, buffer, winX, winY, 0)
 				break // Only one submenu can be open at a time
 			}
 		}
@@ -1964,6 +5620,7 @@ type MenuBar struct {
 	IsActive        bool   // Whether the menu is currently active
 	ActiveMenu      *Menu  // Currently active submenu (or nil if none)
 	zIndex          int    // Default z-index for menus
+	themed
 }
 
 // NewMenuBar creates a new menu bar
@@ -1995,8 +5652,7 @@ func (mb *MenuBar) AddSubMenu(text string, color, activeColor string) *Menu {
 func (mb *MenuBar) Activate() {
 	mb.IsActive = true
 	if mb.Menu.SelectedIdx < 0 && len(mb.Menu.Items) > 0 {
-		mb.Menu.SelectedIdx = 0
-		mb.Menu.Items[0].IsActive = true
+		mb.Menu.selectFirst()
 	}
 }
 
@@ -2015,6 +5671,38 @@ func (mb *MenuBar) Deactivate() {
 	mb.Menu.CloseSubMenus()
 }
 
+// HandleInput implements InputHandler: item accelerators (see MenuItem.Shortcut),
+// the arrow keys move through the menus, Enter activates the selected item and
+// Escape closes them. Other keys are ignored while the menu bar is focused,
+// except Tab, Shift+Tab and Ctrl+C, which are left to the window.
+func (mb *MenuBar) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !mb.IsActive || ev.Key == KeyMouse {
+		return false, false, false
+	}
+	if handled, quit := mb.TriggerShortcut(ev.Raw); handled { // Item accelerators like Ctrl+S
+		return true, true, quit
+	}
+	switch {
+	case ev.Key == KeyUp: // Move up in menu
+		mb.MoveUp()
+	case ev.Key == KeyDown: // Move down in menu or open submenu
+		mb.MoveDown()
+	case ev.Key == KeyRight: // Move right in menu bar or into submenu
+		mb.MoveRight()
+	case ev.Key == KeyLeft: // Move left in menu bar or back from submenu
+		mb.MoveLeft()
+	case ev.Key == KeyEnter: // Activate selected menu item
+		return true, true, mb.ActivateSelected()
+	case ev.Key == KeyEscape: // Deactivate menu
+		mb.Deactivate()
+	case ev.Key == KeyTab, ev.Key == KeyShiftTab, ev.IsCtrl('c'):
+		return false, false, false
+	default:
+		return true, false, false
+	}
+	return true, true, false
+}
+
 // NeedsCursor implements CursorManager interface
 func (mb *MenuBar) NeedsCursor() bool {
 	return false
@@ -2031,6 +5719,21 @@ func (mb *MenuBar) GetZIndex() int {
 	return 100
 }
 
+// Bounds implements Bounded.
+func (mb *MenuBar) Bounds() (int, int, int, int) {
+	return mb.X, mb.Y, mb.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (mb *MenuBar) SetPosition(x, y int) {
+	mb.X, mb.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (mb *MenuBar) SetSize(width, height int) {
+	mb.Width = width
+}
+
 // SelectNext selects the next menu item or delegates to active submenu
 func (mb *MenuBar) SelectNext() {
 	if !mb.IsActive {
@@ -2097,10 +5800,7 @@ func (mb *MenuBar) MoveDown() {
 			item.SubMenu.Y = mb.Y + 1 // Below top-level menu
 
 			item.SubMenu.IsOpen = true
-			item.SubMenu.SelectedIdx = 0
-			if len(item.SubMenu.Items) > 0 {
-				item.SubMenu.Items[0].IsActive = true
-			}
+			item.SubMenu.selectFirst()
 			mb.ActiveMenu = item.SubMenu
 		}
 	}
@@ -2124,6 +5824,63 @@ func (mb *MenuBar) MoveUp() {
 	}
 }
 
+// shortcutKeys maps named keys usable in MenuItem.Shortcut to their terminal sequences.
+var shortcutKeys = map[string]string{
+	"F1": "\x1bOP", "F2": "\x1bOQ", "F3": "\x1bOR", "F4": "\x1bOS",
+	"F5": "\x1b[15~", "F6": "\x1b[17~", "F7": "\x1b[18~", "F8": "\x1b[19~",
+	"F9": "\x1b[20~", "F10": "\x1b[21~", "F11": "\x1b[23~", "F12": "\x1b[24~",
+}
+
+// shortcutSequence returns the key sequence a shortcut like "Ctrl+S", "Alt+X" or
+// "F5" produces in a raw terminal, or "" if it isn't recognized.
+func shortcutSequence(shortcut string) string {
+	s := strings.TrimSpace(shortcut)
+	if seq, ok := shortcutKeys[strings.ToUpper(s)]; ok {
+		return seq
+	}
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.HasPrefix(upper, "CTRL+") && len(s) == 6:
+		if c := upper[5]; c >= 'A' && c <= 'Z' {
+			return string(rune(c - 'A' + 1))
+		}
+	case strings.HasPrefix(upper, "ALT+") && len(s) == 5:
+		return "\x1b" + strings.ToLower(s[4:])
+	}
+	return ""
+}
+
+// shortcutItem returns the item in menu (or its submenus) whose Shortcut produces key.
+func (m *Menu) shortcutItem(key []byte) *MenuItem {
+	for _, item := range m.Items {
+		if seq := shortcutSequence(item.Shortcut); seq != "" && sameKey([]byte(seq), key) {
+			return item
+		}
+		if item.SubMenu != nil {
+			if found := item.SubMenu.shortcutItem(key); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// TriggerShortcut activates the menu item whose Shortcut matches key (running its
+// Action, or toggling it if checkable), closing any open submenus first. It
+// reports whether an item matched, and whether its action asked to quit.
+func (mb *MenuBar) TriggerShortcut(key []byte) (handled, quit bool) {
+	if mb.Menu == nil {
+		return false, false
+	}
+	item := mb.Menu.shortcutItem(key)
+	if item == nil || item.Disabled || (item.Action == nil && !item.Checkable) {
+		return false, false
+	}
+	mb.Menu.CloseSubMenus()
+	mb.ActiveMenu = nil
+	return true, item.activate()
+}
+
 // ActivateSelected activates the currently selected menu item
 func (mb *MenuBar) ActivateSelected() bool {
 	if !mb.IsActive {
@@ -2179,6 +5936,16 @@ func (mb *MenuBar) ActivateSelected() bool {
 	return result
 }
 
+// SetActive activates the menu bar when it gets focus, and deactivates it
+// (closing its submenus) when it loses focus.
+func (mb *MenuBar) SetActive(active bool) {
+	if active {
+		mb.Activate()
+	} else {
+		mb.Deactivate()
+	}
+}
+
 // Render draws the menu bar
 func (mb *MenuBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	absX := winX + mb.X
@@ -2193,7 +5960,7 @@ func (mb *MenuBar) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	// Render the menu and all its active submenus
 
 	// Render only the top-level menu items here
-	mb.Menu.Render(buffer, winX, winY, 0)
+	renderElement(mb.Menu, mb.windowTheme, buffer, winX, winY, 0)
 }
 
 // --- Prompt ---
@@ -2235,14 +6002,32 @@ type Prompt struct {
 	Width        int
 	Height       int // Calculated based on content for dialog box
 	Style        PromptStyle
-	Color        string // Background color
-	BorderColor  string // Border color for dialog box
-	TitleColor   string // Title text color
-	MessageColor string // Message text color
-	IsActive     bool   // Whether the prompt is active
-	SelectedIdx  int    // Index of selected button
-	Modal        bool   // Whether the prompt blocks interaction with elements behind it
-	zIndex       int    // Default z-index for prompts
+	Color        string   // Background color
+	BorderColor  string   // Border color for dialog box
+	TitleColor   string   // Title text color
+	MessageColor string   // Message text color
+	IsActive     bool     // Whether the prompt is active
+	SelectedIdx  int      // Index of selected button
+	Modal        bool     // Whether the prompt blocks interaction with elements behind it
+	Input        *TextBox // Text entry field of input prompts (see NewInputPrompt), nil otherwise
+	Shadow       bool     // Draws a drop shadow below and right of dialog boxes
+	ShadowColor  string   // Background color of the shadow (empty = dark gray)
+
+	// MaxHeight limits the height of dialog boxes (0 = the terminal height). Longer
+	// messages scroll with Up/Down, which focus the message (MessageFocused) until
+	// Left/Right or Tab select a button again.
+	MaxHeight      int
+	MessageFocused bool
+	scrollBar      *ScrollBar // Shows the position in a scrolling message
+
+	// Timeout closes and removes the prompt this long after SetActive(true), then
+	// calls OnTimeout if set. It is checked by the window's ticker (see
+	// Window.SetTicker), so it never fires while no ticker is running.
+	Timeout   time.Duration
+	OnTimeout func()
+	shownAt   time.Time // When the prompt was last activated
+	zIndex    int       // Default z-index for prompts
+	themed
 }
 
 // NewSingleLinePrompt creates a single-line prompt
@@ -2267,31 +6052,13 @@ func NewSingleLinePrompt(title, message string, x, y, width int, titleColor, mes
 
 // NewDialogPrompt creates a dialog box prompt
 func NewDialogPrompt(title, message string, x, y, width int, color, borderColor, titleColor, messageColor string, buttons []*PromptButton) *Prompt {
-	// Calculate height based on message length and width
-	messageLines := 0
-	messageChars := len(message)
-	charsPerLine := width - 4 // Account for borders and padding
-	if charsPerLine < 1 {
-		charsPerLine = 1
-	}
-
-	// Simple word wrap calculation
-	messageLines = (messageChars + charsPerLine - 1) / charsPerLine
-	if messageLines < 1 {
-		messageLines = 1
-	}
-
-	// Height = title(1) + padding(1) + messageLines + padding(1) + buttons(1) + borders(2)
-	height := messageLines + 5
-
-	return &Prompt{
+	p := &Prompt{
 		Title:        title,
 		Message:      message,
 		Buttons:      buttons,
 		X:            x,
 		Y:            y,
 		Width:        width,
-		Height:       height,
 		Style:        DialogBoxPrompt,
 		Color:        color,
 		BorderColor:  borderColor,
@@ -2300,18 +6067,154 @@ func NewDialogPrompt(title, message string, x, y, width int, color, borderColor,
 		IsActive:     false,
 		SelectedIdx:  0,
 		Modal:        true, // Dialog prompts are modal by default
+		scrollBar:    NewScrollBar(0, 0, 2, 0, 0, colors.Gray, colors.BoldWhite, "prompt_scrollbar"),
 		zIndex:       1000, // Prompts should appear above everything
 	}
+	p.layoutMessage()
+	return p
+}
+
+// NewInputPrompt creates a dialog box prompt that asks the user to type a value.
+// The text field starts with defaultValue, which is replaced by the first key
+// typed. The OK button (or Enter) passes the entered text to onSubmit; Cancel runs
+// onCancel if set. Both callbacks return true to close the prompt.
+func NewInputPrompt(title, message, defaultValue string, x, y, width int, color, borderColor, titleColor, messageColor string, onSubmit func(value string) bool, onCancel func() bool) *Prompt {
+	p := NewDialogPrompt(title, message, x, y, width, color, borderColor, titleColor, messageColor, nil)
+	p.Input = NewTextBox(defaultValue, 0, 0, width-4, colors.BgBlack+colors.White, colors.BgBlue+colors.BoldWhite)
+	p.layoutMessage() // Make room for the text field
+
+	p.Buttons = []*PromptButton{
+		NewPromptButton("OK", colors.BoldGreen, colors.BgGreen+colors.BoldWhite, func() bool {
+			if onSubmit == nil {
+				return true
+			}
+			return onSubmit(p.Input.Text)
+		}),
+		NewPromptButton("Cancel", colors.BoldWhite, colors.BgWhite+colors.Black, func() bool {
+			if onCancel == nil {
+				return true
+			}
+			return onCancel()
+		}),
+	}
+	return p
+}
+
+// dialogPromptHeight returns the height of a dialog box prompt whose message wraps
+// to messageLines lines (see wrapPromptMessage), including the text field row of
+// input prompts.
+func dialogPromptHeight(messageLines int, hasInput bool) int {
+	// Height = title(1) + padding(1) + messageLines + padding(1) + buttons(1) + borders(2)
+	height := messageLines + 5
+	if hasInput {
+		height += 2 // Text field and padding below it
+	}
+	return height
+}
+
+// layoutMessage sizes a dialog box from its wrapped message, limited to MaxHeight,
+// and returns the message lines currently scrolled into view.
+func (p *Prompt) layoutMessage() []string {
+	lines := wrapPromptMessage(p.Message, p.Width)
+	hasInput := p.Input != nil
+
+	maxHeight := p.MaxHeight
+	if maxHeight <= 0 {
+		maxHeight = GetTerminalHeight() - 2 // Room for the window borders
+	}
+	visible := len(lines)
+	if overflow := dialogPromptHeight(visible, hasInput) - maxHeight; overflow > 0 {
+		visible -= overflow
+		if visible < 2 {
+			visible = 2 // Keep room for the scrollbar's track and thumb
+		}
+		if visible > len(lines) {
+			visible = len(lines)
+		}
+	}
+	p.Height = dialogPromptHeight(visible, hasInput)
+
+	if p.scrollBar == nil {
+		return lines // Prompts not built by NewDialogPrompt don't scroll
+	}
+	p.scrollBar.Height = visible
+	p.scrollBar.MaxValue = len(lines) - visible
+	p.scrollBar.SetValue(p.scrollBar.Value) // Clamp after a shorter message
+	p.scrollBar.Visible = p.scrollBar.MaxValue > 0
+	if !p.scrollBar.Visible {
+		p.MessageFocused = false
+	}
+	return lines[p.scrollBar.Value : p.scrollBar.Value+visible]
+}
+
+// MessageScrolls reports whether the message is too long to show at once.
+func (p *Prompt) MessageScrolls() bool {
+	return p.scrollBar != nil && p.scrollBar.Visible
+}
+
+// FocusMessage moves the keyboard focus to the message (for scrolling) or back to
+// the selected button.
+func (p *Prompt) FocusMessage(focused bool) {
+	p.MessageFocused = focused && p.MessageScrolls()
+	for i, button := range p.Buttons {
+		button.IsActive = i == p.SelectedIdx && p.IsActive && !p.MessageFocused
+	}
+}
+
+// ScrollUp scrolls a long message up by one line, focusing it.
+func (p *Prompt) ScrollUp() {
+	if p.MessageScrolls() {
+		p.FocusMessage(true)
+		p.scrollBar.SetValue(p.scrollBar.Value - 1)
+	}
+}
+
+// ScrollDown scrolls a long message down by one line, focusing it.
+func (p *Prompt) ScrollDown() {
+	if p.MessageScrolls() {
+		p.FocusMessage(true)
+		p.scrollBar.SetValue(p.scrollBar.Value + 1)
+	}
 }
 
-// SetActive activates or deactivates the prompt
+// wrapPromptMessage splits the message of a dialog box prompt of the given width
+// into the lines it is drawn with: at each embedded newline, then between words
+// to fit inside the borders and padding. It always returns at least one line.
+func wrapPromptMessage(message string, width int) []string {
+	messageWidth := width - 4 // Account for borders and padding
+	if messageWidth < 1 {
+		messageWidth = 1
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(message, "\n") {
+		for {
+			line, rest, _ := wrapLine(paragraph, messageWidth)
+			lines = append(lines, line)
+			if rest == "" {
+				break
+			}
+			paragraph = rest
+		}
+	}
+	return lines
+}
+
+// SetActive activates or deactivates the prompt. Activating it restarts its Timeout.
 func (p *Prompt) SetActive(active bool) {
 	p.IsActive = active
+	p.MessageFocused = false
+	if active {
+		p.shownAt = time.Now()
+	}
 
 	// Reset button state
 	for i, button := range p.Buttons {
 		button.IsActive = (i == p.SelectedIdx && active)
 	}
+	if p.Input != nil {
+		p.Input.IsActive = active // Keystrokes go to the text field while active
+	}
 }
 
 // SelectNext selects the next button
@@ -2367,14 +6270,77 @@ func (p *Prompt) ActivateSelected() bool {
 	return false
 }
 
+// HandleInput implements InputHandler: typing and the editing keys go to the
+// text field of an input prompt, Left/Right select a button, Up/Down scroll a long
+// message and Enter activates the selected button. In a modal prompt Tab moves
+// between the buttons and the message. Other keys are ignored, except Ctrl+C and,
+// in a non-modal prompt, Tab, Shift+Tab and Escape, which are left to the window.
+func (p *Prompt) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !p.IsActive || ev.Key == KeyMouse {
+		return false, false, false
+	}
+	if p.handleInputKey(ev) { // Typing and editing keys go to the text field of input prompts
+		return true, true, false
+	}
+	switch {
+	case ev.Key == KeyRight && p.Input == nil: // Select next button
+		p.FocusMessage(false)
+		p.SelectNext()
+	case ev.Key == KeyLeft && p.Input == nil: // Select previous button
+		p.FocusMessage(false)
+		p.SelectPrevious()
+	case ev.Key == KeyUp: // Scroll a long message
+		p.ScrollUp()
+	case ev.Key == KeyDown:
+		p.ScrollDown()
+	case ev.Key == KeyEnter: // Activate selected button
+		return true, true, p.ActivateSelected()
+	case ev.Key == KeyTab && p.MessageFocused: // From a long message to the first button
+		p.SelectedIdx = 0
+		p.FocusMessage(false)
+	case ev.Key == KeyTab && p.IsModal() && p.MessageScrolls() && p.SelectedIdx == len(p.Buttons)-1:
+		p.FocusMessage(true) // From the last button to a long message
+	case ev.Key == KeyTab && p.IsModal():
+		p.SelectNext()
+	case ev.Key == KeyTab, ev.Key == KeyShiftTab, ev.Key == KeyEscape:
+		return p.IsModal(), false, false // Non-modal: the window moves focus, or closes the prompt
+	case ev.IsCtrl('c'):
+		return false, false, false
+	default:
+		return true, false, false
+	}
+	return true, true, false
+}
+
 // NeedsCursor implements CursorManager interface
 func (p *Prompt) NeedsCursor() bool {
-	return false
+	return p.Input != nil && p.IsActive
 }
 
 // GetCursorPosition implements CursorManager interface
 func (p *Prompt) GetCursorPosition() (int, int, bool) {
-	return 0, 0, false
+	if p.Input == nil {
+		return 0, 0, false
+	}
+	return p.Input.GetCursorPosition()
+}
+
+// GetCursorShape implements CursorShaper.
+func (p *Prompt) GetCursorShape() CursorShape {
+	if p.Input == nil {
+		return CursorDefault
+	}
+	return p.Input.GetCursorShape()
+}
+
+// handleInputKey passes an editing key to the text field of an input prompt,
+// reporting whether it was used. The readline-style control keys are only used
+// with the window's ReadlineKeys set.
+func (p *Prompt) handleInputKey(ev InputEvent) bool {
+	if p.Input == nil || !p.IsActive {
+		return false
+	}
+	return (ev.readline && p.Input.handleReadlineKey(ev)) || p.Input.handleEditKey(ev)
 }
 
 // renderSingleLinePrompt renders the prompt as a single line
@@ -2435,12 +6401,20 @@ func (p *Prompt) renderSingleLinePrompt(buffer *strings.Builder, absX, absY int)
 
 // renderDialogPrompt renders the prompt as a dialog box
 func (p *Prompt) renderDialogPrompt(buffer *strings.Builder, absX, absY int) {
+	// Size the box from the wrapped message, which may have changed since creation
+	messageLines := p.layoutMessage()
+
+	if p.Shadow {
+		renderShadow(buffer, absX, absY, p.Width, p.Height, p.ShadowColor)
+	}
+
 	// Draw border
 	buffer.WriteString(p.BorderColor)
 
 	// Top border with title
 	buffer.WriteString(MoveCursorCmd(absY, absX))
-	buffer.WriteString("┌" + strings.Repeat("─", p.Width-2) + "┐")
+	box := frameBox("single")
+	buffer.WriteString(box.TopLeft + strings.Repeat(box.Horizontal, p.Width-2) + box.TopRight)
 
 	// Title (centered)
 	if p.Title != "" {
@@ -2456,50 +6430,34 @@ func (p *Prompt) renderDialogPrompt(buffer *strings.Builder, absX, absY int) {
 	// Sides and background
 	for i := 1; i < p.Height-1; i++ {
 		buffer.WriteString(MoveCursorCmd(absY+i, absX))
-		buffer.WriteString("│")
+		buffer.WriteString(box.Vertical)
 		buffer.WriteString(p.Color)
 		buffer.WriteString(strings.Repeat(" ", p.Width-2))
 		buffer.WriteString(p.BorderColor)
-		buffer.WriteString("│")
+		buffer.WriteString(box.Vertical)
 	}
 
 	// Bottom border
 	buffer.WriteString(MoveCursorCmd(absY+p.Height-1, absX))
-	buffer.WriteString("└" + strings.Repeat("─", p.Width-2) + "┘")
+	buffer.WriteString(box.BottomLeft + strings.Repeat(box.Horizontal, p.Width-2) + box.BottomRight)
 
-	// Message with simple word wrap
-	messageWidth := p.Width - 4 // Account for borders and padding
+	// Message, wrapped the same way the height was computed
 	buffer.WriteString(p.MessageColor)
+	for i, line := range messageLines {
+		buffer.WriteString(MoveCursorCmd(absY+2+i, absX+2)) // Start after title and top border
+		buffer.WriteString(line)
+	}
 
-	// Simple word wrap implementation
-	words := strings.Fields(p.Message)
-	lineY := absY + 2 // Start after title and top border
-	lineX := absX + 2 // Account for left border and padding
-	lineWidth := 0
-
-	for _, word := range words {
-		wordLen := len(word)
-
-		// Check if this word fits on the current line
-		if lineWidth > 0 && lineWidth+wordLen+1 > messageWidth {
-			// Word doesn't fit, move to next line
-			lineY++
-			lineWidth = 0
-			buffer.WriteString(MoveCursorCmd(lineY, lineX))
-		} else if lineWidth > 0 {
-			// Add space before word
-			buffer.WriteString(" ")
-			lineWidth++
-		}
-
-		// Position cursor if starting a new line
-		if lineWidth == 0 {
-			buffer.WriteString(MoveCursorCmd(lineY, lineX))
-		}
+	// Scrollbar in the right padding column of a long message
+	if p.MessageScrolls() {
+		p.scrollBar.IsActive = p.IsActive && p.MessageFocused
+		renderElement(p.scrollBar, p.windowTheme, buffer, absX+p.Width-2, absY+2, 0)
+	}
 
-		// Add the word
-		buffer.WriteString(word)
-		lineWidth += wordLen
+	// Text field of input prompts, two rows above the buttons
+	if p.Input != nil {
+		buffer.WriteString(colors.Reset)
+		renderElement(p.Input, p.windowTheme, buffer, absX+2, absY+p.Height-4, 0)
 	}
 
 	// Render buttons centered at bottom
@@ -2565,7 +6523,27 @@ func (p *Prompt) GetButton(index int) *PromptButton {
 	return nil
 }
 
+// timedOut reports whether the prompt is active and its Timeout has passed.
+func (p *Prompt) timedOut(now time.Time) bool {
+	return p.IsActive && p.Timeout > 0 && now.Sub(p.shownAt) >= p.Timeout
+}
+
 // IsModal returns whether this prompt is modal
 func (p *Prompt) IsModal() bool {
 	return p.Modal && p.IsActive
 }
+
+// Bounds implements Bounded.
+func (p *Prompt) Bounds() (int, int, int, int) {
+	return p.X, p.Y, p.Width, p.Height
+}
+
+// SetPosition implements Positioner.
+func (p *Prompt) SetPosition(x, y int) {
+	p.X, p.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (p *Prompt) SetSize(width, height int) {
+	p.Width, p.Height = width, height
+}
@@ -0,0 +1,526 @@
+package gui
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+	"window-go/colors"
+)
+
+// renderRows renders element alone at the top-left corner of a width x height
+// screen and returns the text of its rows, with trailing spaces removed.
+func renderRows(element UIElement, width, height int) []string {
+	var buffer strings.Builder
+	element.Render(&buffer, 0, 0, width)
+	grid := parseScreen(buffer.String(), width, height).plain()
+	rows := make([]string, len(grid))
+	for i, row := range grid {
+		rows[i] = strings.TrimRight(string(row), " ")
+	}
+	return rows
+}
+
+// typeKeys sends each key sequence to handler, as WindowActions would.
+func typeKeys(handler InputHandler, keys ...string) {
+	for _, key := range keys {
+		handler.HandleInput(decodeInput([]byte(key)))
+	}
+}
+
+func TestTextBoxMixedWidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		width   int
+		cursor  int // Byte offset of the cursor
+		visible string
+		column  int // Cursor column, relative to the box
+	}{
+		{"ASCII", "abc", 6, 3, "abc", 3},
+		{"wide runes fit", "ab中文😀", 6, len("ab中"), "ab中文", 4},
+		{"scrolled past wide runes", "ab中文😀", 6, len("ab中文😀"), "文😀", 4},
+		{"scrolled to a wide rune", "ab中文", 5, len("ab中文"), "中文", 4},
+		{"wide rune doesn't fit the last column", "a中文", 4, 0, "a中", 0},
+		{"emoji and CJK at the start", "😀中x", 6, len("😀"), "😀中x", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := NewTextBox(tt.text, 0, 0, tt.width, "", "")
+			tb.IsActive = true
+			tb.CursorPos = tt.cursor
+			rows := renderRows(tb, tt.width+2, 1)
+			if rows[0] != tt.visible {
+				t.Errorf("visible text = %q, want %q", rows[0], tt.visible)
+			}
+			if x, _, _ := tb.GetCursorPosition(); x != tt.column {
+				t.Errorf("cursor column = %d, want %d", x, tt.column)
+			}
+		})
+	}
+}
+
+func TestTextBoxTypingMixedWidth(t *testing.T) {
+	tb := NewTextBox("", 0, 0, 10, "", "")
+	tb.IsActive = true
+	typeKeys(tb, "a", "中", "b", "😀", "文")
+	if tb.Text != "a中b😀文" || tb.CursorPos != len(tb.Text) {
+		t.Fatalf("text = %q with cursor at %d, want %q with the cursor at the end", tb.Text, tb.CursorPos, "a中b😀文")
+	}
+	typeKeys(tb, "\x1b[D", "\x1b[D") // Left over 文 and 😀
+	if want := len("a中b"); tb.CursorPos != want {
+		t.Errorf("cursor after two Lefts = %d, want %d", tb.CursorPos, want)
+	}
+	renderRows(tb, 12, 1)
+	if x, _, _ := tb.GetCursorPosition(); x != 4 {
+		t.Errorf("cursor column = %d, want 4", x)
+	}
+	typeKeys(tb, "\x7f") // Backspace deletes b
+	if tb.Text != "a中😀文" {
+		t.Errorf("text after Backspace = %q, want %q", tb.Text, "a中😀文")
+	}
+}
+
+func TestTextAreaMixedWidth(t *testing.T) {
+	ta := NewTextArea("ab中文😀xy", 0, 0, 6, 3, 0, "", "", false, false)
+	ta.IsActive = true
+
+	steps := []struct {
+		keys    []string
+		visible string
+		column  int
+	}{
+		{nil, "ab中文", 0},
+		{[]string{"\x1b[C", "\x1b[C", "\x1b[C"}, "ab中文", 4}, // Right to after 中
+		{[]string{"\x1b[C"}, "b中文", 5},                      // After 文: scrolled one column
+		{[]string{"\x1b[F"}, " 😀xy", 5},                     // End: 文 is cut by the left edge
+		{[]string{"\x1b[D", "\x1b[D", "\x1b[D"}, " 😀xy", 1}, // Left to before 😀
+		{[]string{"\x1b[D"}, "文😀xy", 0},                     // Before 文: scrolled back to it
+		{[]string{"\x1b[H"}, "ab中文", 0},                     // Home
+	}
+	for i, step := range steps {
+		typeKeys(ta, step.keys...)
+		rows := renderRows(ta, 8, 3)
+		if rows[0] != step.visible {
+			t.Errorf("step %d: visible text = %q, want %q", i, rows[0], step.visible)
+		}
+		if x, _, _ := ta.GetCursorPosition(); x != step.column {
+			t.Errorf("step %d: cursor column = %d, want %d", i, x, step.column)
+		}
+	}
+}
+
+func TestDialogPromptBorderEnclosesMessageLines(t *testing.T) {
+	message := "First line\nA second line that is long enough to wrap\n\nFourth"
+	p := NewDialogPrompt("Note", message, 4, 1, 24, "", "", "", "", []*PromptButton{NewPromptButton("OK", "", "", nil)})
+	w := NewWindow("", "Main", 0, 0, 40, 18, "single", "", "", "", "")
+	w.AddElement(p)
+	grid := w.RenderPlain()
+
+	// The dialog's top border is the row with its title
+	top, left, right := -1, -1, -1
+	for row, runes := range grid {
+		if strings.Contains(string(runes), "Note") {
+			top = row
+			for col, r := range runes {
+				if r == '┌' && left == -1 {
+					left = col
+				} else if r == '┐' {
+					right = col
+				}
+			}
+			break
+		}
+	}
+	if top == -1 || left == -1 || right == -1 {
+		t.Fatalf("no dialog top border in\n%s", plainText(grid))
+	}
+	bottom := -1
+	for row := top + 1; row < len(grid); row++ {
+		if grid[row][left] == '└' {
+			bottom = row
+			break
+		}
+	}
+	if bottom == -1 || bottom-top-1 != p.Height-2 {
+		t.Fatalf("bottom border at row %d, want row %d (dialog height %d), in\n%s", bottom, top+p.Height-1, p.Height, plainText(grid))
+	}
+
+	// Every row between the borders is closed on both sides
+	var inside []string
+	for row := top + 1; row < bottom; row++ {
+		if grid[row][left] != '│' || grid[row][right] != '│' {
+			t.Errorf("row %d isn't enclosed: %q", row, string(grid[row]))
+		}
+		inside = append(inside, strings.TrimSpace(string(grid[row][left+1:right])))
+	}
+
+	// And they hold every wrapped message line, in order
+	lines := wrapPromptMessage(message, p.Width)
+	if len(lines) < 5 {
+		t.Fatalf("message wrapped into %q, want the long line wrapped", lines)
+	}
+	next := 0
+	for _, text := range inside {
+		if next < len(lines) && text == strings.TrimSpace(lines[next]) {
+			next++
+		}
+	}
+	if next != len(lines) {
+		t.Errorf("message line %q not found inside the border:\n%s", lines[next], strings.Join(inside, "\n"))
+	}
+}
+
+// plainText joins the rows of a RenderPlain grid.
+func plainText(grid [][]rune) string {
+	rows := make([]string, len(grid))
+	for i, row := range grid {
+		rows[i] = string(row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth int
+		line     string
+		rest     string
+		dropped  bool
+	}{
+		{"short", 10, "short", "", false},
+		{"hello world", 8, "hello", "world", true},
+		{"abcdefgh", 5, "abcde", "fgh", false},
+		{"ab中文", 3, "ab", "中文", false}, // 中 would straddle the boundary
+		{"ab中文", 4, "ab中", "文", false}, // 中 ends exactly at the boundary
+		{"中文字", 5, "中文", "字", false},   // One column left over
+		{"😀😀x", 3, "😀", "😀x", false},   // Emoji are two columns wide
+		{"ab 中文", 4, "ab", "中文", true}, // Break at the space before the wide rune
+		{"中文 ok", 4, "中文", "ok", true}, // Space right after the last rune that fits
+		{"中", 1, "中", "", false},       // Fallback: a wide rune in one column
+		{"中文", 1, "中", "文", false},     // Fallback, one rune per line
+		{"é中", 1, "é", "中", false},     // Multi-byte narrow rune
+	}
+	for _, tt := range tests {
+		line, rest, dropped := wrapLine(tt.text, tt.maxWidth)
+		if line != tt.line || rest != tt.rest || dropped != tt.dropped {
+			t.Errorf("wrapLine(%q, %d) = %q, %q, %v; want %q, %q, %v", tt.text, tt.maxWidth, line, rest, dropped, tt.line, tt.rest, tt.dropped)
+		}
+		if !utf8.ValidString(line) || !utf8.ValidString(rest) {
+			t.Errorf("wrapLine(%q, %d) split a rune: %q, %q", tt.text, tt.maxWidth, line, rest)
+		}
+	}
+}
+
+func TestLabelWrapsWideRunes(t *testing.T) {
+	label := NewLabel("ab中文😀 x", 0, 0, "")
+	label.WrapWidth = 4
+	rows := renderRows(label, 10, 4)
+	want := []string{"ab中", "文😀", "x", ""}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+		if width := DisplayWidth(rows[i]); width > label.WrapWidth {
+			t.Errorf("row %d is %d columns wide, more than WrapWidth %d", i, width, label.WrapWidth)
+		}
+	}
+}
+
+func TestButtonCentersWideText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		brackets bool
+		want     string
+	}{
+		{"CJK", "中文", 8, true, "[  中文  ]"},
+		{"CJK, odd space", "中文", 7, true, "[ 中文  ]"},
+		{"emoji", "OK😀", 8, true, "[  OK😀  ]"},
+		{"ASCII", "OK", 6, true, "[  OK  ]"},
+		{"CJK without brackets", "中文", 8, false, "  中文  "},
+		{"ASCII without brackets", "OK", 4, false, " OK "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewButton(tt.text, 0, 0, tt.width, "", "", nil)
+			b.ShowBrackets = tt.brackets
+			var buffer strings.Builder
+			b.Render(&buffer, 0, 0, 20)
+			got := StripANSI(buffer.String())
+			if got != tt.want {
+				t.Errorf("rendered %q, want %q", got, tt.want)
+			}
+			if _, _, width, _ := b.Bounds(); DisplayWidth(got) != width {
+				t.Errorf("rendered %d columns, Bounds reports %d", DisplayWidth(got), width)
+			}
+		})
+	}
+}
+
+func TestContainerTruncatesColoredRows(t *testing.T) {
+	defer colors.SetEnabled(colors.Enabled())
+	colors.SetEnabled(true) // Colors start disabled when stdout isn't a terminal, as under go test
+
+	rows := []string{
+		"\x1b[31mred text much wider than the container\x1b[0m",
+		"plain \x1b[1;32mgreen\x1b[0m and \x1b[38;2;255;128;0morange\x1b[0m words",
+		"中文\x1b[34m字幕\x1b[0m and more",
+		"short \x1b[35mrow\x1b[0m",
+	}
+	c := NewContainer(0, 0, 12, 6, rows)
+	var buffer strings.Builder
+	c.Render(&buffer, 0, 0, 20)
+
+	// Each row is drawn after a cursor move to its start
+	segments := cursorMove.Split(buffer.String(), -1)[1:]
+	want := []string{"red text muc", "plain green ", "中文字幕 and", "short row   "}
+	for i, text := range want {
+		segment := segments[i]
+		if got := StripANSI(segment); got != text {
+			t.Errorf("row %d shows %q, want %q", i, got, text)
+		}
+		if width := DisplayWidth(StripANSI(segment)); width != 12 {
+			t.Errorf("row %d is %d columns wide, want 12", i, width)
+		}
+		if !strings.HasSuffix(segment, colors.Reset) {
+			t.Errorf("row %d = %q doesn't end with a Reset", i, segment)
+		}
+	}
+}
+
+func TestSparklineNonFiniteValues(t *testing.T) {
+	levels := []rune(glyphs.sparkBars)
+	lowest, highest := string(levels[0]), string(levels[len(levels)-1])
+	tests := []struct {
+		name string
+		data []float64
+		want string
+	}{
+		{"NaN", []float64{1, math.NaN(), 2}, lowest + lowest + highest},
+		{"NaN first", []float64{math.NaN(), 1, 2}, lowest + lowest + highest},
+		{"infinities", []float64{math.Inf(1), 1, math.Inf(-1), 2}, lowest + lowest + lowest + highest},
+		{"all NaN", []float64{math.NaN(), math.NaN()}, lowest + lowest},
+		{"span wider than float64", []float64{-math.MaxFloat64, math.MaxFloat64}, lowest + highest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSparkline(0, 0, 10, nil, "")
+			s.SetData(tt.data)
+			var buffer strings.Builder
+			s.Render(&buffer, 0, 0, 10)
+			if got := StripANSI(buffer.String()); got != tt.want {
+				t.Errorf("rendered %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Downsampling averages only the finite values
+	s := NewSparkline(0, 0, 2, []float64{0, math.NaN(), 10, math.Inf(1)}, "")
+	var buffer strings.Builder
+	s.Render(&buffer, 0, 0, 2)
+	if got := StripANSI(buffer.String()); got != lowest+highest {
+		t.Errorf("downsampled = %q, want %q", got, lowest+highest)
+	}
+}
+
+func TestBarChartNonFiniteValues(t *testing.T) {
+	bc := NewBarChart(0, 0, 20, []Bar{
+		{Label: "a", Value: 5},
+		{Label: "b", Value: math.Inf(1)},
+		{Label: "c", Value: math.NaN()},
+		{Label: "d", Value: 10},
+		{Label: "e", Value: math.Inf(-1)},
+	}, "")
+	bc.ShowValues = true
+	rows := renderRows(bc, 20, 7)
+	want := []string{
+		"a │" + strings.Repeat(glyphs.fill, 7) + " 5",
+		"b │",
+		"c │",
+		"d │" + strings.Repeat(glyphs.fill, 14) + " 10",
+		"e │",
+		"  └" + strings.Repeat(glyphs.horizontal, 14),
+		"  0            10",
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	// A non-finite Max falls back to the largest value
+	bc.Max = math.Inf(1)
+	if rows := renderRows(bc, 20, 7); rows[3] != want[3] {
+		t.Errorf("with an infinite Max, row 3 = %q, want %q", rows[3], want[3])
+	}
+}
+
+func TestWrappedLabelBounds(t *testing.T) {
+	label := NewLabel("one two three", 0, 0, "")
+	label.WrapWidth = 8
+	if _, _, width, height := label.Bounds(); width != 8 || height != 2 {
+		t.Errorf("Bounds = %dx%d, want 8x2", width, height)
+	}
+	label.Text = ""
+	if _, _, _, height := label.Bounds(); height != 1 {
+		t.Errorf("empty label is %d rows tall, want 1", height)
+	}
+
+	// A VBox places the next child below every wrapped row
+	label.Text = "one two three"
+	w := NewWindow("", "Box", 0, 0, 20, 6, "single", "", "", "", "")
+	w.AddLayout(NewVBox(0, 0, 0, label, NewLabel("NEXT", 0, 0, "")))
+	grid := w.RenderPlain()
+	want := []string{"one two", "three", "NEXT"}
+	for i, text := range want {
+		if got := strings.TrimRight(string(grid[i+1][1:19]), " "); got != text {
+			t.Errorf("row %d = %q, want %q", i+1, got, text)
+		}
+	}
+}
+
+func TestButtonWithConfirm(t *testing.T) {
+	defer colors.SetEnabled(colors.Enabled())
+	colors.SetEnabled(true)
+
+	w := NewWindow("", "Confirm", 0, 0, 40, 12, "single", "", "", "", "")
+	w.Theme = &Theme{Background: "\x1b[44m", Border: "\x1b[36m", Title: "\x1b[1;36m", Content: "\x1b[37m", Accent: "\x1b[33m", ActiveBg: "\x1b[43m", ActiveFg: "\x1b[30m"}
+	var out bytes.Buffer
+	w.Output = &out
+	deleted := false
+	b := NewButton("Delete", 1, 1, 10, "", "", func() bool {
+		deleted = true
+		return false
+	}).WithConfirm(w, "Delete", "Really?")
+	w.AddElement(b)
+
+	// Pressing the button opens the dialog without leaving raw mode or clearing the screen
+	if w.runButtonAction(b, -1, nil) {
+		t.Fatal("opening the dialog quit the window")
+	}
+	if out.Len() != 0 {
+		t.Errorf("opening the dialog wrote %q to the terminal", out.String())
+	}
+	confirm := w.activeModal()
+	if confirm == nil {
+		t.Fatal("no confirmation dialog")
+	}
+	if confirm.Color != w.Theme.Background || confirm.BorderColor != w.Theme.Border || confirm.Buttons[0].ActiveColor != w.Theme.Active() {
+		t.Errorf("dialog colors %q, %q, %q don't come from the window theme", confirm.Color, confirm.BorderColor, confirm.Buttons[0].ActiveColor)
+	}
+
+	// "Yes" closes the dialog and leaves the action to the input loop
+	confirm.SelectedIdx = 0
+	confirm.ActivateSelected()
+	if deleted {
+		t.Error("the action ran from the dialog instead of the input loop")
+	}
+	if w.activeModal() != nil || w.pendingAction == nil {
+		t.Fatal("Yes didn't close the dialog and queue the action")
+	}
+	w.pendingAction()
+	if !deleted {
+		t.Error("the queued action isn't the button's")
+	}
+}
+
+func TestBuiltinElementsAreActivators(t *testing.T) {
+	elements := []UIElement{
+		NewButton("OK", 0, 0, 6, "", "", nil),
+		NewTextBox("", 0, 0, 5, "", ""),
+		NewCheckBox("c", 0, 0, false, "", ""),
+		NewToggleSwitch("t", 0, 0, false, "", "", ""),
+		NewRadioButton("r", "r", 0, 0, "", "", NewRadioGroup()),
+		NewScrollBar(0, 0, 5, 0, 0, "", "", ""),
+		NewContainer(0, 0, 5, 5, nil),
+		NewTable(0, 0, 10, 5, []string{"a"}, nil),
+		NewSlider(0, 0, 10, 0, 10, 1, 0, "", "", false),
+		NewDatePicker(0, 0, time.Now(), "", ""),
+		NewBreadcrumb(0, 0, 10, nil, "", ""),
+		NewLogView(0, 0, 10, 5, ""),
+		NewTabPanel(0, 0, 10, 5, "", "", ""),
+		NewAccordion(0, 0, 10, "", ""),
+		NewSplitPane(0, 0, 10, 5, Horizontal, NewSegment(0, 0, 5, 5, ""), NewSegment(0, 0, 5, 5, "")),
+		NewTextArea("", 0, 0, 10, 5, 0, "", "", false, false),
+		NewMenuBar(0, 0, 10, "", "", ""),
+		NewDialogPrompt("", "", 0, 0, 20, "", "", "", "", nil),
+	}
+	for _, element := range elements {
+		if _, ok := element.(Activator); !ok {
+			t.Errorf("%T doesn't implement Activator", element)
+		}
+	}
+}
+
+func TestFocusChangeCallsSetActive(t *testing.T) {
+	w := NewWindow("", "Focus", 0, 0, 40, 10, "single", "", "", "", "")
+	tb := NewTextBox("150", 1, 2, 5, "", "")
+	tb.ClampRange, tb.Min, tb.Max = true, 0, 100
+	menuBar := NewMenuBar(0, 0, 38, "", "", "")
+	menuBar.Menu.AddItem(NewMenuItem("File", "", "", nil))
+	w.AddElement(tb)
+	w.AddElement(menuBar)
+
+	w.FocusElement(tb)
+	if !tb.IsActive {
+		t.Fatal("focused text box isn't active")
+	}
+	w.FocusElement(menuBar)
+	if tb.IsActive || tb.Text != "100" {
+		t.Errorf("text box after losing focus: active %v, text %q; want inactive with its range applied", tb.IsActive, tb.Text)
+	}
+	if !menuBar.IsActive || menuBar.Menu.SelectedIdx != 0 {
+		t.Errorf("focused menu bar: active %v, selected %d; want active with its first item selected", menuBar.IsActive, menuBar.Menu.SelectedIdx)
+	}
+	w.FocusElement(tb)
+	if menuBar.IsActive || menuBar.Menu.SelectedIdx != -1 {
+		t.Errorf("menu bar after losing focus: active %v, selected %d; want it deactivated", menuBar.IsActive, menuBar.Menu.SelectedIdx)
+	}
+}
+
+func TestLogViewKeepsScrolledRowsWhenDroppingLines(t *testing.T) {
+	lv := NewLogView(0, 0, 11, 4, "Log") // 10 text columns, 3 rows
+	lv.MaxLines = 6
+	for i := 0; i < 6; i++ {
+		lv.AppendLine(strings.Repeat(strconv.Itoa(i), 15)) // Two rows each
+	}
+	lv.ScrollToTop()
+	lv.ScrollBy(5) // Rows 5-7: the second row of line 2, then line 3
+
+	// text returns the rows in view, without the scrollbar
+	text := func() string {
+		var rows []string
+		for _, row := range renderRows(lv, 11, 4)[1:] {
+			runes := []rune(row)
+			if len(runes) > 10 {
+				runes = runes[:10]
+			}
+			rows = append(rows, strings.TrimRight(string(runes), " "))
+		}
+		return strings.Join(rows, "|")
+	}
+	before := text()
+	if before != "22222|3333333333|33333" {
+		t.Fatalf("rows before = %q", before)
+	}
+
+	lv.AppendLine("new line") // Drops line 0 and its two rows
+	if lv.IsFollowing() {
+		t.Fatal("appending made the view follow new lines again")
+	}
+	if after := text(); after != before {
+		t.Errorf("rows after dropping a line = %q, want them unchanged from %q", after, before)
+	}
+
+	// Dropping the rows in view leaves the view at the top
+	lv.MaxLines = 1
+	lv.AppendLine("last")
+	if rows := text(); rows != "last||" {
+		t.Errorf("rows = %q, want the one line left at the top", rows)
+	}
+}
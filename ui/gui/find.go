@@ -0,0 +1,318 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"window-go/colors"
+)
+
+// Pos is a position in a TextArea: a line index and a rune column within it.
+type Pos struct {
+	Line, Col int
+}
+
+// before reports whether p comes before q in the text.
+func (p Pos) before(q Pos) bool {
+	return p.Line < q.Line || (p.Line == q.Line && p.Col < q.Col)
+}
+
+// findIn returns the start of every occurrence of query in lines, in text order.
+// Matches don't overlap and don't span lines.
+func findIn(lines []string, query string) []Pos {
+	if query == "" {
+		return nil
+	}
+	var matches []Pos
+	for i, line := range lines {
+		col, rest := 0, line
+		for {
+			idx := strings.Index(rest, query)
+			if idx < 0 {
+				break
+			}
+			col += utf8.RuneCountInString(rest[:idx])
+			matches = append(matches, Pos{Line: i, Col: col})
+			col += utf8.RuneCountInString(query)
+			rest = rest[idx+len(query):]
+		}
+	}
+	return matches
+}
+
+// Find highlights every occurrence of query in the text (in MatchColor) and returns
+// where they start. Matching is case-sensitive and within single lines. FindNext and
+// FindPrev move between the matches, which follow later edits; an empty query
+// clears them.
+func (ta *TextArea) Find(query string) []Pos {
+	ta.findQuery = query
+	ta.matches = findIn(ta.Lines, query)
+	ta.matchIndex = -1
+	return ta.matches
+}
+
+// refreshMatches finds the current query again after the text changed.
+func (ta *TextArea) refreshMatches() {
+	if ta.findQuery != "" {
+		ta.matches = findIn(ta.Lines, ta.findQuery)
+		ta.matchIndex = -1
+	}
+}
+
+// matchEnd returns the position just after match m.
+func (ta *TextArea) matchEnd(m Pos) Pos {
+	return Pos{Line: m.Line, Col: m.Col + utf8.RuneCountInString(ta.findQuery)}
+}
+
+// atMatch reports whether the selection is still the match FindNext/FindPrev moved to.
+func (ta *TextArea) atMatch() bool {
+	if ta.matchIndex < 0 || ta.matchIndex >= len(ta.matches) || !ta.hasSelection {
+		return false
+	}
+	m, end := ta.matches[ta.matchIndex], ta.matchEnd(ta.matches[ta.matchIndex])
+	return ta.selStartLine == m.Line && ta.selStartCol == m.Col &&
+		ta.cursorLine == end.Line && ta.cursorCol == end.Col
+}
+
+// selectMatch selects match i and scrolls it into view, with the cursor at its end.
+func (ta *TextArea) selectMatch(i int) {
+	m, end := ta.matches[i], ta.matchEnd(ta.matches[i])
+	ta.matchIndex = i
+	ta.hasSelection = true
+	ta.selStartLine, ta.selStartCol = m.Line, m.Col
+	ta.cursorLine, ta.cursorCol = end.Line, end.Col
+	ta.selEndLine, ta.selEndCol = end.Line, end.Col
+	ta.clampCursorCol()
+	ta.ensureCursorVisible()
+}
+
+// FindNext selects the next match of the last Find after the cursor, wrapping
+// around to the first one. It reports whether there was a match to move to.
+func (ta *TextArea) FindNext() bool {
+	if len(ta.matches) == 0 {
+		return false
+	}
+	next := 0
+	if ta.atMatch() {
+		next = (ta.matchIndex + 1) % len(ta.matches)
+	} else {
+		cursor := Pos{Line: ta.cursorLine, Col: ta.cursorCol}
+		for i, m := range ta.matches {
+			if !m.before(cursor) {
+				next = i
+				break
+			}
+		}
+	}
+	ta.selectMatch(next)
+	return true
+}
+
+// FindPrev selects the previous match of the last Find before the cursor, wrapping
+// around to the last one. It reports whether there was a match to move to.
+func (ta *TextArea) FindPrev() bool {
+	if len(ta.matches) == 0 {
+		return false
+	}
+	prev := len(ta.matches) - 1
+	if ta.atMatch() {
+		prev = (ta.matchIndex - 1 + len(ta.matches)) % len(ta.matches)
+	} else {
+		cursor := Pos{Line: ta.cursorLine, Col: ta.cursorCol}
+		for i := len(ta.matches) - 1; i >= 0; i-- {
+			if ta.matches[i].before(cursor) {
+				prev = i
+				break
+			}
+		}
+	}
+	ta.selectMatch(prev)
+	return true
+}
+
+// isMatch reports whether the rune at line/col is inside a match of the last Find.
+func (ta *TextArea) isMatch(line, col int) bool {
+	if len(ta.matches) == 0 {
+		return false
+	}
+	at := Pos{Line: line, Col: col}
+	// The last match starting at or before the rune
+	i := sort.Search(len(ta.matches), func(i int) bool { return at.before(ta.matches[i]) }) - 1
+	return i >= 0 && ta.matches[i].Line == line && col < ta.matchEnd(ta.matches[i]).Col
+}
+
+// Replace replaces the first occurrence of old at or after the cursor (or the
+// selection, so a match selected by FindNext is replaced), wrapping around to the
+// start, and puts the cursor after the new text. Like Find it matches within single
+// lines; new may contain line breaks. It reports whether anything was replaced, and
+// refuses replacements that would exceed the maxChars limit.
+func (ta *TextArea) Replace(old, new string) bool {
+	matches := findIn(ta.Lines, old)
+	if len(matches) == 0 {
+		return false
+	}
+	if ta.maxChars > 0 && ta.charCount-utf8.RuneCountInString(old)+utf8.RuneCountInString(new) > ta.maxChars {
+		ta.limitReached = true
+		return false
+	}
+	from := Pos{Line: ta.cursorLine, Col: ta.cursorCol}
+	if startLine, startCol, _, _, ok := ta.selectionRange(); ok {
+		from = Pos{Line: startLine, Col: startCol}
+	}
+	m := matches[0]
+	for _, candidate := range matches {
+		if !candidate.before(from) {
+			m = candidate
+			break
+		}
+	}
+
+	runes := []rune(ta.Lines[m.Line])
+	before := string(runes[:m.Col])
+	after := string(runes[m.Col+utf8.RuneCountInString(old):])
+	lines := strings.Split(before+new+after, "\n")
+	ta.Lines = append(ta.Lines[:m.Line], append(lines, ta.Lines[m.Line+1:]...)...)
+	last := len(lines) - 1
+	ta.cursorLine = m.Line + last
+	ta.cursorCol = len([]rune(lines[last])) - len([]rune(after))
+
+	ta.hasSelection = false
+	ta.clampCursorCol()
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
+	return true
+}
+
+// ReplaceAll replaces every occurrence of old (within single lines, like Find) with
+// new and returns how many were replaced. Nothing is replaced if the result would
+// exceed the maxChars limit.
+func (ta *TextArea) ReplaceAll(old, new string) int {
+	count := len(findIn(ta.Lines, old))
+	if count == 0 {
+		return 0
+	}
+	if ta.maxChars > 0 && ta.charCount+count*(utf8.RuneCountInString(new)-utf8.RuneCountInString(old)) > ta.maxChars {
+		ta.limitReached = true
+		return 0
+	}
+	lines := make([]string, len(ta.Lines))
+	for i, line := range ta.Lines {
+		lines[i] = strings.ReplaceAll(line, old, new)
+	}
+	ta.Lines = strings.Split(strings.Join(lines, "\n"), "\n") // new may add lines
+
+	ta.hasSelection = false
+	ta.clampCursorCol()
+	ta.textChanged()
+	ta.updateScrollState()
+	ta.ensureCursorVisible()
+	return count
+}
+
+// --- Find bar ---
+
+// StartSearch opens the find bar on the status line (Ctrl+F in WindowActions).
+// While it is open, typing searches as you type, Enter or Down selects the next
+// match, Up the previous one, and Escape or Ctrl+F closes it. The last query is
+// shown again, and replaced by the first key typed.
+func (ta *TextArea) StartSearch() {
+	if ta.searchBox == nil {
+		ta.searchBox = NewTextBox("", 0, 0, 0, colors.White, "")
+	}
+	ta.searchBox.IsActive = true
+	ta.searchBox.IsPristine = true
+	ta.searchBox.CursorPos = len(ta.searchBox.Text)
+	ta.searchBox.CursorShape = ta.CursorShape
+	ta.Find(ta.searchBox.Text)
+}
+
+// StopSearch closes the find bar and clears the match highlighting. The last
+// match found stays selected.
+func (ta *TextArea) StopSearch() {
+	if ta.searchBox != nil {
+		ta.searchBox.IsActive = false
+	}
+	ta.Find("")
+}
+
+// IsSearching reports whether the find bar is open.
+func (ta *TextArea) IsSearching() bool {
+	return ta.searchBox != nil && ta.searchBox.IsActive
+}
+
+// handleSearchKey applies a key to the open find bar. Every key but Tab, Shift+Tab
+// and Ctrl+C is taken, so none of them edits the text while searching.
+func (ta *TextArea) handleSearchKey(ev InputEvent) bool {
+	switch {
+	case ev.Key == KeyTab, ev.Key == KeyShiftTab, ev.IsCtrl('c'):
+		return false
+	case ev.Key == KeyEscape, ev.IsCtrl('f'):
+		ta.StopSearch()
+	case ev.Key == KeyEnter, ev.Key == KeyDown:
+		ta.FindNext()
+	case ev.Key == KeyUp:
+		ta.FindPrev()
+	default:
+		if (ev.readline && ta.searchBox.handleReadlineKey(ev)) || ta.searchBox.handleEditKey(ev) {
+			ta.searchChanged()
+		}
+	}
+	return true
+}
+
+// searchPaste inserts pasted text into the open find bar.
+func (ta *TextArea) searchPaste(text string) {
+	ta.searchBox.Paste(text)
+	ta.searchChanged()
+}
+
+// searchChanged searches for the find bar's query if it changed, selecting the
+// first match from where the previous one began, so the selection stays put while
+// the typed query keeps matching.
+func (ta *TextArea) searchChanged() {
+	if ta.searchBox.Text == ta.findQuery {
+		return
+	}
+	if startLine, startCol, _, _, ok := ta.selectionRange(); ok {
+		ta.cursorLine, ta.cursorCol = startLine, startCol
+	}
+	ta.hasSelection = false
+	ta.Find(ta.searchBox.Text)
+	ta.FindNext()
+}
+
+// renderSearchBar draws the find bar on the status line at row absY: the query and
+// the position of the selected match among all matches.
+func (ta *TextArea) renderSearchBar(buffer *strings.Builder, absX, absY int) {
+	const label = "Find: "
+	status := ""
+	switch {
+	case ta.findQuery == "":
+	case len(ta.matches) == 0:
+		status = " No matches"
+	case ta.atMatch():
+		status = fmt.Sprintf(" %d/%d", ta.matchIndex+1, len(ta.matches))
+	default:
+		status = fmt.Sprintf(" %d matches", len(ta.matches))
+	}
+	boxWidth := ta.Width - len(label) - len(status)
+	if boxWidth < 1 {
+		status = ""
+		boxWidth = ta.Width - len(label)
+	}
+	if boxWidth < 1 {
+		boxWidth = 1
+	}
+
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(colors.Gray + label + colors.Reset)
+	ta.searchBox.Width = boxWidth
+	renderElement(ta.searchBox, ta.windowTheme, buffer, absX+len(label), absY, 0)
+	buffer.WriteString(colors.Reset)
+	if status != "" {
+		buffer.WriteString(MoveCursorCmd(absY, absX+len(label)+boxWidth))
+		buffer.WriteString(colors.Gray + status + colors.Reset)
+	}
+}
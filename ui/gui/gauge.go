@@ -0,0 +1,167 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"window-go/colors"
+)
+
+// Gauge shows a value on a bracketed meter for dashboards: a centered readout on
+// top, the meter, tick marks at the minimum, middle and maximum, and their labels.
+// Unlike a ProgressBar it has a Min, so it suits readings like temperatures, and
+// the meter changes color once the value reaches Threshold.
+//
+//	  CPU 72%
+//	[███████▓░░░]
+//	╵    ╵     ╵
+//	0%  50%  100%
+type Gauge struct {
+	X, Y           int     // Position relative to window content area
+	Width          int     // Width including the brackets
+	Min, Max       float64 // Range of the meter
+	Value          float64 // Current value
+	Label          string  // Shown before the value in the readout
+	Format         string  // fmt verb for the readout and tick labels ("%.0f" if empty), e.g. "%.0f%%"
+	Color          string  // Color of the filled part of the meter and the readout
+	UnfilledColor  string  // Color of the empty part of the meter
+	Threshold      float64 // Value from which ThresholdColor is used
+	ThresholdColor string  // Color at or past Threshold ("" = no threshold)
+	Hidden         bool    // Hidden gauges are not rendered
+	themed
+}
+
+// NewGauge creates a Gauge at (x, y), width cells wide, for values in [min, max].
+func NewGauge(x, y, width int, min, max, value float64, color string) *Gauge {
+	if max <= min {
+		max = min + 100 // Default range if invalid
+	}
+	g := &Gauge{X: x, Y: y, Width: width, Min: min, Max: max, Color: color, UnfilledColor: colors.Gray}
+	g.SetValue(value)
+	return g
+}
+
+// SetValue updates the gauge's value, clamping it between Min and Max. A NaN or
+// infinite value (e.g. a missing reading) shows as Min.
+func (g *Gauge) SetValue(value float64) {
+	if value < g.Min || math.IsNaN(value) || math.IsInf(value, 0) {
+		g.Value = g.Min
+	} else if value > g.Max {
+		g.Value = g.Max
+	} else {
+		g.Value = value
+	}
+}
+
+// format formats value for the readout and tick labels.
+func (g *Gauge) format(value float64) string {
+	format := g.Format
+	if format == "" {
+		format = "%.0f"
+	}
+	return fmt.Sprintf(format, value)
+}
+
+// fillColor returns the color of the meter and readout for the current value.
+func (g *Gauge) fillColor() string {
+	if g.ThresholdColor != "" && g.Value >= g.Threshold {
+		return g.ThresholdColor
+	}
+	return themeColor(g.Color, g.theme().Accent)
+}
+
+// Bounds implements Bounded.
+func (g *Gauge) Bounds() (int, int, int, int) {
+	return g.X, g.Y, g.Width, 4
+}
+
+// SetPosition implements Positioner.
+func (g *Gauge) SetPosition(x, y int) {
+	g.X, g.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always four rows.
+func (g *Gauge) SetSize(width, height int) {
+	g.Width = width
+}
+
+// SetVisible implements Hider.
+func (g *Gauge) SetVisible(visible bool) {
+	g.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (g *Gauge) IsVisible() bool {
+	return !g.Hidden
+}
+
+// Render draws the readout, the meter, the tick marks and the tick labels.
+func (g *Gauge) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + g.X
+	absY := winY + g.Y
+	trackWidth := g.Width - 2 // Inside the brackets
+	if trackWidth < 1 {
+		return
+	}
+	fillColor := g.fillColor()
+	value := g.Value // Value may have been set directly, bypassing SetValue
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		value = g.Min
+	}
+
+	// Readout, centered over the meter
+	readout := g.format(value)
+	if g.Label != "" {
+		readout = g.Label + " " + readout
+	}
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(fillColor + alignText(truncateToWidth(readout, g.Width), g.Width, AlignCenter) + colors.Reset)
+
+	// Meter: whole cells filled, and a shaded cell for a filled part of at least half
+	fraction := 0.0
+	if g.Max > g.Min {
+		fraction = (value - g.Min) / (g.Max - g.Min)
+	}
+	if math.IsNaN(fraction) {
+		fraction = 0
+	}
+	cells := math.Max(0, math.Min(fraction, 1)) * float64(trackWidth)
+	filled := int(cells) // Within [0, trackWidth], so the Repeat counts below aren't negative
+	partial := filled < trackWidth && cells-float64(filled) >= 0.5
+	empty := trackWidth - filled
+	buffer.WriteString(MoveCursorCmd(absY+1, absX))
+	buffer.WriteString(colors.Reset + "[" + fillColor)
+	buffer.WriteString(strings.Repeat(glyphs.fill, filled))
+	if partial {
+		buffer.WriteString(glyphs.trackFilled)
+		empty--
+	}
+	buffer.WriteString(colors.Reset + g.UnfilledColor + strings.Repeat(glyphs.empty, empty))
+	buffer.WriteString(colors.Reset + "]")
+
+	// Tick marks under the first, middle and last cell of the meter, with labels
+	// centered under them (kept inside the gauge, the middle one dropped if crowded)
+	mid := 1 + (trackWidth-1)/2
+	ticks := []int{1, mid, trackWidth}
+	labels := []string{g.format(g.Min), g.format((g.Min + g.Max) / 2), g.format(g.Max)}
+	tickRow := []rune(strings.Repeat(" ", g.Width))
+	for _, col := range ticks {
+		tickRow[col] = []rune(glyphs.tick)[0]
+	}
+	buffer.WriteString(MoveCursorCmd(absY+2, absX))
+	buffer.WriteString(g.UnfilledColor + string(tickRow) + colors.Reset)
+
+	minLabel, maxLabel := labels[0], labels[2]
+	labelRow := alignText(truncateToWidth(minLabel, g.Width), g.Width, AlignLeft)
+	if maxWidth := DisplayWidth(maxLabel); DisplayWidth(minLabel)+1+maxWidth <= g.Width {
+		labelRow = alignText(minLabel, g.Width-maxWidth, AlignLeft) + maxLabel
+		midWidth := DisplayWidth(labels[1])
+		start := mid - midWidth/2
+		if start > DisplayWidth(minLabel) && start+midWidth < g.Width-maxWidth {
+			runes := []rune(labelRow)
+			labelRow = string(runes[:start]) + labels[1] + string(runes[start+len([]rune(labels[1])):])
+		}
+	}
+	buffer.WriteString(MoveCursorCmd(absY+3, absX))
+	buffer.WriteString(g.UnfilledColor + labelRow + colors.Reset)
+}
@@ -0,0 +1,273 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"window-go/colors"
+)
+
+// helpOverlay is the modal panel shown by Window.ShowHelpOverlay. It lists every
+// focusable element with its key hints and registered help text.
+type helpOverlay struct {
+	Lines       []string
+	Color       string // Background color of the panel
+	BorderColor string
+	TitleColor  string
+	TextColor   string
+	MaxHeight   int // Height available for the panel (set by the window before rendering)
+}
+
+// SetHelpText registers help text for element, shown next to it in the help overlay.
+// Passing an empty string removes the element's help text.
+func (w *Window) SetHelpText(element UIElement, text string) {
+	if text == "" {
+		delete(w.helpTexts, element)
+		return
+	}
+	if w.helpTexts == nil {
+		w.helpTexts = make(map[UIElement]string)
+	}
+	w.helpTexts[element] = text
+}
+
+// GetHelpText returns the help text registered for element, if any.
+func (w *Window) GetHelpText(element UIElement) string {
+	return w.helpTexts[element]
+}
+
+// ShowHelpOverlay opens a modal panel listing the window's key bindings and every
+// focusable element with its key hints and help text. It is bound to F1 in
+// WindowActions (unless F1 is bound with BindFunctionKey) and closed with Escape.
+func (w *Window) ShowHelpOverlay() {
+	lines := w.keyBindingLines()
+	if w.Resizable {
+		lines = append(lines, "Ctrl+R: resize (arrows change size, Enter/Escape to finish)")
+	}
+	if w.Movable {
+		lines = append(lines, "Ctrl+G: move (arrows move the window, Enter/Escape to finish)")
+	}
+	lines = append(lines, "")
+	for _, element := range w.focusableElements {
+		if !isFocusEnabled(element) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s - %s", elementName(element), keyHints(element)))
+		if text := w.helpTexts[element]; text != "" {
+			lines = append(lines, "    "+text)
+		}
+	}
+	lines = append(lines, "", "Press Escape to close")
+
+	w.helpOverlay = &helpOverlay{
+		Lines:       lines,
+		Color:       colors.BgBlack,
+		BorderColor: colors.BoldCyan,
+		TitleColor:  colors.BoldWhite,
+		TextColor:   colors.White,
+	}
+}
+
+// keyBindingLines lists the window's KeyMap and function key bindings for the help overlay.
+func (w *Window) keyBindingLines() []string {
+	km := w.keyMap
+	if km == nil {
+		km = DefaultKeyMap()
+	}
+	var lines []string
+	for _, binding := range []struct {
+		action KeyAction
+		label  string
+	}{
+		{ActionNextFocus, "next element"},
+		{ActionPrevFocus, "previous element"},
+		{ActionActivate, "activate"},
+		{ActionQuit, "quit"},
+	} {
+		var names []string
+		for _, seq := range km[binding.action] {
+			names = append(names, keyName(seq))
+		}
+		if len(names) > 0 {
+			lines = append(lines, strings.Join(names, " / ")+": "+binding.label)
+		}
+	}
+
+	var bound []string
+	for n := 1; n <= 12; n++ {
+		if w.functionKeys[n] != nil {
+			bound = append(bound, fmt.Sprintf("F%d", n))
+		}
+	}
+	if w.functionKeys[1] == nil {
+		lines = append(lines, "F1: help")
+	}
+	if len(bound) > 0 {
+		lines = append(lines, strings.Join(bound, " / ")+": application actions")
+	}
+	return lines
+}
+
+// HideHelpOverlay closes the help overlay if it is open.
+func (w *Window) HideHelpOverlay() {
+	w.helpOverlay = nil
+}
+
+// IsHelpOverlayVisible reports whether the help overlay is open.
+func (w *Window) IsHelpOverlayVisible() bool {
+	return w.helpOverlay != nil
+}
+
+// elementName returns a short, human-readable name for a focusable element.
+func elementName(element UIElement) string {
+	switch el := element.(type) {
+	case *Button:
+		return "[" + strings.TrimSpace(el.Text) + "]"
+	case *TextBox:
+		return "Text box"
+	case *CheckBox:
+		return "Checkbox '" + el.Label + "'"
+	case *ToggleSwitch:
+		return "Switch '" + el.Label + "'"
+	case *RadioButton:
+		return "Option '" + el.Label + "'"
+	case *ScrollBar:
+		return "Scroll bar"
+	case *Container:
+		return "List"
+	case *Table:
+		return "Table"
+	case *Slider:
+		return "Slider"
+	case *DatePicker:
+		return "Date picker"
+	case *Breadcrumb:
+		return "Breadcrumb"
+	case *LogView:
+		return "Log '" + el.Title + "'"
+	case *TabPanel:
+		return "Tabs"
+	case *Accordion:
+		return "Accordion"
+	case *SplitPane:
+		return "Split pane divider"
+	case *TextArea:
+		return "Text area"
+	case *MenuBar:
+		return "Menu bar"
+	case *Prompt:
+		return "Prompt '" + el.Title + "'"
+	}
+	return "Element"
+}
+
+// keyHints returns the keys an element responds to while focused.
+func keyHints(element UIElement) string {
+	switch el := element.(type) {
+	case *Button:
+		if el.Mnemonic != 0 {
+			return fmt.Sprintf("Enter or %c / Alt+%c: activate", unicode.ToUpper(el.Mnemonic), unicode.ToUpper(el.Mnemonic))
+		}
+		return "Enter: activate"
+	case *TextBox:
+		return "type to edit, Left/Right: move cursor, Ctrl+Left/Right: by word, Home/End: start/end, Ctrl+W: delete word"
+	case *CheckBox:
+		return "Enter: toggle"
+	case *ToggleSwitch:
+		return "Enter/Space: toggle"
+	case *RadioButton:
+		return "Enter: select"
+	case *ScrollBar:
+		if el.Orientation == Horizontal {
+			return "Left/Right: scroll, Home/End: start/end"
+		}
+		return "Up/Down/PageUp/PageDown: scroll, Home/End: top/bottom"
+	case *Container:
+		return "Up/Down/PageUp/PageDown/Home/End: highlight, Left/Right: scroll wide lines, /: filter, Enter: select"
+	case *Table:
+		return "Up/Down/PageUp/PageDown/Home/End: highlight row, Enter: select row"
+	case *Slider:
+		return "Left/Right: change value, Home/End: min/max"
+	case *Breadcrumb:
+		return "Left/Right/Home/End: highlight item, Enter: go to item"
+	case *LogView:
+		return "Up/Down/PageUp/PageDown/Home: scroll, End: follow new lines, Enter/Space: collapse/expand"
+	case *DatePicker:
+		return "Left/Right: day, Up/Down: week, PageUp/PageDown: month, Home/End: first/last day"
+	case *TabPanel:
+		return "Left/Right or Ctrl+Tab: switch tab, Enter: go to page"
+	case *Accordion:
+		return "Up/Down/Home/End: highlight section, Enter/Space: expand/collapse, Right/Left: expand/collapse"
+	case *SplitPane:
+		if el.Orientation == Vertical {
+			return "Up/Down: move divider, Home/End: top/bottom, Enter: go to panes"
+		}
+		return "Left/Right: move divider, Home/End: left/right, Enter: go to panes"
+	case *TextArea:
+		return "type to edit, arrows/PageUp/PageDown: move cursor, Ctrl+Left/Right: by word, Home/End: line start/end, Ctrl+Home/End: text start/end, Ctrl+W: delete word"
+	case *MenuBar:
+		return "arrows: navigate, Enter: choose, Escape: close"
+	case *Prompt:
+		return "Left/Right: choose button, Enter: confirm"
+	}
+	return ""
+}
+
+// Render draws the overlay centered in the given content area.
+func (h *helpOverlay) Render(buffer *strings.Builder, winX, winY int, width int) {
+	// Size the panel to its longest line, within the content width
+	panelWidth := DisplayWidth(" Help ") + 4
+	for _, line := range h.Lines {
+		if w := DisplayWidth(line) + 4; w > panelWidth {
+			panelWidth = w
+		}
+	}
+	if panelWidth > width {
+		panelWidth = width
+	}
+	textWidth := panelWidth - 4
+	if textWidth < 0 {
+		textWidth = 0
+	}
+	lines := h.Lines
+	if h.MaxHeight > 2 && len(lines) > h.MaxHeight-2 {
+		lines = lines[:h.MaxHeight-2] // Drop lines that would draw past the window border
+	}
+	panelHeight := len(lines) + 2
+
+	absX := winX + (width-panelWidth)/2
+	absY := winY
+
+	box := frameBox("single")
+	buffer.WriteString(h.BorderColor)
+	buffer.WriteString(h.Color)
+
+	// Top border with title
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(box.TopLeft)
+	buffer.WriteString(h.TitleColor + " Help " + h.BorderColor)
+	titleFill := panelWidth - 2 - DisplayWidth(" Help ")
+	if titleFill < 0 {
+		titleFill = 0
+	}
+	buffer.WriteString(strings.Repeat(box.Horizontal, titleFill))
+	buffer.WriteString(box.TopRight)
+
+	// Lines, truncated to the panel width
+	for i, line := range lines {
+		buffer.WriteString(MoveCursorCmd(absY+1+i, absX))
+		buffer.WriteString(box.Vertical)
+		buffer.WriteString(h.TextColor)
+		buffer.WriteString(" " + alignText(line, textWidth, AlignLeft) + " ")
+		buffer.WriteString(h.BorderColor)
+		buffer.WriteString(box.Vertical)
+	}
+
+	// Bottom border
+	buffer.WriteString(MoveCursorCmd(absY+panelHeight-1, absX))
+	buffer.WriteString(box.BottomLeft)
+	buffer.WriteString(strings.Repeat(box.Horizontal, panelWidth-2))
+	buffer.WriteString(box.BottomRight)
+
+	buffer.WriteString(colors.Reset)
+}
@@ -0,0 +1,495 @@
+package gui
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// inputBufferSize is the size of the buffer raw terminal input is read into.
+// It is large enough for a typical paste to arrive in a single read.
+const inputBufferSize = 4096
+
+// escapeTimeout is how long a key cut off at the end of a read waits for the rest
+// of it. A lone Escape can't be told apart from the start of a sequence until then.
+const escapeTimeout = 50 * time.Millisecond
+
+// keySplitter cuts raw terminal input into keys: escape sequences (arrows, mouse
+// reports, ...), control characters, runs of typed text and bracketed pastes.
+// A paste split across reads is held back until its end marker arrives, and an
+// escape sequence or character split across reads until the rest of it arrives
+// or escapeTimeout passes (see timeout and flush).
+type keySplitter struct {
+	pending []byte // Start of an unfinished bracketed paste, escape sequence or UTF-8 character
+}
+
+// split returns the keys in data, a chunk read from the terminal.
+func (ks *keySplitter) split(data []byte) [][]byte {
+	buf := append(ks.pending, data...)
+	ks.pending = nil
+
+	var keys [][]byte
+	for i := 0; i < len(buf); {
+		n := 1
+		switch {
+		case bytes.HasPrefix(buf[i:], []byte(pasteStart)):
+			end := bytes.Index(buf[i:], []byte(pasteEnd))
+			if end < 0 {
+				ks.pending = buf[i:] // Wait for the rest of the paste
+				return keys
+			}
+			n = end + len(pasteEnd)
+		case buf[i] == '\x1b':
+			if n = escapeKeyLen(buf, i); n == 0 {
+				ks.pending = buf[i:] // Wait for the rest of the sequence
+				return keys
+			}
+		case !utf8.FullRune(buf[i:]):
+			ks.pending = buf[i:] // Wait for the rest of the character
+			return keys
+		default:
+			// Printable text, kept together so a paste is typed in one go
+			for j := i; j < len(buf); {
+				r, size := utf8.DecodeRune(buf[j:])
+				if r == utf8.RuneError || !(unicode.IsPrint(r) || r == '\u200d') {
+					break
+				}
+				j += size
+				n = j - i
+			}
+		}
+		keys = append(keys, buf[i:i+n])
+		i += n
+	}
+	return keys
+}
+
+// waiting reports whether split holds back the start of a key, which escapeTimeout
+// applies to. A paste waits for its end however long it takes.
+func (ks *keySplitter) waiting() bool {
+	return len(ks.pending) > 0 && !bytes.HasPrefix(ks.pending, []byte(pasteStart))
+}
+
+// timeout returns a channel that fires when split should stop waiting for the
+// rest of the key it held back, or nil if it isn't waiting for one.
+func (ks *keySplitter) timeout() <-chan time.Time {
+	if !ks.waiting() {
+		return nil
+	}
+	return time.After(escapeTimeout)
+}
+
+// flush returns the key split held back, as it is, once its timeout has fired:
+// a lone Escape, or a sequence or character that was cut off for good.
+func (ks *keySplitter) flush() [][]byte {
+	if !ks.waiting() {
+		return nil
+	}
+	key := ks.pending
+	ks.pending = nil
+	return [][]byte{key}
+}
+
+// escapeKeyLen returns the length of the key starting with the escape at buf[i]:
+// a CSI sequence, an SS3 sequence (e.g. F1 as ESC O P), Alt+key, or a lone Escape.
+// It returns 0 if buf ends before the key does.
+func escapeKeyLen(buf []byte, i int) int {
+	if i+1 >= len(buf) {
+		return 0 // A lone Escape, or the start of a sequence
+	}
+	switch buf[i+1] {
+	case '[':
+		if i+2 < len(buf) && buf[i+2] == '[' { // Linux console F1–F5: \x1b[[A .. \x1b[[E
+			if i+3 < len(buf) {
+				return 4
+			}
+			return 0
+		}
+		for j := i + 2; j < len(buf); j++ {
+			if buf[j] >= 0x40 && buf[j] <= 0x7e { // Final byte of a CSI sequence
+				return j - i + 1
+			}
+		}
+		return 0
+	case 'O':
+		if i+2 < len(buf) {
+			return 3
+		}
+		return 0
+	case '\x1b':
+		return 1 // Escape pressed twice
+	}
+	return 2
+}
+
+// pastedText returns the text of a bracketed paste key.
+func pastedText(key []byte) (string, bool) {
+	s := string(key)
+	if !strings.HasPrefix(s, pasteStart) || !strings.HasSuffix(s, pasteEnd) {
+		return "", false
+	}
+	return s[len(pasteStart) : len(s)-len(pasteEnd)], true
+}
+
+// inputTarget returns the element keys go to: the active modal prompt, or the
+// focused element.
+func (w *Window) inputTarget() UIElement {
+	if modal := w.activeModal(); modal != nil {
+		return modal
+	}
+	return w.FocusedElement()
+}
+
+// takesTextInput reports whether the element keys go to takes printable keys as text.
+func (w *Window) takesTextInput() bool {
+	switch e := w.inputTarget().(type) {
+	case *TextBox:
+		return e.IsActive
+	case *TextArea:
+		return e.IsActive
+	case *Container:
+		return e.IsActive && e.IsFilterInputActive()
+	case *Prompt:
+		return e.IsActive && e.Input != nil
+	}
+	return false
+}
+
+// pasteLine returns pasted text for a single-line field: line breaks and tabs
+// become spaces and other control characters are dropped.
+func pasteLine(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if !unicode.IsPrint(r) && r != '\u200d' {
+			return -1
+		}
+		return r
+	}, strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+// handlePaste delivers the text of a bracketed paste to the element keys go to,
+// as a single edit (see TextBox.Paste and TextArea.Paste). Elements that don't
+// take text ignore it.
+func (w *Window) handlePaste(text string) {
+	switch e := w.inputTarget().(type) {
+	case *TextBox:
+		if !e.IsActive {
+			return
+		}
+		e.Paste(text)
+	case *TextArea:
+		if !e.IsActive {
+			return
+		}
+		if e.IsSearching() {
+			e.searchPaste(text)
+			return
+		}
+		e.Paste(text)
+	case *Prompt:
+		if !e.IsActive || e.Input == nil {
+			return
+		}
+		e.Input.Paste(text)
+	case *Container:
+		if !e.IsActive || !e.IsFilterInputActive() {
+			return
+		}
+		e.Filter(e.FilterQuery + pasteLine(text))
+	default:
+		return
+	}
+	w.Render()
+}
+
+// Key identifies the key of an InputEvent.
+type Key int
+
+const (
+	KeyNone      Key = iota // Not a key parseKey knows; see InputEvent.Raw
+	KeyRune                 // A printable character, or Ctrl/Alt+character; see InputEvent.Rune
+	KeyEnter                // Enter (\r)
+	KeyTab                  // Tab (\t)
+	KeyShiftTab             // Shift+Tab (\x1b[Z)
+	KeyEscape               // A lone Escape
+	KeyBackspace            // DEL, or ASCII BS with ModCtrl (Ctrl+Backspace)
+	KeyDelete
+	KeyInsert
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyMouse // A mouse report; see InputEvent.Mouse
+)
+
+// Modifiers are the modifier keys held with a key, as bits.
+type Modifiers int
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// InputEvent is one key or mouse report read by WindowActions, decoded. The
+// window passes it to the focused element if it implements InputHandler.
+type InputEvent struct {
+	Key      Key
+	Rune     rune        // Character of a KeyRune event (the lower case letter for Ctrl+letter)
+	Text     string      // Characters of a printable KeyRune event (several when typed at once)
+	Mod      Modifiers   // Modifier keys held with the key
+	Mouse    *MouseEvent // Report of a KeyMouse event
+	Raw      []byte      // Bytes read from the terminal, after the window's KeyMap
+	readline bool        // The window's ReadlineKeys setting, for the built-in text fields
+}
+
+// IsCtrl reports whether ev is Ctrl+letter, with letter given in lower case
+// (e.g. ev.IsCtrl('c') for Ctrl+C).
+func (ev InputEvent) IsCtrl(letter rune) bool {
+	return ev.Key == KeyRune && ev.Mod == ModCtrl && ev.Rune == letter
+}
+
+// decodeInput decodes key, one key read by WindowActions (see keySplitter).
+func decodeInput(key []byte) InputEvent {
+	ev := InputEvent{Raw: key}
+	if mouse, ok := parseMouseEvent(key); ok {
+		ev.Key, ev.Mouse = KeyMouse, &mouse
+		return ev
+	}
+	ev.Key, ev.Rune, ev.Mod = parseKey(key)
+	if text, ok := typedText(key); ok {
+		ev.Text = text
+	}
+	return ev
+}
+
+// finalKeys maps the final byte of CSI and SS3 sequences like \x1b[A, \x1bOH or
+// \x1b[1;5P to their keys.
+var finalKeys = map[byte]Key{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft,
+	'H': KeyHome, 'F': KeyEnd,
+	'P': KeyF1, 'Q': KeyF2, 'R': KeyF3, 'S': KeyF4,
+}
+
+// tildeKeys maps the number of CSI sequences ending in ~ (e.g. \x1b[5~, or \x1b[5;5~
+// with modifiers) to their keys. rxvt ends them in ^ for Ctrl, $ for Shift and @
+// for both.
+var tildeKeys = map[int]Key{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown, 7: KeyHome, 8: KeyEnd,
+	11: KeyF1, 12: KeyF2, 13: KeyF3, 14: KeyF4, 15: KeyF5,
+	17: KeyF6, 18: KeyF7, 19: KeyF8, 20: KeyF9, 21: KeyF10, 23: KeyF11, 24: KeyF12,
+}
+
+// parseKey decodes one key read from the terminal (see keySplitter): a control
+// character (Ctrl+letter is KeyRune with ModCtrl), typed text (KeyRune with its
+// first character), Alt+key, or a CSI or SS3 escape sequence for a named key,
+// with the modifiers xterm encodes in it. Anything else, including mouse reports
+// and pastes, is KeyNone.
+func parseKey(buf []byte) (Key, rune, Modifiers) {
+	switch {
+	case len(buf) == 0:
+		return KeyNone, 0, 0
+	case len(buf) == 1 && (buf[0] < ' ' || buf[0] == 127):
+		return controlKey(buf[0])
+	case buf[0] != '\x1b':
+		if text, ok := typedText(buf); ok {
+			r, _ := utf8.DecodeRuneInString(text)
+			return KeyRune, r, 0
+		}
+		return KeyNone, 0, 0
+	case len(buf) > 2 && buf[1] == '[':
+		return csiKey(string(buf[2:]))
+	case len(buf) == 3 && buf[1] == 'O': // SS3, e.g. \x1bOP for F1
+		if k, ok := finalKeys[buf[2]]; ok {
+			return k, 0, 0
+		}
+		return KeyNone, 0, 0
+	}
+	// Alt+key: Escape followed by the key
+	k, r, mod := parseKey(buf[1:])
+	if k == KeyNone || k == KeyEscape {
+		return KeyNone, 0, 0
+	}
+	return k, r, mod | ModAlt
+}
+
+// controlKey decodes a control character.
+func controlKey(b byte) (Key, rune, Modifiers) {
+	switch {
+	case b == '\r':
+		return KeyEnter, 0, 0
+	case b == '\t':
+		return KeyTab, 0, 0
+	case b == 27:
+		return KeyEscape, 0, 0
+	case b == 127:
+		return KeyBackspace, 0, 0
+	case b == 8: // Ctrl+Backspace on most terminals (also Ctrl+H)
+		return KeyBackspace, 0, ModCtrl
+	case b == 0: // Ctrl+Space
+		return KeyRune, ' ', ModCtrl
+	case b <= 26: // Ctrl+A .. Ctrl+Z
+		return KeyRune, rune('a' + b - 1), ModCtrl
+	}
+	return KeyRune, rune(b + 64), ModCtrl // Ctrl+\ ] ^ _
+}
+
+// csiKey decodes the CSI sequence \x1b[ seq: its parameters, separated by ';', and
+// final byte.
+func csiKey(seq string) (Key, rune, Modifiers) {
+	final := seq[len(seq)-1]
+	params := strings.Split(seq[:len(seq)-1], ";")
+	param := func(i int) int {
+		if i >= len(params) {
+			return 0
+		}
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+
+	switch {
+	case seq == "Z":
+		return KeyShiftTab, 0, 0
+	case len(seq) == 2 && seq[0] == '[' && final >= 'A' && final <= 'E': // Linux console F1-F5
+		return KeyF1 + Key(final-'A'), 0, 0
+	case final == 'u': // CSI u: code;modifiers
+		return codeKey(param(0), csiModifiers(param(1)))
+	case final == '~' && len(params) == 3 && param(0) == 27: // xterm modifyOtherKeys: 27;modifiers;code
+		return codeKey(param(2), csiModifiers(param(1)))
+	case final == '~' || final == '^' || final == '$' || final == '@':
+		k, ok := tildeKeys[param(0)]
+		if !ok {
+			return KeyNone, 0, 0
+		}
+		mod := csiModifiers(param(1))
+		switch final {
+		case '^':
+			mod |= ModCtrl
+		case '$':
+			mod |= ModShift
+		case '@':
+			mod |= ModCtrl | ModShift
+		}
+		return k, 0, mod
+	}
+	if k, ok := finalKeys[final]; ok && (len(params) == 1 && params[0] == "" || param(0) == 1) {
+		return k, 0, csiModifiers(param(1))
+	}
+	return KeyNone, 0, 0
+}
+
+// csiModifiers decodes the modifier parameter of a CSI sequence: 1 plus Shift (1),
+// Alt (2) and Ctrl (4), with Meta (8) taken as Alt.
+func csiModifiers(n int) Modifiers {
+	if n < 2 {
+		return 0
+	}
+	bits := n - 1
+	mod := Modifiers(bits) & (ModShift | ModAlt | ModCtrl)
+	if bits&8 != 0 {
+		mod |= ModAlt
+	}
+	return mod
+}
+
+// codeKey decodes a key given by its character code, as in the CSI u and
+// modifyOtherKeys encodings.
+func codeKey(code int, mod Modifiers) (Key, rune, Modifiers) {
+	switch {
+	case code == '\r':
+		return KeyEnter, 0, mod
+	case code == '\t':
+		return KeyTab, 0, mod
+	case code == 27:
+		return KeyEscape, 0, mod
+	case code == 127, code == 8:
+		return KeyBackspace, 0, mod
+	case code >= ' ' && unicode.IsPrint(rune(code)):
+		r := rune(code)
+		if mod&ModCtrl != 0 {
+			r = unicode.ToLower(r)
+		}
+		return KeyRune, r, mod
+	}
+	return KeyNone, 0, 0
+}
+
+// keyNames are the names keyName gives the keys that aren't characters.
+var keyNames = map[Key]string{
+	KeyEnter: "Enter", KeyTab: "Tab", KeyShiftTab: "Shift+Tab", KeyEscape: "Escape",
+	KeyBackspace: "Backspace", KeyDelete: "Delete", KeyInsert: "Insert",
+	KeyUp: "Up", KeyDown: "Down", KeyRight: "Right", KeyLeft: "Left",
+	KeyHome: "Home", KeyEnd: "End", KeyPageUp: "PageUp", KeyPageDown: "PageDown",
+}
+
+// keyName returns a readable name for a key sequence, like "Ctrl+C", "Shift+Tab" or
+// "F5", for listing key bindings.
+func keyName(seq string) string {
+	key, r, mod := parseKey([]byte(seq))
+	name, named := keyNames[key]
+	switch {
+	case key >= KeyF1 && key <= KeyF12:
+		name = "F" + strconv.Itoa(int(key-KeyF1)+1)
+	case key == KeyRune && r == ' ':
+		name = "Space"
+	case key == KeyRune && mod&ModCtrl != 0:
+		name = string(unicode.ToUpper(r))
+	case key == KeyRune:
+		name = string(r)
+	case !named:
+		return strconv.Quote(seq)
+	}
+	if mod&ModShift != 0 {
+		name = "Shift+" + name
+	}
+	if mod&ModAlt != 0 {
+		name = "Alt+" + name
+	}
+	if mod&ModCtrl != 0 {
+		name = "Ctrl+" + name
+	}
+	return name
+}
+
+// sameKey reports whether the sequences a and b are the same key, so that a key
+// with several encodings (like F1: \x1bOP, \x1b[11~ or \x1b[[A) matches any of them.
+func sameKey(a, b []byte) bool {
+	ka, ra, ma := parseKey(a)
+	kb, rb, mb := parseKey(b)
+	if ka == KeyNone || kb == KeyNone || (ka == KeyRune && ma == 0) {
+		return string(a) == string(b) // Unknown sequences and typed text match exactly
+	}
+	return ka == kb && ra == rb && ma == mb
+}
+
+// functionKey returns n for an unmodified Fn key event, or 0.
+func functionKey(ev InputEvent) int {
+	if ev.Key < KeyF1 || ev.Key > KeyF12 || ev.Mod != 0 {
+		return 0
+	}
+	return int(ev.Key-KeyF1) + 1
+}
@@ -0,0 +1,143 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+		key  Key
+		r    rune
+		mod  Modifiers
+	}{
+		{"letter", "a", KeyRune, 'a', 0},
+		{"multi-byte letter", "é", KeyRune, 'é', 0},
+		{"enter", "\r", KeyEnter, 0, 0},
+		{"tab", "\t", KeyTab, 0, 0},
+		{"shift+tab", "\x1b[Z", KeyShiftTab, 0, 0},
+		{"lone escape", "\x1b", KeyEscape, 0, 0},
+		{"DEL", "\x7f", KeyBackspace, 0, 0},
+		{"BS", "\x08", KeyBackspace, 0, ModCtrl},
+		{"ctrl+a", "\x01", KeyRune, 'a', ModCtrl},
+		{"ctrl+c", "\x03", KeyRune, 'c', ModCtrl},
+		{"ctrl+w", "\x17", KeyRune, 'w', ModCtrl},
+		{"alt+x", "\x1bx", KeyRune, 'x', ModAlt},
+		{"alt+enter", "\x1b\r", KeyEnter, 0, ModAlt},
+		{"up", "\x1b[A", KeyUp, 0, 0},
+		{"up SS3", "\x1bOA", KeyUp, 0, 0},
+		{"left", "\x1b[D", KeyLeft, 0, 0},
+		{"ctrl+right", "\x1b[1;5C", KeyRight, 0, ModCtrl},
+		{"shift+left", "\x1b[1;2D", KeyLeft, 0, ModShift},
+		{"home", "\x1b[H", KeyHome, 0, 0},
+		{"home VT220", "\x1b[1~", KeyHome, 0, 0},
+		{"end", "\x1b[F", KeyEnd, 0, 0},
+		{"end VT220", "\x1b[4~", KeyEnd, 0, 0},
+		{"ctrl+end", "\x1b[1;5F", KeyEnd, 0, ModCtrl},
+		{"insert", "\x1b[2~", KeyInsert, 0, 0},
+		{"delete", "\x1b[3~", KeyDelete, 0, 0},
+		{"page up", "\x1b[5~", KeyPageUp, 0, 0},
+		{"ctrl+page up", "\x1b[5;5~", KeyPageUp, 0, ModCtrl},
+		{"page down", "\x1b[6~", KeyPageDown, 0, 0},
+		{"F1 SS3", "\x1bOP", KeyF1, 0, 0},
+		{"F1 VT220", "\x1b[11~", KeyF1, 0, 0},
+		{"F1 Linux console", "\x1b[[A", KeyF1, 0, 0},
+		{"F5 Linux console", "\x1b[[E", KeyF5, 0, 0},
+		{"F4 SS3", "\x1bOS", KeyF4, 0, 0},
+		{"ctrl+F1", "\x1b[1;5P", KeyF1, 0, ModCtrl},
+		{"F5", "\x1b[15~", KeyF5, 0, 0},
+		{"shift+F5", "\x1b[15;2~", KeyF5, 0, ModShift},
+		{"F12", "\x1b[24~", KeyF12, 0, 0},
+		{"ctrl+insert rxvt", "\x1b[2^", KeyInsert, 0, ModCtrl},
+		{"ctrl+a CSI u", "\x1b[97;5u", KeyRune, 'a', ModCtrl},
+		{"ctrl+tab CSI u", "\x1b[9;5u", KeyTab, 0, ModCtrl},
+		{"ctrl+shift+tab modifyOtherKeys", "\x1b[27;6;9~", KeyTab, 0, ModCtrl | ModShift},
+		{"empty", "", KeyNone, 0, 0},
+		{"unknown CSI", "\x1b[99~", KeyNone, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, r, mod := parseKey([]byte(tt.seq))
+			if key != tt.key || r != tt.r || mod != tt.mod {
+				t.Errorf("parseKey(%q) = %v, %q, %v; want %v, %q, %v", tt.seq, key, r, mod, tt.key, tt.r, tt.mod)
+			}
+		})
+	}
+}
+
+func TestKeySplitterKeepsSequencesWhole(t *testing.T) {
+	var splitter keySplitter
+	keys := splitter.split([]byte("\x1b[[A\x1b[1;5Cx\x1b[11~"))
+	want := []string{"\x1b[[A", "\x1b[1;5C", "x", "\x1b[11~"}
+	if len(keys) != len(want) {
+		t.Fatalf("split into %q, want %q", keys, want)
+	}
+	for i, key := range keys {
+		if string(key) != want[i] {
+			t.Errorf("key %d = %q, want %q", i, key, want[i])
+		}
+	}
+}
+
+func TestKeySplitterHoldsKeysCutByReads(t *testing.T) {
+	tests := []struct {
+		name  string
+		reads []string
+		want  []string
+	}{
+		{"CSI sequence", []string{"\x1b[1;5", "C"}, []string{"\x1b[1;5C"}},
+		{"SS3 sequence", []string{"\x1bO", "P"}, []string{"\x1bOP"}},
+		{"Linux console F1", []string{"\x1b[[", "A"}, []string{"\x1b[[A"}},
+		{"escape alone", []string{"x\x1b", "[A"}, []string{"x", "\x1b[A"}},
+		{"UTF-8 character", []string{"a\xe4\xb8", "\x96b"}, []string{"a", "世b"}},
+		{"paste", []string{pasteStart + "one", " two" + pasteEnd}, []string{pasteStart + "one two" + pasteEnd}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var splitter keySplitter
+			var keys []string
+			for i, read := range tt.reads {
+				for _, key := range splitter.split([]byte(read)) {
+					keys = append(keys, string(key))
+				}
+				if i < len(tt.reads)-1 && !splitter.waiting() && !strings.HasPrefix(read, pasteStart) {
+					t.Errorf("split(%q) isn't waiting for the rest of the key", read)
+				}
+			}
+			if strings.Join(keys, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("keys = %q, want %q", keys, tt.want)
+			}
+			if splitter.waiting() {
+				t.Errorf("still waiting with %q held back", splitter.pending)
+			}
+		})
+	}
+}
+
+func TestKeySplitterFlushesLoneEscape(t *testing.T) {
+	var splitter keySplitter
+	if keys := splitter.split([]byte("\x1b")); len(keys) != 0 {
+		t.Fatalf("split(Escape) = %q, want it held back", keys)
+	}
+	if splitter.timeout() == nil {
+		t.Fatal("no timeout for the held back Escape")
+	}
+	keys := splitter.flush()
+	if len(keys) != 1 || string(keys[0]) != "\x1b" {
+		t.Fatalf("flush = %q, want the Escape key", keys)
+	}
+	if key, _, _ := parseKey(keys[0]); key != KeyEscape {
+		t.Errorf("flushed key parses as %v, want KeyEscape", key)
+	}
+	if splitter.timeout() != nil || splitter.flush() != nil {
+		t.Error("nothing is held back after flush, but the splitter still waits")
+	}
+
+	// An unfinished paste waits for its end, however long it takes
+	splitter.split([]byte(pasteStart + "text"))
+	if splitter.timeout() != nil || splitter.flush() != nil {
+		t.Error("an unfinished paste times out")
+	}
+}
@@ -0,0 +1,104 @@
+package gui
+
+// KeyAction is a logical action of the built-in key handling in WindowActions.
+type KeyAction int
+
+const (
+	ActionQuit      KeyAction = iota // Leave WindowActions
+	ActionNextFocus                  // Move focus to the next element
+	ActionPrevFocus                  // Move focus to the previous element
+	ActionActivate                   // Press the focused button, toggle a checkbox, select a row, ...
+)
+
+// KeyMap maps logical actions to the key sequences that trigger them, as read from
+// the raw terminal (e.g. "\x03" for Ctrl+C, "\x1b[Z" for Shift+Tab).
+//
+// Printable keys (like the default q) only trigger their action when the focused
+// element isn't taking text input. A default key left out of the map no longer
+// triggers its action and is ignored, except as text input.
+type KeyMap map[KeyAction][]string
+
+// standardKeys are the sequences the built-in key handling understands for each action.
+var standardKeys = map[KeyAction]string{
+	ActionQuit:      "\x03", // Ctrl+C
+	ActionNextFocus: "\t",
+	ActionPrevFocus: "\x1b[Z",
+	ActionActivate:  "\r",
+}
+
+// DefaultKeyMap returns a new KeyMap with the built-in bindings: q, Q and Ctrl+C
+// quit, Tab and Shift+Tab move focus, Enter activates.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		ActionQuit:      {"\x03", "q", "Q"},
+		ActionNextFocus: {"\t"},
+		ActionPrevFocus: {"\x1b[Z"},
+		ActionActivate:  {"\r"},
+	}
+}
+
+// Lookup returns the action key is bound to.
+func (km KeyMap) Lookup(key []byte) (KeyAction, bool) {
+	for action, keys := range km {
+		for _, k := range keys {
+			if k == string(key) {
+				return action, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// SetKeyMap replaces the window's key bindings; nil restores the defaults.
+func (w *Window) SetKeyMap(km KeyMap) {
+	w.keyMap = km
+}
+
+// isQuitKey reports whether key is bound to ActionQuit as a control key, which
+// quits even where printable quit keys don't.
+func (w *Window) isQuitKey(key []byte) bool {
+	return string(w.standardKey(key, false)) == standardKeys[ActionQuit]
+}
+
+// standardKey translates key through the window's KeyMap into the sequence the
+// built-in key handling expects, or nil if the key should be ignored. textInput
+// reports whether the focused element takes printable keys as text.
+func (w *Window) standardKey(key []byte, textInput bool) []byte {
+	_, printable := typedText(key)
+	if printable && textInput {
+		return key // Typed text
+	}
+
+	km := w.keyMap
+	if km == nil {
+		km = DefaultKeyMap()
+	}
+	if action, ok := km.Lookup(key); ok {
+		if action == ActionQuit && printable {
+			return []byte("q") // Quits like q: not while a menu or prompt is open
+		}
+		return []byte(standardKeys[action])
+	}
+	if _, ok := DefaultKeyMap().Lookup(key); ok {
+		return nil // Default binding removed from the map
+	}
+	return key
+}
+
+// BindFunctionKey binds the function key Fn (1 to 12) to fn, which WindowActions
+// calls when the key is pressed without modifiers (except while a modal prompt is
+// open). fn returns true to leave WindowActions. Binding F1 replaces its default
+// binding, the help overlay; a nil fn removes the binding.
+func (w *Window) BindFunctionKey(n int, fn func() bool) {
+	if n < 1 || n > 12 {
+		return
+	}
+	if fn == nil {
+		delete(w.functionKeys, n)
+		return
+	}
+	if w.functionKeys == nil {
+		w.functionKeys = make(map[int]func() bool)
+	}
+	w.functionKeys[n] = fn
+}
@@ -0,0 +1,278 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"window-go/colors"
+)
+
+// Layouts describe a window and its elements as JSON, so they can live in files
+// and be edited without recompiling:
+//
+//	{
+//	  "title": "Settings", "x": 2, "y": 1, "width": 40, "height": 12, "boxStyle": "rounded",
+//	  "elements": [
+//	    {"type": "label", "x": 2, "y": 1, "text": "Name:", "color": "bold_white"},
+//	    {"type": "textbox", "name": "name", "x": 9, "y": 1, "width": 20, "text": "Guest"},
+//	    {"type": "button", "x": 2, "y": 3, "width": 10, "text": "Save", "action": "save"}
+//	  ]
+//	}
+//
+// Colors are color names from colors.ColorMap ("bold_cyan"), hex colors ("#ff8800")
+// or raw ANSI sequences. Button actions can't be stored, so they are bound by name:
+// register them with RegisterAction before calling LoadWindow.
+
+// windowLayout is the JSON form of a window.
+type windowLayout struct {
+	Icon         string          `json:"icon,omitempty"`
+	Title        string          `json:"title"`
+	X            int             `json:"x"`
+	Y            int             `json:"y"`
+	Width        int             `json:"width"`
+	Height       int             `json:"height"`
+	BoxStyle     string          `json:"boxStyle,omitempty"`
+	TitleColor   string          `json:"titleColor,omitempty"`
+	BorderColor  string          `json:"borderColor,omitempty"`
+	BgColor      string          `json:"bgColor,omitempty"`
+	ContentColor string          `json:"contentColor,omitempty"`
+	Elements     []elementLayout `json:"elements"`
+}
+
+// elementLayout is the JSON form of an element. Which fields apply depends on Type.
+type elementLayout struct {
+	Type        string   `json:"type"`           // label, button, textbox, checkbox, toggle, radio, progressbar, slider, container, textarea
+	Name        string   `json:"name,omitempty"` // Finds the element after loading (see ElementByName)
+	X           int      `json:"x"`
+	Y           int      `json:"y"`
+	Width       int      `json:"width,omitempty"`
+	Height      int      `json:"height,omitempty"`
+	Text        string   `json:"text,omitempty"`  // Text, label or initial content
+	Lines       []string `json:"lines,omitempty"` // Container content
+	Color       string   `json:"color,omitempty"`
+	ActiveColor string   `json:"activeColor,omitempty"`
+	OffColor    string   `json:"offColor,omitempty"`  // Toggle color when off; progress bar unfilled color
+	Checked     bool     `json:"checked,omitempty"`   // Checkbox, toggle and selected radio button
+	Group       string   `json:"group,omitempty"`     // Radio buttons with the same group name form one RadioGroup
+	Value       string   `json:"value,omitempty"`     // Radio button value
+	Action      string   `json:"action,omitempty"`    // Button action registered with RegisterAction
+	Number      float64  `json:"number,omitempty"`    // Progress bar or slider value
+	Min         float64  `json:"min,omitempty"`       // Slider minimum
+	Max         float64  `json:"max,omitempty"`       // Progress bar or slider maximum
+	Step        float64  `json:"step,omitempty"`      // Slider step
+	ShowValue   bool     `json:"showValue,omitempty"` // Progress bar percentage, slider value
+	MaxChars    int      `json:"maxChars,omitempty"`  // TextArea character limit
+}
+
+var (
+	actionsMu sync.RWMutex
+	actions   = map[string]func() bool{} // Button actions for layouts (see RegisterAction)
+)
+
+// RegisterAction makes fn available to layouts as the button action called name.
+// Registering a name again replaces its function.
+func RegisterAction(name string, fn func() bool) {
+	actionsMu.Lock()
+	defer actionsMu.Unlock()
+	actions[name] = fn
+}
+
+// lookupAction returns the action registered as name.
+func lookupAction(name string) (func() bool, bool) {
+	actionsMu.RLock()
+	defer actionsMu.RUnlock()
+	fn, ok := actions[name]
+	return fn, ok
+}
+
+// ElementByName returns the element a layout loaded with LoadWindow gave name,
+// or nil.
+func (w *Window) ElementByName(name string) UIElement {
+	return w.named[name]
+}
+
+// SaveLayout writes the window and its elements to out as a JSON layout that
+// LoadWindow can read back. Buttons are saved with their ActionName. Elements
+// layouts don't support (menus, prompts, tab panels, ...) are left out.
+func (w *Window) SaveLayout(out io.Writer) error {
+	layout := windowLayout{
+		Icon:         w.Icon,
+		Title:        w.Title,
+		X:            w.X,
+		Y:            w.Y,
+		Width:        w.Width,
+		Height:       w.Height,
+		BoxStyle:     w.BoxStyle,
+		TitleColor:   colorName(w.TitleColor),
+		BorderColor:  colorName(w.BorderColor),
+		BgColor:      colorName(w.BgColor),
+		ContentColor: colorName(w.ContentColor),
+		Elements:     []elementLayout{},
+	}
+
+	names := make(map[UIElement]string, len(w.named))
+	for name, element := range w.named {
+		names[element] = name
+	}
+	groups := map[*RadioGroup]string{}
+
+	for _, element := range w.Elements {
+		var el elementLayout
+		switch e := element.(type) {
+		case *Label:
+			el = elementLayout{Type: "label", X: e.X, Y: e.Y, Text: e.Text, Color: colorName(e.Color)}
+		case *Button:
+			el = elementLayout{Type: "button", X: e.X, Y: e.Y, Width: e.Width, Text: e.Text,
+				Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor), Action: e.ActionName}
+		case *TextBox:
+			el = elementLayout{Type: "textbox", X: e.X, Y: e.Y, Width: e.Width, Text: e.Text,
+				Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		case *CheckBox:
+			el = elementLayout{Type: "checkbox", X: e.X, Y: e.Y, Text: e.Label, Checked: e.Checked,
+				Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		case *ToggleSwitch:
+			el = elementLayout{Type: "toggle", X: e.X, Y: e.Y, Text: e.Label, Checked: e.On,
+				Color: colorName(e.OnColor), OffColor: colorName(e.OffColor), ActiveColor: colorName(e.ActiveColor)}
+		case *RadioButton:
+			group, ok := groups[e.Group]
+			if !ok {
+				group = fmt.Sprintf("group%d", len(groups)+1)
+				groups[e.Group] = group
+			}
+			el = elementLayout{Type: "radio", X: e.X, Y: e.Y, Text: e.Label, Value: e.Value, Group: group,
+				Checked: e.IsSelected, Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		case *ProgressBar:
+			el = elementLayout{Type: "progressbar", X: e.X, Y: e.Y, Width: e.Width, Number: e.Value, Max: e.MaxValue,
+				ShowValue: e.ShowPercentage, Color: colorName(e.Color), OffColor: colorName(e.UnfilledColor)}
+		case *Slider:
+			el = elementLayout{Type: "slider", X: e.X, Y: e.Y, Width: e.Width, Number: e.Value, Min: e.Min, Max: e.Max,
+				Step: e.Step, ShowValue: e.ShowValue, Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		case *Container:
+			content := e.Content
+			if e.unfiltered != nil {
+				content = e.unfiltered // Save everything, not just the rows the filter shows
+			}
+			el = elementLayout{Type: "container", X: e.X, Y: e.Y, Width: e.Width, Height: e.Height, Lines: content,
+				Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		case *TextArea:
+			el = elementLayout{Type: "textarea", X: e.X, Y: e.Y, Width: e.Width, Height: e.Height, Text: e.GetText(),
+				MaxChars: e.maxChars, Color: colorName(e.Color), ActiveColor: colorName(e.ActiveColor)}
+		default:
+			continue // Not supported by layouts
+		}
+		el.Name = names[element]
+		layout.Elements = append(layout.Elements, el)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(layout)
+}
+
+// LoadWindow reads a JSON layout (see SaveLayout) and builds the window and its
+// elements with the usual constructors. Button actions are looked up by name
+// among those registered with RegisterAction; an unknown action or element type
+// is an error.
+func LoadWindow(in io.Reader) (*Window, error) {
+	var layout windowLayout
+	if err := json.NewDecoder(in).Decode(&layout); err != nil {
+		return nil, fmt.Errorf("reading window layout: %w", err)
+	}
+
+	w := NewWindow(layout.Icon, layout.Title, layout.X, layout.Y, layout.Width, layout.Height, layout.BoxStyle,
+		colorCode(layout.TitleColor), colorCode(layout.BorderColor), colorCode(layout.BgColor), colorCode(layout.ContentColor))
+	groups := map[string]*RadioGroup{}
+
+	for i, el := range layout.Elements {
+		var element UIElement
+		color, activeColor := colorCode(el.Color), colorCode(el.ActiveColor)
+		switch el.Type {
+		case "label":
+			element = NewLabel(el.Text, el.X, el.Y, color)
+		case "button":
+			var action func() bool
+			if el.Action != "" {
+				fn, ok := lookupAction(el.Action)
+				if !ok {
+					return nil, fmt.Errorf("element %d: unknown action %q", i, el.Action)
+				}
+				action = fn
+			}
+			button := NewButton(el.Text, el.X, el.Y, el.Width, color, activeColor, action)
+			button.ActionName = el.Action
+			element = button
+		case "textbox":
+			element = NewTextBox(el.Text, el.X, el.Y, el.Width, color, activeColor)
+		case "checkbox":
+			element = NewCheckBox(el.Text, el.X, el.Y, el.Checked, color, activeColor)
+		case "toggle":
+			element = NewToggleSwitch(el.Text, el.X, el.Y, el.Checked, color, colorCode(el.OffColor), activeColor)
+		case "radio":
+			group, ok := groups[el.Group]
+			if !ok {
+				group = NewRadioGroup()
+				groups[el.Group] = group
+			}
+			radio := NewRadioButton(el.Text, el.Value, el.X, el.Y, color, activeColor, group)
+			if el.Checked {
+				group.Select(len(group.Buttons) - 1)
+			}
+			element = radio
+		case "progressbar":
+			element = NewProgressBar(el.X, el.Y, el.Width, el.Number, el.Max, color, colorCode(el.OffColor), el.ShowValue)
+		case "slider":
+			element = NewSlider(el.X, el.Y, el.Width, el.Min, el.Max, el.Step, el.Number, color, activeColor, el.ShowValue)
+		case "container":
+			container := NewContainer(el.X, el.Y, el.Width, el.Height, el.Lines)
+			container.Color, container.ActiveColor = color, activeColor
+			element = container
+		case "textarea":
+			element = NewTextArea(el.Text, el.X, el.Y, el.Width, el.Height, el.MaxChars, color, activeColor, false, false)
+		default:
+			return nil, fmt.Errorf("element %d: unknown element type %q", i, el.Type)
+		}
+
+		w.AddElement(element)
+		if el.Name != "" {
+			if w.named == nil {
+				w.named = map[string]UIElement{}
+			}
+			w.named[el.Name] = element
+		}
+	}
+	return w, nil
+}
+
+// colorName returns the name of an ANSI color code in colors.ColorMap, so saved
+// layouts stay readable, or the code itself if it has no name.
+func colorName(code string) string {
+	if code == "" {
+		return ""
+	}
+	names := make([]string, 0, len(colors.ColorMap))
+	for name := range colors.ColorMap {
+		names = append(names, name)
+	}
+	sort.Strings(names) // The same code always gets the same name
+	for _, name := range names {
+		if colors.ColorMap[name] == code {
+			return name
+		}
+	}
+	return code
+}
+
+// colorCode returns the ANSI code for a layout color: a name in colors.ColorMap,
+// a hex color ("#ff8800"), or a raw code, which is used as is.
+func colorCode(color string) string {
+	if code, ok := colors.ColorMap[color]; ok {
+		return code
+	}
+	if strings.HasPrefix(color, "#") {
+		return colors.FromHex(color)
+	}
+	return color
+}
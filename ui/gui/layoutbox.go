@@ -0,0 +1,296 @@
+package gui
+
+// Placeable is anything a layout can position: elements, and other layouts.
+type Placeable interface {
+	Bounded
+	Positioner
+}
+
+// Layout positions elements for the app instead of it computing every X/Y by hand.
+// Add one to a window with AddLayout: its elements become ordinary window elements,
+// and the window arranges them again before each Render, so they reflow when the
+// window is resized or an element changes size. Hidden elements take no space.
+type Layout interface {
+	Placeable
+	Arrange(width int)     // Positions the children in a content area width columns wide
+	Elements() []UIElement // The elements positioned, including those of nested layouts
+}
+
+// VBox stacks its children top to bottom, Spacing rows apart.
+type VBox struct {
+	X, Y     int // Position relative to window content area
+	Spacing  int // Empty rows between children
+	Children []Placeable
+}
+
+// NewVBox creates a VBox at (x, y) holding children.
+func NewVBox(x, y, spacing int, children ...Placeable) *VBox {
+	return &VBox{X: x, Y: y, Spacing: spacing, Children: children}
+}
+
+// Add appends children to the box. Call it before adding the box to a window.
+func (vb *VBox) Add(children ...Placeable) {
+	vb.Children = append(vb.Children, children...)
+}
+
+// Arrange implements Layout.
+func (vb *VBox) Arrange(width int) {
+	y := vb.Y
+	for _, child := range visibleChildren(vb.Children) {
+		child.SetPosition(vb.X, y)
+		if layout, ok := child.(Layout); ok {
+			layout.Arrange(width)
+		}
+		_, _, _, h := child.Bounds()
+		y += h + vb.Spacing
+	}
+}
+
+// Bounds implements Bounded.
+func (vb *VBox) Bounds() (int, int, int, int) {
+	width, height := 0, 0
+	for i, child := range visibleChildren(vb.Children) {
+		_, _, w, h := child.Bounds()
+		if w > width {
+			width = w
+		}
+		if i > 0 {
+			height += vb.Spacing
+		}
+		height += h
+	}
+	return vb.X, vb.Y, width, height
+}
+
+// SetPosition implements Positioner; the children move on the next Arrange.
+func (vb *VBox) SetPosition(x, y int) {
+	vb.X, vb.Y = x, y
+}
+
+// Elements implements Layout.
+func (vb *VBox) Elements() []UIElement {
+	return layoutElements(vb.Children)
+}
+
+// HBox places its children left to right, Spacing columns apart.
+type HBox struct {
+	X, Y     int // Position relative to window content area
+	Spacing  int // Empty columns between children
+	Children []Placeable
+}
+
+// NewHBox creates an HBox at (x, y) holding children.
+func NewHBox(x, y, spacing int, children ...Placeable) *HBox {
+	return &HBox{X: x, Y: y, Spacing: spacing, Children: children}
+}
+
+// Add appends children to the box. Call it before adding the box to a window.
+func (hb *HBox) Add(children ...Placeable) {
+	hb.Children = append(hb.Children, children...)
+}
+
+// Arrange implements Layout.
+func (hb *HBox) Arrange(width int) {
+	x := hb.X
+	for _, child := range visibleChildren(hb.Children) {
+		child.SetPosition(x, hb.Y)
+		if layout, ok := child.(Layout); ok {
+			layout.Arrange(width)
+		}
+		_, _, w, _ := child.Bounds()
+		x += w + hb.Spacing
+	}
+}
+
+// Bounds implements Bounded.
+func (hb *HBox) Bounds() (int, int, int, int) {
+	width, height := 0, 0
+	for i, child := range visibleChildren(hb.Children) {
+		_, _, w, h := child.Bounds()
+		if h > height {
+			height = h
+		}
+		if i > 0 {
+			width += hb.Spacing
+		}
+		width += w
+	}
+	return hb.X, hb.Y, width, height
+}
+
+// SetPosition implements Positioner; the children move on the next Arrange.
+func (hb *HBox) SetPosition(x, y int) {
+	hb.X, hb.Y = x, y
+}
+
+// Elements implements Layout.
+func (hb *HBox) Elements() []UIElement {
+	return layoutElements(hb.Children)
+}
+
+// Grid places its children in equal-width cells, left to right and then top to
+// bottom. Each cell is as wide as the widest child, and each row as tall as its
+// tallest child. With Columns 0 the grid flows: it fits as many columns as the
+// content width allows, so a resize rewraps the cells.
+type Grid struct {
+	X, Y       int // Position relative to window content area
+	Columns    int // Cells per row (0 = as many as fit)
+	Spacing    int // Empty columns between cells
+	RowSpacing int // Empty rows between rows
+	Children   []Placeable
+	columns    int // Columns used by the last Arrange
+}
+
+// NewGrid creates a Grid at (x, y) with columns cells per row (0 = as many as
+// fit) holding children, one cell apart.
+func NewGrid(x, y, columns int, children ...Placeable) *Grid {
+	return &Grid{X: x, Y: y, Columns: columns, Spacing: 1, Children: children}
+}
+
+// Add appends children to the grid. Call it before adding the grid to a window.
+func (g *Grid) Add(children ...Placeable) {
+	g.Children = append(g.Children, children...)
+}
+
+// cellWidth returns the width of the widest child.
+func (g *Grid) cellWidth(children []Placeable) int {
+	cellWidth := 0
+	for _, child := range children {
+		if _, _, w, _ := child.Bounds(); w > cellWidth {
+			cellWidth = w
+		}
+	}
+	return cellWidth
+}
+
+// rowHeights returns the height of each row of children laid out in columns.
+func (g *Grid) rowHeights(children []Placeable, columns int) []int {
+	var heights []int
+	for i, child := range children {
+		if i%columns == 0 {
+			heights = append(heights, 0)
+		}
+		if _, _, _, h := child.Bounds(); h > heights[len(heights)-1] {
+			heights[len(heights)-1] = h
+		}
+	}
+	return heights
+}
+
+// Arrange implements Layout.
+func (g *Grid) Arrange(width int) {
+	children := visibleChildren(g.Children)
+	cellWidth := g.cellWidth(children)
+	g.columns = g.Columns
+	if g.columns <= 0 {
+		g.columns = (width - g.X + g.Spacing) / (cellWidth + g.Spacing)
+	}
+	if g.columns < 1 {
+		g.columns = 1
+	}
+
+	heights := g.rowHeights(children, g.columns)
+	y := g.Y
+	for i, child := range children {
+		row, col := i/g.columns, i%g.columns
+		if col == 0 && row > 0 {
+			y += heights[row-1] + g.RowSpacing
+		}
+		child.SetPosition(g.X+col*(cellWidth+g.Spacing), y)
+		if layout, ok := child.(Layout); ok {
+			layout.Arrange(width)
+		}
+	}
+}
+
+// Bounds implements Bounded, for the columns of the last Arrange.
+func (g *Grid) Bounds() (int, int, int, int) {
+	children := visibleChildren(g.Children)
+	columns := g.columns
+	if columns < 1 {
+		columns = 1
+	}
+	if len(children) < columns {
+		columns = len(children)
+	}
+	if columns == 0 {
+		return g.X, g.Y, 0, 0
+	}
+	width := columns*g.cellWidth(children) + (columns-1)*g.Spacing
+	height := 0
+	for i, h := range g.rowHeights(children, columns) {
+		if i > 0 {
+			height += g.RowSpacing
+		}
+		height += h
+	}
+	return g.X, g.Y, width, height
+}
+
+// SetPosition implements Positioner; the children move on the next Arrange.
+func (g *Grid) SetPosition(x, y int) {
+	g.X, g.Y = x, y
+}
+
+// Elements implements Layout.
+func (g *Grid) Elements() []UIElement {
+	return layoutElements(g.Children)
+}
+
+// visibleChildren returns the children that take space: all but hidden elements.
+func visibleChildren(children []Placeable) []Placeable {
+	visible := make([]Placeable, 0, len(children))
+	for _, child := range children {
+		if element, ok := child.(UIElement); ok && !isVisible(element) {
+			continue
+		}
+		visible = append(visible, child)
+	}
+	return visible
+}
+
+// layoutElements returns the elements among children, descending into nested layouts.
+func layoutElements(children []Placeable) []UIElement {
+	var elements []UIElement
+	for _, child := range children {
+		switch c := child.(type) {
+		case Layout:
+			elements = append(elements, c.Elements()...)
+		case UIElement:
+			elements = append(elements, c)
+		}
+	}
+	return elements
+}
+
+// AddLayout arranges layout in the window's content area and adds its elements
+// (see Layout). The window keeps arranging it before each Render.
+func (w *Window) AddLayout(layout Layout) {
+	w.layouts = append(w.layouts, layout)
+	layout.Arrange(w.Width - 2)
+	for _, element := range layout.Elements() {
+		w.AddElement(element)
+	}
+}
+
+// RemoveLayout removes layout and its elements from the window.
+func (w *Window) RemoveLayout(layout Layout) {
+	for i, l := range w.layouts {
+		if l == layout {
+			w.layouts = append(w.layouts[:i], w.layouts[i+1:]...)
+			break
+		}
+	}
+	delete(w.constraints, layout)
+	for _, element := range layout.Elements() {
+		w.RemoveElement(element)
+	}
+}
+
+// arrangeLayouts repositions the elements of the window's layouts for a content
+// area width columns wide.
+func (w *Window) arrangeLayouts(width int) {
+	for _, layout := range w.layouts {
+		layout.Arrange(width)
+	}
+}
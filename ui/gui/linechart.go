@@ -0,0 +1,329 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"window-go/colors"
+)
+
+// --- Canvas ---
+
+// brailleDots maps a dot's column (0-1) and row (0-3) within a cell to its bit in
+// a braille character (U+2800 plus the bits of the raised dots).
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// canvas is a grid of cells drawn with braille characters, each holding 2x4 dots,
+// so plots get twice the horizontal and four times the vertical resolution of
+// the text grid. Each cell takes the color of the last dot set in it. In ASCII
+// mode a cell with any dot is drawn as '*'.
+type canvas struct {
+	width, height int        // Size in cells
+	cells         [][]rune   // Dot bits of each cell
+	colors        [][]string // Color of each cell
+}
+
+// newCanvas creates an empty canvas of width x height cells.
+func newCanvas(width, height int) *canvas {
+	c := &canvas{width: width, height: height}
+	c.cells = make([][]rune, height)
+	c.colors = make([][]string, height)
+	for row := range c.cells {
+		c.cells[row] = make([]rune, width)
+		c.colors[row] = make([]string, width)
+	}
+	return c
+}
+
+// set raises the dot at (x, y), counted in dots from the top-left corner, in
+// color. Dots outside the canvas are ignored.
+func (c *canvas) set(x, y int, color string) {
+	if x < 0 || y < 0 || x >= c.width*2 || y >= c.height*4 {
+		return
+	}
+	c.cells[y/4][x/2] |= brailleDots[x%2][y%4]
+	c.colors[y/4][x/2] = color
+}
+
+// line raises the dots on a straight line from (x0, y0) to (x1, y1).
+func (c *canvas) line(x0, y0, x1, y1 int, color string) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		c.set(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// render draws the canvas with its top-left cell at (absX, absY). Empty cells are
+// written as spaces, so the canvas replaces whatever was under it.
+func (c *canvas) render(buffer *strings.Builder, absX, absY int) {
+	for row := range c.cells {
+		buffer.WriteString(MoveCursorCmd(absY+row, absX))
+		for col, bits := range c.cells[row] {
+			switch {
+			case bits == 0:
+				buffer.WriteString(" ")
+			case asciiMode:
+				buffer.WriteString(c.colors[row][col] + "*" + colors.Reset)
+			default:
+				buffer.WriteString(c.colors[row][col] + string(0x2800+bits) + colors.Reset)
+			}
+		}
+	}
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// clampDot converts a dot coordinate to an int in [0, max]. It reports false for NaN.
+func clampDot(f float64, max int) (int, bool) {
+	switch {
+	case math.IsNaN(f):
+		return 0, false
+	case f < 0:
+		return 0, true
+	case f > float64(max):
+		return max, true
+	}
+	return int(f), true
+}
+
+// --- LineChart ---
+
+// seriesColors are the colors given to LineChart series added without one, in turn.
+var seriesColors = []string{colors.Cyan, colors.Yellow, colors.Magenta, colors.Green, colors.Red, colors.Blue}
+
+// LineSeries is one line of a LineChart.
+type LineSeries struct {
+	Name  string    // Shown in the legend
+	Data  []float64 // Values, plotted left to right
+	Color string    // Color of the line
+}
+
+// LineChart plots one or more series as lines on a braille canvas, with the range
+// of values labeled on a Y axis on the left and the X range under the plot. All
+// series share the X axis: value i of every series is plotted at the same column,
+// and the longest series spans the full width.
+//
+//	100│    ⡠⠊⠉⠢⡀
+//	   │  ⡠⠊    ⠈⠢⡀
+//	  0│⠔⠊        ⠈⠢
+//	   └────────────
+//	   0          60
+type LineChart struct {
+	X, Y       int          // Position relative to window content area
+	Width      int          // Total width, including the Y axis labels
+	Height     int          // Total height, including the X axis and the legend
+	Series     []LineSeries // The lines plotted, in drawing order
+	MinY, MaxY float64      // Y range (both 0 = fit to the data)
+	XMin, XMax float64      // X range labeled under the plot (both 0 = the value indices)
+	Format     string       // fmt verb for the axis labels ("%.0f" if empty)
+	AxisColor  string       // Color of the axes and their labels
+	ShowLegend bool         // Write the series names in their colors on the last row
+	Hidden     bool         // Hidden charts are not rendered
+}
+
+// NewLineChart creates an empty LineChart at (x, y) of width x height cells; add
+// lines with AddSeries.
+func NewLineChart(x, y, width, height int) *LineChart {
+	return &LineChart{X: x, Y: y, Width: width, Height: height, AxisColor: colors.Gray}
+}
+
+// AddSeries adds a line named name plotting data, in the next of the default
+// series colors (change it through Series).
+func (lc *LineChart) AddSeries(name string, data []float64) {
+	lc.Series = append(lc.Series, LineSeries{
+		Name:  name,
+		Data:  data,
+		Color: seriesColors[len(lc.Series)%len(seriesColors)],
+	})
+}
+
+// format formats value for the axis labels.
+func (lc *LineChart) format(value float64) string {
+	format := lc.Format
+	if format == "" {
+		format = "%.0f"
+	}
+	return fmt.Sprintf(format, value)
+}
+
+// yRange returns the values at the bottom and top of the plot.
+func (lc *LineChart) yRange() (float64, float64) {
+	if lc.MinY != 0 || lc.MaxY != 0 {
+		return lc.MinY, lc.MaxY
+	}
+	low, high, found := 0.0, 0.0, false
+	for _, s := range lc.Series {
+		for _, v := range s.Data {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue // Not plotted
+			}
+			if !found || v < low {
+				low = v
+			}
+			if !found || v > high {
+				high = v
+			}
+			found = true
+		}
+	}
+	return low, high
+}
+
+// points returns the number of values of the longest series.
+func (lc *LineChart) points() int {
+	points := 0
+	for _, s := range lc.Series {
+		if len(s.Data) > points {
+			points = len(s.Data)
+		}
+	}
+	return points
+}
+
+// Bounds implements Bounded.
+func (lc *LineChart) Bounds() (int, int, int, int) {
+	return lc.X, lc.Y, lc.Width, lc.Height
+}
+
+// SetPosition implements Positioner.
+func (lc *LineChart) SetPosition(x, y int) {
+	lc.X, lc.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (lc *LineChart) SetSize(width, height int) {
+	lc.Width, lc.Height = width, height
+}
+
+// SetVisible implements Hider.
+func (lc *LineChart) SetVisible(visible bool) {
+	lc.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (lc *LineChart) IsVisible() bool {
+	return !lc.Hidden
+}
+
+// Render plots the series and draws the axes, their labels and the legend.
+func (lc *LineChart) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + lc.X
+	absY := winY + lc.Y
+	low, high := lc.yRange()
+	topLabel, bottomLabel := lc.format(high), lc.format(low)
+	labelWidth := DisplayWidth(topLabel)
+	if w := DisplayWidth(bottomLabel); w > labelWidth {
+		labelWidth = w
+	}
+	plotHeight := lc.Height - 2 // The X axis and its labels
+	if lc.ShowLegend {
+		plotHeight--
+	}
+	plotWidth := lc.Width - labelWidth - 1 // The Y axis
+	if plotWidth < 1 || plotHeight < 1 {
+		return
+	}
+
+	// Y axis, labeled with the range at the top and bottom rows
+	for row := 0; row < plotHeight; row++ {
+		label := ""
+		if row == 0 {
+			label = topLabel
+		} else if row == plotHeight-1 {
+			label = bottomLabel
+		}
+		buffer.WriteString(MoveCursorCmd(absY+row, absX))
+		buffer.WriteString(lc.AxisColor + alignText(label, labelWidth, AlignRight) + glyphs.vertical + colors.Reset)
+	}
+
+	// The series, each value joined to the next by a line
+	plot := newCanvas(plotWidth, plotHeight)
+	dotsWidth, dotsHeight := plotWidth*2, plotHeight*4
+	points := lc.points()
+	for _, s := range lc.Series {
+		prevX, prevY, joined := 0, 0, false
+		for i, v := range s.Data {
+			x := 0
+			if points > 1 {
+				x = i * (dotsWidth - 1) / (points - 1)
+			}
+			y, ok := dotsHeight-1, !math.IsNaN(v) && !math.IsInf(v, 0) // A flat range is plotted along the bottom
+			if ok && high > low {
+				y, ok = clampDot((high-v)/(high-low)*float64(dotsHeight-1), dotsHeight-1) // Values outside MinY/MaxY stay on the edge
+			}
+			if !ok {
+				joined = false // Non-finite values leave a gap in the line
+				continue
+			}
+			if joined {
+				plot.line(prevX, prevY, x, y, s.Color)
+			} else {
+				plot.set(x, y, s.Color)
+			}
+			prevX, prevY, joined = x, y, true
+		}
+	}
+	plot.render(buffer, absX+labelWidth+1, absY)
+
+	// X axis, labeled with the X range at its ends
+	xMin, xMax := lc.XMin, lc.XMax
+	if xMin == 0 && xMax == 0 && points > 0 {
+		xMax = float64(points - 1)
+	}
+	buffer.WriteString(MoveCursorCmd(absY+plotHeight, absX))
+	buffer.WriteString(lc.AxisColor + strings.Repeat(" ", labelWidth) + glyphs.axisCorner + strings.Repeat(glyphs.horizontal, plotWidth))
+	start, end := lc.format(xMin), lc.format(xMax)
+	axisLabels := truncateToWidth(start, plotWidth+1)
+	if DisplayWidth(start)+1+DisplayWidth(end) <= plotWidth+1 {
+		axisLabels = alignText(start, plotWidth+1-DisplayWidth(end), AlignLeft) + end
+	}
+	buffer.WriteString(MoveCursorCmd(absY+plotHeight+1, absX))
+	buffer.WriteString(strings.Repeat(" ", labelWidth) + axisLabels + colors.Reset)
+
+	if lc.ShowLegend {
+		// As many series as fit under the plot
+		buffer.WriteString(MoveCursorCmd(absY+plotHeight+2, absX+labelWidth+1))
+		used := 0
+		for i, s := range lc.Series {
+			entry := glyphs.fill + " " + s.Name
+			if i > 0 {
+				buffer.WriteString("  ")
+				used += 2
+			}
+			if used+DisplayWidth(entry) > plotWidth+1 {
+				break
+			}
+			buffer.WriteString(s.Color + glyphs.fill + colors.Reset + " " + s.Name)
+			used += DisplayWidth(entry)
+		}
+	}
+}
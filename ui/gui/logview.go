@@ -0,0 +1,342 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+	"window-go/colors"
+)
+
+// LogView is a read-only pane for an app's running output, under a header that
+// collapses it to one row. Lines are appended at the bottom and wrapped to the
+// width; they may carry their own ANSI colors (see AppendColored). The view
+// follows new lines until the user scrolls up, and follows again once they
+// scroll back to the bottom. AppendLine may be called from other goroutines;
+// re-render the window afterwards to show the lines.
+type LogView struct {
+	X, Y          int        // Position relative to window content area
+	Width, Height int        // Dimensions, including the header row
+	Title         string     // Shown in the header
+	MaxLines      int        // Oldest lines are dropped past this many (0 = no limit)
+	Color         string     // Color of lines without their own
+	HeaderColor   string     // Color of the header
+	ActiveColor   string     // Color of the header when focused
+	Collapsed     bool       // Only the header is shown
+	IsActive      bool       // State for rendering/input handling
+	Hidden        bool       // Hidden log views are not rendered and are skipped by focus traversal
+	lines         []string   // Appended lines, with their colors
+	scrollOffset  int        // Wrapped rows scrolled off the top
+	unpinned      bool       // The user scrolled up: new lines don't move the view
+	scrollBar     *ScrollBar // Shown over the right column while the lines overflow
+	mu            sync.Mutex // Guards the lines and scroll state against concurrent appends
+	themed
+}
+
+// NewLogView creates an empty LogView at (x, y) of width x height cells, titled title.
+func NewLogView(x, y, width, height int, title string) *LogView {
+	return &LogView{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Height:      height,
+		Title:       title,
+		HeaderColor: colors.BoldWhite,
+	}
+}
+
+// AppendLine adds s at the bottom of the log; line breaks in it start new lines.
+func (lv *LogView) AppendLine(s string) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	for _, line := range strings.Split(s, "\n") {
+		lv.lines = append(lv.lines, strings.ReplaceAll(line, "\t", "    "))
+	}
+	if lv.MaxLines > 0 && len(lv.lines) > lv.MaxLines {
+		dropped := len(lv.lines) - lv.MaxLines
+		if lv.unpinned {
+			// Keep the rows the user scrolled to in view as the ones above them go
+			for _, line := range lv.lines[:dropped] {
+				lv.scrollOffset -= len(wrapANSI(line, lv.textWidth()))
+			}
+			if lv.scrollOffset < 0 {
+				lv.scrollOffset = 0
+			}
+		}
+		lv.lines = append([]string(nil), lv.lines[dropped:]...)
+	}
+}
+
+// AppendColored adds s at the bottom of the log in color, e.g. colors.BoldRed for
+// an error as PrintError would show it.
+func (lv *LogView) AppendColored(s, color string) {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = color + line + colors.Reset
+	}
+	lv.AppendLine(strings.Join(lines, "\n"))
+}
+
+// Printf appends a line formatted like fmt.Sprintf.
+func (lv *LogView) Printf(format string, args ...interface{}) {
+	lv.AppendLine(fmt.Sprintf(format, args...))
+}
+
+// Clear removes every line and follows new ones again.
+func (lv *LogView) Clear() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.lines = nil
+	lv.scrollOffset = 0
+	lv.unpinned = false
+}
+
+// LineCount returns the number of lines in the log.
+func (lv *LogView) LineCount() int {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return len(lv.lines)
+}
+
+// Toggle collapses the view to its header, or expands it again.
+func (lv *LogView) Toggle() {
+	lv.Collapsed = !lv.Collapsed
+}
+
+// SetActive sets the focus state of the log view.
+func (lv *LogView) SetActive(active bool) {
+	lv.IsActive = active
+}
+
+// viewHeight returns the rows available for lines, under the header.
+func (lv *LogView) viewHeight() int {
+	if lv.Height < 2 {
+		return 0
+	}
+	return lv.Height - 1
+}
+
+// textWidth returns the width lines wrap at: the width less the scrollbar column.
+func (lv *LogView) textWidth() int {
+	if lv.Width < 2 {
+		return 1
+	}
+	return lv.Width - 1
+}
+
+// rows returns the lines wrapped to the text width. Call with mu held.
+func (lv *LogView) rows() []string {
+	var rows []string
+	for _, line := range lv.lines {
+		rows = append(rows, wrapANSI(line, lv.textWidth())...)
+	}
+	return rows
+}
+
+// maxScroll returns the largest scroll offset for rowCount wrapped rows.
+func (lv *LogView) maxScroll(rowCount int) int {
+	if overflow := rowCount - lv.viewHeight(); overflow > 0 {
+		return overflow
+	}
+	return 0
+}
+
+// ScrollBy scrolls the view by delta rows (positive scrolls down). Scrolling up
+// stops following new lines; reaching the bottom follows them again.
+func (lv *LogView) ScrollBy(delta int) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	maxScroll := lv.maxScroll(len(lv.rows()))
+	if !lv.unpinned {
+		lv.scrollOffset = maxScroll
+	}
+	lv.scrollTo(lv.scrollOffset+delta, maxScroll)
+}
+
+// ScrollToTop shows the first lines, and stops following new ones.
+func (lv *LogView) ScrollToTop() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.scrollTo(0, lv.maxScroll(len(lv.rows())))
+}
+
+// scrollTo sets the scroll offset, clamped to [0, maxScroll], following new lines
+// only at the bottom. Call with mu held.
+func (lv *LogView) scrollTo(offset, maxScroll int) {
+	if offset > maxScroll {
+		offset = maxScroll
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	lv.scrollOffset = offset
+	lv.unpinned = offset < maxScroll
+}
+
+// ScrollToBottom shows the last lines, and follows new ones again.
+func (lv *LogView) ScrollToBottom() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.unpinned = false
+}
+
+// IsFollowing reports whether the view moves to show new lines.
+func (lv *LogView) IsFollowing() bool {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return !lv.unpinned
+}
+
+// wrapANSI splits s into rows of at most width columns. Escape sequences take no
+// space, and each row starts with the styles in effect where it begins, so colors
+// carry over onto the wrapped rows.
+func wrapANSI(s string, width int) []string {
+	var rows []string
+	var row strings.Builder
+	style := "" // Escape sequences since the last reset
+	used := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			n := escapeLen(s, i)
+			seq := s[i : i+n]
+			row.WriteString(seq)
+			if seq == colors.Reset || seq == "\x1b[m" {
+				style = ""
+			} else {
+				style += seq
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if rw := runeWidth(r); used+rw > width && used > 0 {
+			rows = append(rows, row.String())
+			row.Reset()
+			row.WriteString(style)
+			used = 0
+		}
+		row.WriteRune(r)
+		used += runeWidth(r)
+		i += size
+	}
+	return append(rows, row.String())
+}
+
+// HandleInput implements InputHandler: Up/Down, PageUp/PageDown and Home scroll,
+// End follows new lines again, and Enter or Space collapses or expands the view.
+func (lv *LogView) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !lv.IsActive {
+		return false, false, false
+	}
+	switch {
+	case ev.Key == KeyUp: // Scroll up
+		lv.ScrollBy(-1)
+	case ev.Key == KeyDown: // Scroll down
+		lv.ScrollBy(1)
+	case ev.Key == KeyPageUp: // Scroll up a page
+		lv.ScrollBy(-lv.viewHeight())
+	case ev.Key == KeyPageDown: // Scroll down a page
+		lv.ScrollBy(lv.viewHeight())
+	case ev.Key == KeyHome: // First lines
+		lv.ScrollToTop()
+	case ev.Key == KeyEnd: // Last lines, following new ones
+		lv.ScrollToBottom()
+	case ev.Key == KeyEnter, ev.Key == KeyRune && ev.Mod == 0 && ev.Rune == ' ': // Collapse or expand
+		lv.Toggle()
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface (never needs cursor)
+func (lv *LogView) NeedsCursor() bool {
+	return false
+}
+
+// GetCursorPosition implements CursorManager interface
+func (lv *LogView) GetCursorPosition() (int, int, bool) {
+	return 0, 0, false
+}
+
+// Bounds implements Bounded; a collapsed view is only its header.
+func (lv *LogView) Bounds() (int, int, int, int) {
+	if lv.Collapsed {
+		return lv.X, lv.Y, lv.Width, 1
+	}
+	return lv.X, lv.Y, lv.Width, lv.Height
+}
+
+// SetPosition implements Positioner.
+func (lv *LogView) SetPosition(x, y int) {
+	lv.X, lv.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (lv *LogView) SetSize(width, height int) {
+	lv.Width, lv.Height = width, height
+}
+
+// SetVisible implements Hider.
+func (lv *LogView) SetVisible(visible bool) {
+	lv.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (lv *LogView) IsVisible() bool {
+	return !lv.Hidden
+}
+
+// Render draws the header and, unless collapsed, the visible rows of the log with
+// a scrollbar while they overflow.
+func (lv *LogView) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	absX := winX + lv.X
+	absY := winY + lv.Y
+
+	// 1. Header: an arrow showing the state, the title and the line count
+	arrow := glyphs.expanded
+	if lv.Collapsed {
+		arrow = glyphs.collapsed
+	}
+	header := fmt.Sprintf("%s %s (%d)", arrow, lv.Title, len(lv.lines))
+	if lv.IsActive {
+		buffer.WriteString(MoveCursorCmd(absY, absX) + themeColor(lv.ActiveColor, lv.theme().Active()) + ReverseVideo())
+	} else {
+		buffer.WriteString(MoveCursorCmd(absY, absX) + lv.HeaderColor)
+	}
+	buffer.WriteString(alignText(truncateToWidth(header, lv.Width), lv.Width, AlignLeft) + colors.Reset)
+	if lv.Collapsed || lv.Width < 2 {
+		return
+	}
+
+	// 2. Rows, pinned to the bottom unless the user scrolled up
+	rows := lv.rows()
+	maxScroll := lv.maxScroll(len(rows))
+	if !lv.unpinned || lv.scrollOffset > maxScroll {
+		lv.scrollOffset = maxScroll
+	}
+	textColor := themeColor(lv.Color, lv.theme().Content)
+	for r := 0; r < lv.viewHeight(); r++ {
+		buffer.WriteString(MoveCursorCmd(absY+1+r, absX))
+		text := ""
+		if i := lv.scrollOffset + r; i < len(rows) {
+			text = rows[i]
+		}
+		buffer.WriteString(textColor + text + colors.Reset)
+		buffer.WriteString(strings.Repeat(" ", lv.Width-DisplayWidth(text)))
+	}
+
+	// 3. Scroll position over the right column while the rows overflow
+	if maxScroll > 0 {
+		if lv.scrollBar == nil {
+			lv.scrollBar = NewScrollBar(0, 0, lv.viewHeight(), 0, 0, colors.Gray, colors.Gray, "logview_scrollbar")
+		}
+		lv.scrollBar.Height = lv.viewHeight()
+		lv.scrollBar.MaxValue = maxScroll
+		lv.scrollBar.Value = lv.scrollOffset
+		lv.scrollBar.Visible = true
+		renderElement(lv.scrollBar, lv.windowTheme, buffer, absX+lv.Width-1, absY+1, 1)
+	}
+}
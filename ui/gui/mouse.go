@@ -0,0 +1,266 @@
+package gui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// mouseWheelLines is how far one wheel notch scrolls a Container, TextArea or window.
+const mouseWheelLines = 3
+
+// MouseEvent is a decoded xterm SGR mouse report. In an InputEvent passed to an
+// element's HandleInput, X and Y are relative to the element's top-left corner.
+type MouseEvent struct {
+	Button  int  // 0 left, 1 middle, 2 right, 64 wheel up, 65 wheel down (modifier bits removed)
+	X, Y    int  // 0-based screen column and row
+	Pressed bool // True for a press ('M'), false for a release ('m')
+	Motion  bool // True for pointer motion while a button is held (a drag)
+}
+
+// parseMouseEvent decodes an SGR mouse report ("\x1b[<b;x;yM" or "...m").
+func parseMouseEvent(key []byte) (MouseEvent, bool) {
+	s := string(key)
+	if !strings.HasPrefix(s, "\x1b[<") || len(s) < 9 {
+		return MouseEvent{}, false
+	}
+	final := s[len(s)-1]
+	if final != 'M' && final != 'm' {
+		return MouseEvent{}, false
+	}
+	fields := strings.Split(s[3:len(s)-1], ";")
+	if len(fields) != 3 {
+		return MouseEvent{}, false
+	}
+	values := make([]int, 3)
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return MouseEvent{}, false
+		}
+		values[i] = v
+	}
+	return MouseEvent{
+		Button:  values[0] &^ (4 | 8 | 16 | 32), // Drop Shift/Meta/Ctrl and motion bits
+		X:       values[1] - 1,
+		Y:       values[2] - 1,
+		Pressed: final == 'M',
+		Motion:  values[0]&32 != 0,
+	}, true
+}
+
+// elementOrigin returns the absolute screen position that element's X/Y are relative to:
+// the window content area (adjusted for content scrolling), a TabPanel's page area,
+// an Accordion section's content area, or a Segment's content area.
+func (w *Window) elementOrigin(element UIElement) (int, int) {
+	switch parent := w.segmentParent(element).(type) {
+	case *Segment:
+		x, y := w.elementOrigin(parent)
+		return x + parent.X + parent.contentOffset(), y + parent.Y + parent.contentOffset() - parent.scrollOffset
+	case *SegmentGroup, *SplitPane: // Grouped segments and panes are positioned like their parent
+		return w.elementOrigin(parent)
+	}
+	if panel := w.tabPanelFor(element); panel != nil && UIElement(panel) != element {
+		x, y := w.elementOrigin(panel)
+		return x + panel.X, y + panel.Y + 2 // Pages start under the tab strip
+	}
+	if accordion := w.accordionFor(element); accordion != nil {
+		x, y := w.elementOrigin(accordion)
+		return x + accordion.X, y + accordion.Y + accordion.sectionTop(accordion.sectionOf(element)) + 1 // Under the section's header
+	}
+	x, y := w.X+1, w.Y+1
+	if w.Scrollable && !isPinned(element) {
+		y -= w.scrollOffset
+	}
+	return x, y
+}
+
+// elementAt returns the index in focusableElements of the topmost element under the
+// screen position (x, y), or -1. While a modal prompt is focused, only it can be hit.
+func (w *Window) elementAt(x, y int) int {
+	if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
+		if p, ok := w.focusableElements[w.focusedIndex].(*Prompt); ok && p.Modal {
+			if w.hits(p, x, y) {
+				return w.focusedIndex
+			}
+			return -1
+		}
+	}
+
+	hit, hitZ := -1, 0
+	for i, element := range w.focusableElements {
+		if !isFocusEnabled(element) || !w.hits(element, x, y) {
+			continue
+		}
+		z := 0
+		if zi, ok := element.(ZIndexer); ok {
+			z = zi.GetZIndex()
+		}
+		if hit == -1 || z >= hitZ {
+			hit, hitZ = i, z
+		}
+	}
+	return hit
+}
+
+// hits reports whether the screen position (x, y) is inside element.
+func (w *Window) hits(element UIElement, x, y int) bool {
+	b, ok := element.(Bounded)
+	if !ok {
+		return false
+	}
+	ex, ey, width, height := b.Bounds()
+	originX, originY := w.elementOrigin(element)
+	ex += originX
+	ey += originY
+	return x >= ex && x < ex+width && y >= ey && y < ey+height && w.inSegmentViews(element, x, y)
+}
+
+// handleTitleDrag moves a Movable window while its title bar (top border) is
+// dragged with the left button. It reports whether ev was part of a drag.
+func (w *Window) handleTitleDrag(ev MouseEvent) bool {
+	if ev.Button != 0 {
+		return false
+	}
+	switch {
+	case w.dragging && ev.Motion:
+		w.MoveTo(ev.X-w.dragOffset, ev.Y)
+		return true
+	case w.dragging && !ev.Pressed: // Released
+		w.dragging = false
+		return true
+	case w.Movable && ev.Pressed && !ev.Motion && ev.Y == w.Y && ev.X >= w.X && ev.X < w.X+w.Width:
+		w.dragging = true
+		w.dragOffset = ev.X - w.X
+		return true
+	}
+	return false
+}
+
+// handleMouse applies a mouse event: a left click focuses and activates the element
+// under the pointer, and the wheel scrolls the Container or TextArea under the
+// pointer (or the focused one, or the scrollable Segment holding it, or the window
+// content). Other elements implementing InputHandler get the event through
+// HandleInput. If the click landed on a button with an Action, the button is
+// returned for the caller to run; quit reports whether an element asked to quit.
+func (w *Window) handleMouse(ev MouseEvent) (btn *Button, quit bool) {
+	switch ev.Button {
+	case 64, 65: // Wheel up / down
+		delta := -mouseWheelLines
+		if ev.Button == 65 {
+			delta = mouseWheelLines
+		}
+		var target UIElement
+		if index := w.elementAt(ev.X, ev.Y); index != -1 {
+			target = w.focusableElements[index]
+		} else if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
+			target = w.focusableElements[w.focusedIndex]
+		}
+		switch el := target.(type) {
+		case *Container:
+			el.scrollBar.SetValue(el.scrollBar.Value + delta)
+		case *TextArea:
+			el.scrollBar.SetValue(el.scrollBar.Value + delta) // OnScroll moves the view
+		case *LogView:
+			el.ScrollBy(delta)
+		default:
+			if handled, quit := w.sendMouse(target, ev); handled {
+				return nil, quit
+			}
+			if seg := w.scrollingSegment(target); seg != nil {
+				seg.Scroll(delta)
+			} else if w.Scrollable {
+				w.ScrollContent(delta)
+			}
+		}
+		return nil, false
+	case 0: // Left button
+		if !ev.Pressed || ev.Motion {
+			return nil, false // Act on the press only
+		}
+	default:
+		return nil, false
+	}
+
+	index := w.elementAt(ev.X, ev.Y)
+	if index == -1 {
+		return nil, false
+	}
+	if index != w.focusedIndex {
+		w.setFocus(index)
+	}
+	element := w.focusableElements[index]
+	originX, originY := w.elementOrigin(element)
+
+	switch el := element.(type) {
+	case *Button:
+		if el.Action != nil {
+			return el, false
+		}
+	case *CheckBox:
+		el.Checked = !el.Checked
+	case *ToggleSwitch:
+		el.Toggle()
+	case *RadioButton:
+		for i, rb := range el.Group.Buttons {
+			if rb == el {
+				el.Group.Select(i)
+				break
+			}
+		}
+	case *Container:
+		row := ev.Y - originY - el.Y - el.inset()
+		if rowIndex := el.GetScrollOffset() + row; row < el.visibleHeight() && rowIndex < el.rowCount() {
+			el.HighlightedIndex = rowIndex
+		}
+	case *Table:
+		row := ev.Y - originY - el.Y - 2 // Rows start under the header and separator
+		if rowIndex := el.scrollOffset + row; row >= 0 && rowIndex < len(el.Rows) {
+			el.HighlightedIndex = rowIndex
+		}
+	case *DatePicker:
+		if day, ok := el.dayAt(ev.X-originX-el.X, ev.Y-originY-el.Y); ok {
+			el.SetValue(day)
+		}
+	case *LogView:
+		if ev.Y == originY+el.Y { // The header collapses and expands the view
+			el.Toggle()
+		}
+	case *Breadcrumb:
+		if item := el.itemAt(ev.X - originX - el.X); item != -1 {
+			el.Select(item)
+			el.Navigate()
+		}
+	case *Accordion:
+		if section := el.headerAt(ev.Y - originY - el.Y); section != -1 {
+			el.Select(section)
+			el.Toggle(section)
+		}
+	case *TabPanel:
+		if ev.Y == originY+el.Y {
+			if tab := el.tabAt(ev.X - originX - el.X); tab != -1 {
+				el.SetActiveTab(tab)
+			}
+		}
+	default:
+		_, quit := w.sendMouse(element, ev)
+		return nil, quit
+	}
+	return nil, false
+}
+
+// sendMouse passes ev to element's HandleInput, if it implements InputHandler, with
+// the position made relative to the element. It reports whether the element
+// handled the event and whether it asked to quit.
+func (w *Window) sendMouse(element UIElement, ev MouseEvent) (bool, bool) {
+	handler, ok := element.(InputHandler)
+	b, bounded := element.(Bounded)
+	if !ok || !bounded {
+		return false, false
+	}
+	x, y, _, _ := b.Bounds()
+	originX, originY := w.elementOrigin(element)
+	ev.X -= originX + x
+	ev.Y -= originY + y
+	handled, _, quit := handler.HandleInput(InputEvent{Key: KeyMouse, Mouse: &ev})
+	return handled, quit
+}
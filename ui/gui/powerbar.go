@@ -0,0 +1,155 @@
+package gui
+
+import (
+	"strconv"
+	"strings"
+	"window-go/colors"
+)
+
+// PowerBarSegment is one colored part of a PowerBar.
+type PowerBarSegment struct {
+	Text string
+	Fg   string // Text color
+	Bg   string // Background color; also colors the arrow that ends the segment
+}
+
+// PowerBar is a one-row bar of colored segments separated by Powerline arrows
+// (chars.RightArrowFilled), e.g. a header showing mode, file and position. Each
+// arrow is drawn in its segment's background on the next segment's background, so
+// the segments flow into each other. The arrow needs a Nerd Font; set Separator
+// for terminals without one (see chars.HasGlyphSupport).
+type PowerBar struct {
+	X, Y            int // Position relative to window content area
+	Width           int // Width of the bar (0 = the rest of the content width)
+	Segments        []PowerBarSegment
+	BackgroundColor string // Color of the bar after the last segment
+	Separator       string // Glyph ending each segment ("" = the Powerline arrow, > in ASCII mode)
+	Hidden          bool   // Hidden bars are not rendered
+}
+
+// NewPowerBar creates a PowerBar at (x, y) of the given width (0 = the rest of the
+// content width) showing segments.
+func NewPowerBar(x, y, width int, segments ...PowerBarSegment) *PowerBar {
+	return &PowerBar{X: x, Y: y, Width: width, Segments: segments}
+}
+
+// AddSegment appends a segment showing text in fg on bg.
+func (pb *PowerBar) AddSegment(text, fg, bg string) {
+	pb.Segments = append(pb.Segments, PowerBarSegment{Text: text, Fg: fg, Bg: bg})
+}
+
+// separator returns the glyph ending each segment.
+func (pb *PowerBar) separator() string {
+	if pb.Separator != "" {
+		return pb.Separator
+	}
+	return glyphs.powerArrow
+}
+
+// Render draws the segments left to right. Segments that don't fit in the width
+// are cut short with "…", and those after them are left out.
+func (pb *PowerBar) Render(buffer *strings.Builder, winX, winY int, contentWidth int) {
+	if pb.Hidden {
+		return
+	}
+	width := pb.Width
+	if width <= 0 || width > contentWidth-pb.X {
+		width = contentWidth - pb.X
+	}
+	if width <= 0 {
+		return
+	}
+	sep := pb.separator()
+	sepWidth := DisplayWidth(sep)
+
+	// Fit the segments first: each arrow's colors depend on the segment after it
+	var texts []string
+	used := 0
+	for _, seg := range pb.Segments {
+		room := width - used - sepWidth
+		if room < 1 {
+			break
+		}
+		text := truncateToWidth(" "+seg.Text+" ", room)
+		texts = append(texts, text)
+		used += DisplayWidth(text) + sepWidth
+	}
+
+	buffer.WriteString(MoveCursorCmd(winY+pb.Y, winX+pb.X))
+	for i, text := range texts {
+		seg := pb.Segments[i]
+		buffer.WriteString(seg.Bg + seg.Fg + text + colors.Reset)
+		next := pb.BackgroundColor
+		if i+1 < len(texts) {
+			next = pb.Segments[i+1].Bg
+		}
+		buffer.WriteString(next + foregroundOf(seg.Bg) + sep + colors.Reset)
+	}
+	if rest := width - used; rest > 0 {
+		buffer.WriteString(pb.BackgroundColor + strings.Repeat(" ", rest) + colors.Reset)
+	}
+}
+
+// Bounds implements Bounded. A zero Width reports the width of the segments.
+func (pb *PowerBar) Bounds() (int, int, int, int) {
+	width := pb.Width
+	if width <= 0 {
+		for _, seg := range pb.Segments {
+			width += DisplayWidth(seg.Text) + 2 + DisplayWidth(pb.separator())
+		}
+	}
+	return pb.X, pb.Y, width, 1
+}
+
+// SetPosition implements Positioner.
+func (pb *PowerBar) SetPosition(x, y int) {
+	pb.X, pb.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (pb *PowerBar) SetSize(width, height int) {
+	pb.Width = width
+}
+
+// SetVisible implements Hider.
+func (pb *PowerBar) SetVisible(visible bool) {
+	pb.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (pb *PowerBar) IsVisible() bool {
+	return !pb.Hidden
+}
+
+// foregroundOf returns the background colors set by the SGR sequences in code as
+// foreground colors (e.g. colors.BgBlue as colors.Blue), dropping everything else.
+// It lets a Powerline arrow continue a segment's background.
+func foregroundOf(code string) string {
+	var out strings.Builder
+	for _, seq := range strings.SplitAfter(code, "m") {
+		start := strings.Index(seq, "\x1b[")
+		if start < 0 || !strings.HasSuffix(seq, "m") {
+			continue
+		}
+		params := strings.Split(seq[start+2:len(seq)-1], ";")
+		for i := 0; i < len(params); i++ {
+			n, _ := strconv.Atoi(params[i])
+			switch {
+			case n >= 40 && n <= 47, n >= 100 && n <= 107: // Basic and bright backgrounds
+				out.WriteString("\x1b[" + strconv.Itoa(n-10) + "m")
+			case n == 48 || n == 38: // 256-color (5;n) or 24-bit (2;r;g;b)
+				extra := 0
+				if i+1 < len(params) && params[i+1] == "5" {
+					extra = 2
+				} else if i+1 < len(params) && params[i+1] == "2" {
+					extra = 4
+				}
+				if n == 48 && i+extra < len(params) {
+					out.WriteString("\x1b[38;" + strings.Join(params[i+1:i+1+extra], ";") + "m")
+				}
+				i += extra
+			}
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package gui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyTerminalResize delivers a signal on ch whenever the terminal is resized.
+func notifyTerminalResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package gui
+
+import "os"
+
+// notifyTerminalResize is a no-op on Windows, which has no SIGWINCH.
+func notifyTerminalResize(ch chan<- os.Signal) {}
@@ -0,0 +1,301 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"window-go/colors"
+)
+
+// screenCell is one terminal cell of a rendered frame.
+type screenCell struct {
+	ch    string // Text drawn in the cell ("" for the right half of a wide character)
+	style string // SGR sequences in effect when it was drawn
+	set   bool   // Whether the frame drew this cell at all
+}
+
+// screen is a frame decoded from the command stream Render builds: a grid of cells,
+// plus the commands that don't draw (cursor shape, titles, ...) and the final cursor state.
+// Comparing two screens lets Render only rewrite the cells that changed.
+type screen struct {
+	cells         [][]screenCell
+	width, height int             // Terminal size; cells outside it are dropped (0 = unbounded)
+	passthrough   strings.Builder // Non-drawing commands, in order
+	row, col      int             // Cursor position after the last command
+	cursorVisible bool
+}
+
+// parseScreen decodes commands (cursor moves, SGR colors, text and other escape
+// sequences) into a screen of width x height cells. Whatever is drawn outside it,
+// e.g. by a window larger than the terminal, is clipped. 0 leaves a dimension unbounded.
+func parseScreen(commands string, width, height int) *screen {
+	s := &screen{width: width, height: height}
+	style := ""
+	for i := 0; i < len(commands); {
+		if commands[i] == '\x1b' {
+			n := escapeLen(commands, i)
+			seq := commands[i : i+n]
+			i += n
+			switch {
+			case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "H"): // Cursor position
+				s.row, s.col = 0, 0
+				fmt.Sscanf(seq, moveCursorFormat, &s.row, &s.col)
+				s.row--
+				s.col--
+			case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"): // Colors and attributes
+				params := seq[2 : len(seq)-1]
+				if params == "" || params == "0" {
+					style = ""
+				} else if strings.HasPrefix(params, "0;") {
+					style = "\x1b[" + params[2:] + "m"
+				} else if !strings.HasSuffix(style, seq) {
+					style += seq
+				}
+			case seq == showCursor:
+				s.cursorVisible = true
+			case seq == hideCursor:
+				s.cursorVisible = false
+			default:
+				s.passthrough.WriteString(seq)
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(commands[i:])
+		i += size
+		switch {
+		case r == '\n':
+			s.row++
+		case r == '\r':
+			s.col = 0
+		case r < ' ':
+			s.passthrough.WriteRune(r) // e.g. the bell
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) && s.col > 0:
+			// Zero-width marks (variation selectors, joiners) belong to the previous character
+			prev := s.cell(s.row, s.col-1)
+			if prev.ch == "" && s.col > 1 {
+				prev = s.cell(s.row, s.col-2)
+			}
+			prev.ch += string(r)
+		default:
+			s.put(string(r), style)
+		}
+	}
+	return s
+}
+
+// escapeLen returns the length of the escape sequence at s[i]: a CSI sequence,
+// an OSC sequence ended by BEL or ST, or a two-byte escape.
+func escapeLen(s string, i int) int {
+	if i+1 < len(s) && s[i+1] == ']' {
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j - i + 1
+			}
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j - i + 2
+			}
+		}
+		return len(s) - i
+	}
+	if n := ansiSequenceLen(s, i); n > 0 {
+		return n
+	}
+	return 1 // Lone escape
+}
+
+// cell returns the cell at row, col, growing the grid as needed.
+func (s *screen) cell(row, col int) *screenCell {
+	if !s.inBounds(row, col) {
+		return &screenCell{} // Off screen: drawn nowhere
+	}
+	for len(s.cells) <= row {
+		s.cells = append(s.cells, nil)
+	}
+	for len(s.cells[row]) <= col {
+		s.cells[row] = append(s.cells[row], screenCell{})
+	}
+	return &s.cells[row][col]
+}
+
+// plain returns the text of the screen as rows of runes, height x width (or as
+// far as anything was drawn if unbounded). Cells nothing was drawn in are spaces;
+// the right half of a wide character is left out, so each row reads as its text.
+func (s *screen) plain() [][]rune {
+	height, width := s.height, s.width
+	if height <= 0 {
+		height = len(s.cells)
+	}
+	grid := make([][]rune, height)
+	for row := range grid {
+		rowWidth := width
+		if rowWidth <= 0 && row < len(s.cells) {
+			rowWidth = len(s.cells[row])
+		}
+		line := make([]rune, 0, rowWidth)
+		for col := 0; col < rowWidth; col++ {
+			cell := s.at(row, col)
+			switch {
+			case !cell.set:
+				line = append(line, ' ')
+			case cell.ch != "":
+				line = append(line, []rune(cell.ch)...)
+			}
+		}
+		grid[row] = line
+	}
+	return grid
+}
+
+// inBounds reports whether row, col is a cell of the screen.
+func (s *screen) inBounds(row, col int) bool {
+	return row >= 0 && col >= 0 && (s.height <= 0 || row < s.height) && (s.width <= 0 || col < s.width)
+}
+
+// at returns the cell at row, col, or an unset cell outside the grid.
+func (s *screen) at(row, col int) screenCell {
+	if s == nil || row < 0 || row >= len(s.cells) || col < 0 || col >= len(s.cells[row]) {
+		return screenCell{}
+	}
+	return s.cells[row][col]
+}
+
+// put draws ch at the cursor and advances it; wide characters take two cells.
+func (s *screen) put(ch, style string) {
+	// Overwriting half of a wide character blanks the other half
+	if current := s.at(s.row, s.col); current.set && current.ch == "" && s.col > 0 {
+		*s.cell(s.row, s.col-1) = screenCell{ch: " ", style: current.style, set: true}
+	}
+	if DisplayWidth(ch) == 2 && !s.inBounds(s.row, s.col+1) {
+		ch = " " // A wide character cut by the right edge would wrap
+	}
+	*s.cell(s.row, s.col) = screenCell{ch: ch, style: style, set: true}
+	if DisplayWidth(ch) == 2 {
+		*s.cell(s.row, s.col+1) = screenCell{ch: "", style: style, set: true}
+		s.col++
+	} else if next := s.at(s.row, s.col+1); next.set && next.ch == "" {
+		*s.cell(s.row, s.col+1) = screenCell{ch: " ", style: next.style, set: true}
+	}
+	s.col++
+}
+
+// diff returns the commands that turn the prev frame (nil for an unknown screen)
+// into s: only changed cells are written, cells prev drew that s doesn't are blanked.
+func (s *screen) diff(prev *screen) string {
+	var out strings.Builder
+	out.WriteString(hideCursor) // Don't show the cursor jumping around while drawing
+	out.WriteString(s.passthrough.String())
+
+	rows := len(s.cells)
+	if prev != nil && len(prev.cells) > rows {
+		rows = len(prev.cells)
+	}
+	styleKnown := false
+	emittedStyle := ""
+	curRow, curCol := -1, -1
+	for row := 0; row < rows; row++ {
+		cols := 0
+		if row < len(s.cells) {
+			cols = len(s.cells[row])
+		}
+		if prev != nil && row < len(prev.cells) && len(prev.cells[row]) > cols {
+			cols = len(prev.cells[row])
+		}
+		for col := 0; col < cols; col++ {
+			cell := s.at(row, col)
+			if cell == prev.at(row, col) {
+				continue
+			}
+			if cell.set && cell.ch == "" {
+				continue // Right half of a wide character, written with its left half
+			}
+			if !cell.set {
+				cell = screenCell{ch: " "} // No longer drawn: blank it
+			}
+			if row != curRow || col != curCol {
+				out.WriteString(MoveCursorCmd(row, col))
+			}
+			if !styleKnown || cell.style != emittedStyle {
+				out.WriteString(colors.Reset + cell.style)
+				emittedStyle = cell.style
+				styleKnown = true
+			}
+			out.WriteString(cell.ch)
+			curRow, curCol = row, col+DisplayWidth(cell.ch)
+		}
+	}
+	out.WriteString(colors.Reset)
+
+	if s.cursorVisible && s.inBounds(s.row, s.col) {
+		out.WriteString(MoveCursorCmd(s.row, s.col))
+		out.WriteString(showCursor)
+	}
+	return out.String()
+}
+
+// region returns the commands that draw the cells s has in the rectangle of width x
+// height cells at row top, column left, and nothing outside it. It clips what
+// elements rendered into a scratch buffer to the area they belong in.
+func (s *screen) region(top, left, width, height int) string {
+	var out strings.Builder
+	out.WriteString(s.passthrough.String())
+	styleKnown := false
+	emittedStyle := ""
+	for row := top; row < top+height && row < len(s.cells); row++ {
+		curCol := -1
+		for col := left; col < left+width && row >= 0 && col < len(s.cells[row]); col++ {
+			if col < 0 {
+				continue
+			}
+			cell := s.cells[row][col]
+			switch {
+			case !cell.set:
+				continue
+			case cell.ch == "" && col > left:
+				continue // Right half of a wide character, written with its left half
+			case cell.ch == "", DisplayWidth(cell.ch) == 2 && col+1 >= left+width:
+				cell.ch = " " // Half of a wide character cut by the edge
+			}
+			if col != curCol {
+				out.WriteString(MoveCursorCmd(row, col))
+			}
+			if !styleKnown || cell.style != emittedStyle {
+				out.WriteString(colors.Reset + cell.style)
+				emittedStyle = cell.style
+				styleKnown = true
+			}
+			out.WriteString(cell.ch)
+			curCol = col + DisplayWidth(cell.ch)
+		}
+	}
+	out.WriteString(colors.Reset)
+	return out.String()
+}
+
+// flush writes the frame in w.buffer to the terminal: only the changes since the
+// last frame, or everything when DisableDiff is set or after Invalidate. Either
+// way, cells outside the terminal are clipped rather than wrapped or scrolled.
+func (w *Window) flush() {
+	if w.manager != nil {
+		w.manager.windowRendered(w, w.buffer.String()) // Drawn together with the other windows
+		return
+	}
+	frame := parseScreen(w.buffer.String(), GetTerminalWidth(), GetTerminalHeight())
+	if w.DisableDiff {
+		w.frontScreen = nil
+		fmt.Fprint(w.output(), frame.diff(nil))
+		return
+	}
+	fmt.Fprint(w.output(), frame.diff(w.frontScreen))
+	w.frontScreen = frame
+}
+
+// Invalidate forgets what the window last drew, so the next Render redraws
+// everything. Call it after printing to the terminal outside of Render.
+func (w *Window) Invalidate() {
+	w.frontScreen = nil
+	if w.manager != nil {
+		w.manager.Invalidate()
+	}
+}
@@ -0,0 +1,169 @@
+package gui
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newDiffWindow returns a window with a few labels, rendering into out.
+func newDiffWindow(out *bytes.Buffer) (*Window, *Label) {
+	w := NewWindow("", "Diff", 0, 0, 60, 12, "single", "", "", "", "")
+	w.Output = out
+	for i := 0; i < 8; i++ {
+		w.AddElement(NewLabel(strings.Repeat("static text ", 4), 1, i, ""))
+	}
+	changed := NewLabel("before", 1, 9, "")
+	w.AddElement(changed)
+	return w, changed
+}
+
+func TestRenderWritesOnlyChangedCells(t *testing.T) {
+	var out bytes.Buffer
+	w, label := newDiffWindow(&out)
+
+	w.Render()
+	full := out.Len()
+	out.Reset()
+	label.Text = "AFTER!"
+	w.Render()
+	changed := out.String()
+
+	if !strings.Contains(StripANSI(changed), "AFTER!") {
+		t.Errorf("second frame %q doesn't draw the new label text", changed)
+	}
+	if strings.Contains(changed, "static text") {
+		t.Errorf("second frame %q redraws unchanged labels", changed)
+	}
+	if len(changed)*4 > full {
+		t.Errorf("second frame wrote %d bytes, first frame %d; want far fewer", len(changed), full)
+	}
+
+	// DisableDiff redraws everything
+	w.DisableDiff = true
+	out.Reset()
+	label.Text = "before"
+	w.Render()
+	if out.Len() < full/2 {
+		t.Errorf("frame with DisableDiff wrote %d bytes, want a full frame (%d)", out.Len(), full)
+	}
+}
+
+func BenchmarkRenderOneLabelChanged(b *testing.B) {
+	var out bytes.Buffer
+	w, label := newDiffWindow(&out)
+	w.Render()
+	texts := [2]string{"before", "AFTER!"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out.Reset()
+		label.Text = texts[i%2]
+		w.Render()
+	}
+	b.ReportMetric(float64(out.Len()), "bytes/frame")
+}
+
+func BenchmarkRenderOneLabelChangedNoDiff(b *testing.B) {
+	var out bytes.Buffer
+	w, label := newDiffWindow(&out)
+	w.DisableDiff = true
+	texts := [2]string{"before", "AFTER!"}
+	for i := 0; i < b.N; i++ {
+		out.Reset()
+		label.Text = texts[i%2]
+		w.Render()
+	}
+	b.ReportMetric(float64(out.Len()), "bytes/frame")
+}
+
+var cursorMove = regexp.MustCompile(`\x1b\[(\d+);(\d+)H`)
+
+func TestRenderClipsToTerminal(t *testing.T) {
+	termWidth, termHeight := GetTerminalWidth(), GetTerminalHeight()
+	// A window hanging off the bottom-right corner of the terminal
+	w := NewWindow("", "Clipped", termWidth-10, termHeight-4, 30, 10, "single", "", "", "", "")
+	w.AddElement(NewLabel("a label wider than the visible part", 1, 1, ""))
+	var out bytes.Buffer
+	w.Output = &out
+
+	check := func(name, commands string) {
+		moves := cursorMove.FindAllStringSubmatch(commands, -1)
+		if len(moves) == 0 {
+			t.Fatalf("%s: no cursor moves in %q", name, commands)
+		}
+		for _, m := range moves {
+			row, _ := strconv.Atoi(m[1])
+			col, _ := strconv.Atoi(m[2])
+			if row < 1 || row > termHeight || col < 1 || col > termWidth {
+				t.Errorf("%s: cursor move to row %d, column %d outside the %dx%d terminal", name, row, col, termWidth, termHeight)
+			}
+		}
+	}
+
+	w.Render()
+	check("Render", out.String())
+
+	out.Reset()
+	w.DisableDiff = true
+	w.Render()
+	check("Render with DisableDiff", out.String())
+
+	var to bytes.Buffer
+	if err := w.RenderTo(&to); err != nil {
+		t.Fatal(err)
+	}
+	check("RenderTo", to.String())
+
+	// The visible corner is still drawn
+	grid := w.RenderPlain()
+	if got := grid[termHeight-4][termWidth-10]; got != '┌' {
+		t.Errorf("top-left corner = %q, want '┌'", got)
+	}
+}
+
+func TestScrollableWindowClipsPartlyVisibleElements(t *testing.T) {
+	rows := make([]string, 30)
+	for i := range rows {
+		rows[i] = "row " + strconv.Itoa(i)
+	}
+	w := NewWindow("", "Scroll", 0, 0, 20, 8, "single", "", "", "", "")
+	w.Scrollable = true
+	w.AddElement(NewLabel("above", 1, 0, ""))
+	w.AddElement(NewContainer(1, 1, 12, 10, rows)) // Taller than the 6 content rows
+	w.AddElement(NewLabel("below", 1, 11, ""))
+
+	// Focusing the container scrolled it to the top; it shows its first rows
+	grid := w.RenderPlain()
+	if !strings.HasPrefix(string(grid[1][2:]), "row 0") {
+		t.Errorf("row 1 = %q, want the focused container's first row", string(grid[1]))
+	}
+
+	w.ScrollContentTo(0)
+	grid = w.RenderPlain()
+	if got := strings.TrimSpace(string(grid[1][1:19])); got != "above" {
+		t.Errorf("row 1 = %q, want the label above the container", got)
+	}
+	for row := 2; row < 7; row++ {
+		if want := "row " + strconv.Itoa(row-2); !strings.HasPrefix(string(grid[row][2:]), want) {
+			t.Errorf("row %d = %q, want the container's %q", row, string(grid[row]), want)
+		}
+	}
+	if got := grid[7][0]; got != '└' {
+		t.Errorf("bottom border starts with %q, want '└': the container drew over it", got)
+	}
+
+	// Scrolled down, the container's top is cut off and the label below shows
+	w.ScrollContentTo(6)
+	grid = w.RenderPlain()
+	if !strings.HasPrefix(string(grid[1][2:]), "row 5") {
+		t.Errorf("row 1 = %q, want the container's sixth row", string(grid[1]))
+	}
+	if got := strings.TrimSpace(string(grid[6][1:19])); got != "below" {
+		t.Errorf("row 6 = %q, want the label below the container", got)
+	}
+	if got := grid[7][0]; got != '└' {
+		t.Errorf("bottom border starts with %q, want '└'", got)
+	}
+}
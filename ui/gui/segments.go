@@ -16,6 +16,11 @@ type Segment struct {
 	BorderColor   string      // Border color if border is used
 	Title         string      // Optional title for bordered segments
 	TitleColor    string      // Title color
+	Scrollable    bool        // Scroll the content vertically when it is taller than the segment
+	enableState   enableGroup // Tracks elements disabled via SetEnabled
+	scrollOffset  int         // Content rows scrolled off the top (when Scrollable)
+	scrollBar     *ScrollBar  // Shown over the right edge while the content overflows
+	themed
 }
 
 // NewSegment creates a new segment with the specified dimensions
@@ -52,11 +57,160 @@ func NewBorderedSegment(x, y, width, height int, bgColor, borderStyle, borderCol
 	}
 }
 
-// AddElement adds a UI element to the segment
+// AddElement adds a UI element to the segment. Its X/Y are relative to the
+// segment's content area (inside the border, if any). A window registers the
+// focusable elements of a segment when the segment is added to it, so add them
+// to the segment first.
 func (s *Segment) AddElement(element UIElement) {
 	s.Elements = append(s.Elements, element)
 }
 
+// activeCursorManager returns the element in the segment that wants the cursor, if any.
+func (s *Segment) activeCursorManager() CursorManager {
+	for _, element := range s.Elements {
+		if cm, ok := element.(CursorManager); ok && cm.NeedsCursor() && isVisible(element) {
+			return cm
+		}
+	}
+	return nil
+}
+
+// NeedsCursor implements CursorManager interface; the segment asks for the cursor
+// on behalf of an active element in it.
+func (s *Segment) NeedsCursor() bool {
+	return s.activeCursorManager() != nil
+}
+
+// GetCursorPosition implements CursorManager interface
+func (s *Segment) GetCursorPosition() (int, int, bool) {
+	if cm := s.activeCursorManager(); cm != nil {
+		return cm.GetCursorPosition()
+	}
+	return 0, 0, false
+}
+
+// GetCursorShape implements CursorShaper for the element holding the cursor.
+func (s *Segment) GetCursorShape() CursorShape {
+	if shaper, ok := s.activeCursorManager().(CursorShaper); ok {
+		return shaper.GetCursorShape()
+	}
+	return CursorDefault
+}
+
+// contentOffset returns how far the segment's content area is inset from its
+// position: one cell for the border, if any.
+func (s *Segment) contentOffset() int {
+	if s.BorderStyle != "" {
+		return 1
+	}
+	return 0
+}
+
+// SetEnabled implements Enabler. It enables or disables every element in the
+// segment (including nested segments), e.g. to lock a section while work runs.
+// Re-enabling leaves elements that were disabled individually disabled.
+func (s *Segment) SetEnabled(enabled bool) {
+	s.enableState.set(enabled, s.Elements)
+}
+
+// IsEnabled implements Enabler.
+func (s *Segment) IsEnabled() bool {
+	return !s.enableState.disabled
+}
+
+// Bounds implements Bounded.
+func (s *Segment) Bounds() (int, int, int, int) {
+	return s.X, s.Y, s.Width, s.Height
+}
+
+// SetPosition implements Positioner.
+func (s *Segment) SetPosition(x, y int) {
+	s.X, s.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (s *Segment) SetSize(width, height int) {
+	s.Width, s.Height = width, height
+}
+
+// contentSize returns the width and height of the segment's content area.
+func (s *Segment) contentSize() (int, int) {
+	width, height := s.Width-2*s.contentOffset(), s.Height-2*s.contentOffset()
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	return width, height
+}
+
+// contentRows returns the number of rows needed to show every element.
+func (s *Segment) contentRows() int {
+	rows := 0
+	for _, element := range s.Elements {
+		if b, ok := element.(Bounded); ok {
+			_, y, _, h := b.Bounds()
+			if y+h > rows {
+				rows = y + h
+			}
+		}
+	}
+	return rows
+}
+
+// maxScrollOffset returns how far the content can scroll: 0 unless the segment is
+// Scrollable and its content is taller than the content area.
+func (s *Segment) maxScrollOffset() int {
+	if !s.Scrollable {
+		return 0
+	}
+	_, height := s.contentSize()
+	if overflow := s.contentRows() - height; overflow > 0 {
+		return overflow
+	}
+	return 0
+}
+
+// ScrollTo sets the number of content rows scrolled off the top, clamped to the
+// valid range. It has no effect unless the segment is Scrollable.
+func (s *Segment) ScrollTo(offset int) {
+	if offset > s.maxScrollOffset() {
+		offset = s.maxScrollOffset()
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	s.scrollOffset = offset
+}
+
+// Scroll scrolls the content by delta rows (positive scrolls down).
+func (s *Segment) Scroll(delta int) {
+	s.ScrollTo(s.scrollOffset + delta)
+}
+
+// ScrollOffset returns the number of content rows scrolled off the top.
+func (s *Segment) ScrollOffset() int {
+	return s.scrollOffset
+}
+
+// EnsureVisible scrolls the content so that element, one of the segment's
+// elements, lies within the content area. Elements taller than the content area
+// are aligned to the top.
+func (s *Segment) EnsureVisible(element UIElement) {
+	b, ok := element.(Bounded)
+	if !ok || !s.Scrollable {
+		return
+	}
+	_, y, _, h := b.Bounds()
+	_, height := s.contentSize()
+	if y < s.scrollOffset || h > height {
+		s.ScrollTo(y)
+	} else if y+h > s.scrollOffset+height {
+		s.ScrollTo(y + h - height)
+	}
+}
+
 // Render draws the segment and all elements within it
 func (s *Segment) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	// Calculate absolute position
@@ -89,10 +243,7 @@ func (s *Segment) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 	// 2. Draw border if specified (over the background)
 	if s.BorderStyle != "" {
-		box, exists := BoxTypes[s.BorderStyle]
-		if !exists {
-			box = BoxTypes["single"] // Fallback
-		}
+		box := frameBox(s.BorderStyle) // Falls back to "single"
 		buffer.WriteString(s.BorderColor)
 
 		// Draw top border with optional title
@@ -158,21 +309,39 @@ func (s *Segment) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	}
 
 	// 3. Render all elements within segment's adjusted content area
-	// Elements are rendered relative to the content area's top-left corner.
+	// Elements are rendered relative to the content area's top-left corner,
+	// scrolled, then clipped to the content area so they can't spill out.
 	contentAbsX := absX + contentOffsetX
 	contentAbsY := absY + contentOffsetY
+	s.ScrollTo(s.scrollOffset) // Clamp in case elements or the size changed
+	var content strings.Builder
 	for _, element := range s.Elements {
 		// Pass the absolute top-left of the content area and the content width/height
-		element.Render(buffer, contentAbsX, contentAbsY, contentWidth)
+		renderElement(element, s.windowTheme, &content, contentAbsX, contentAbsY-s.scrollOffset, contentWidth)
+	}
+	buffer.WriteString(parseScreen(content.String(), 0, 0).region(contentAbsY, contentAbsX, contentWidth, contentHeight))
+
+	// 4. Show the scroll position over the right edge while the content overflows
+	if maxOffset := s.maxScrollOffset(); maxOffset > 0 {
+		if s.scrollBar == nil {
+			s.scrollBar = NewScrollBar(0, 0, contentHeight, 0, 0, s.BorderColor, s.BorderColor, "segment_scrollbar")
+		}
+		s.scrollBar.Height = contentHeight
+		s.scrollBar.MaxValue = maxOffset
+		s.scrollBar.Value = s.scrollOffset
+		s.scrollBar.Visible = true
+		renderElement(s.scrollBar, s.windowTheme, buffer, absX+s.Width-1, contentAbsY, 1)
 	}
 }
 
 // SegmentGroup manages a collection of segments arranged horizontally
 type SegmentGroup struct {
-	X, Y           int        // Position relative to window
-	Segments       []*Segment // List of segments in this group
-	SeparatorChar  string     // Character for the vertical separator
-	SeparatorColor string     // Color for the separator
+	X, Y           int         // Position relative to window
+	Segments       []*Segment  // List of segments in this group
+	SeparatorChar  string      // Character for the vertical separator
+	SeparatorColor string      // Color for the separator
+	enableState    enableGroup // Tracks segments disabled via SetEnabled
+	themed
 }
 
 // NewSegmentGroup creates a new segment group at the specified position
@@ -238,6 +407,110 @@ func (sg *SegmentGroup) GetMaxHeight() int {
 	return maxHeight
 }
 
+// SetEnabled implements Enabler. It enables or disables every segment in the group.
+// Re-enabling leaves segments that were disabled individually disabled.
+func (sg *SegmentGroup) SetEnabled(enabled bool) {
+	members := make([]UIElement, len(sg.Segments))
+	for i, segment := range sg.Segments {
+		members[i] = segment
+	}
+	sg.enableState.set(enabled, members)
+}
+
+// IsEnabled implements Enabler.
+func (sg *SegmentGroup) IsEnabled() bool {
+	return !sg.enableState.disabled
+}
+
+// Bounds implements Bounded.
+func (sg *SegmentGroup) Bounds() (int, int, int, int) {
+	return sg.X, sg.Y, sg.GetTotalWidth(), sg.GetMaxHeight()
+}
+
+// SetPosition implements Positioner.
+func (sg *SegmentGroup) SetPosition(x, y int) {
+	for _, segment := range sg.Segments { // Grouped segments are positioned from the group
+		segment.X += x - sg.X
+		segment.Y += y - sg.Y
+	}
+	sg.X, sg.Y = x, y
+}
+
+// NeedsCursor implements CursorManager interface for an active element in one of the segments.
+func (sg *SegmentGroup) NeedsCursor() bool {
+	for _, segment := range sg.Segments {
+		if segment.NeedsCursor() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCursorPosition implements CursorManager interface
+func (sg *SegmentGroup) GetCursorPosition() (int, int, bool) {
+	for _, segment := range sg.Segments {
+		if segment.NeedsCursor() {
+			return segment.GetCursorPosition()
+		}
+	}
+	return 0, 0, false
+}
+
+// GetCursorShape implements CursorShaper for the element holding the cursor.
+func (sg *SegmentGroup) GetCursorShape() CursorShape {
+	for _, segment := range sg.Segments {
+		if segment.NeedsCursor() {
+			return segment.GetCursorShape()
+		}
+	}
+	return CursorDefault
+}
+
+// segmentChildren returns the elements element holds directly if it is a Segment
+// (its elements), a SegmentGroup (its segments) or a SplitPane (its panes), or nil.
+func segmentChildren(element UIElement) []UIElement {
+	switch v := element.(type) {
+	case *Segment:
+		return v.Elements
+	case *SegmentGroup:
+		children := make([]UIElement, len(v.Segments))
+		for i, segment := range v.Segments {
+			children[i] = segment
+		}
+		return children
+	case *SplitPane:
+		return []UIElement{v.First, v.Second}
+	}
+	return nil
+}
+
+// containsElement reports whether element is one of elements or inside a segment among them.
+func containsElement(elements []UIElement, element UIElement) bool {
+	for _, el := range elements {
+		if el == element || containsElement(segmentChildren(el), element) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentParent returns the Segment or SegmentGroup among elements (or nested in
+// them) that holds element directly, or nil.
+func segmentParent(elements []UIElement, element UIElement) UIElement {
+	for _, el := range elements {
+		children := segmentChildren(el)
+		for _, child := range children {
+			if child == element {
+				return el
+			}
+		}
+		if parent := segmentParent(children, element); parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
+
 // Render implements the UIElement interface for the segment group
 func (sg *SegmentGroup) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	maxHeight := sg.GetMaxHeight() // Determine max height for drawing separators
@@ -245,7 +518,7 @@ func (sg *SegmentGroup) Render(buffer *strings.Builder, winX, winY int, _ int) {
 	// Render each segment and draw separators between them
 	for i, segment := range sg.Segments {
 		// Render the segment itself (it uses its own X, Y relative to winX, winY)
-		segment.Render(buffer, winX, winY, segment.Width)
+		renderElement(segment, sg.windowTheme, buffer, winX, winY, segment.Width)
 
 		// Draw separator *after* the segment, if it's not the last one
 		if i < len(sg.Segments)-1 {
@@ -254,11 +527,74 @@ func (sg *SegmentGroup) Render(buffer *strings.Builder, winX, winY int, _ int) {
 
 			// Make the separator more prominent - use full-height line
 			buffer.WriteString(sg.SeparatorColor)
+			separator := sg.SeparatorChar
+			if asciiMode && separator == unicodeGlyphs.vertical {
+				separator = glyphs.vertical // The default separator has an ASCII form
+			}
 			for row := 0; row < maxHeight; row++ {
 				buffer.WriteString(MoveCursorCmd(separatorY+row, separatorX))
-				buffer.WriteString(sg.SeparatorChar) // Uses the configured separator character (default: "│")
+				buffer.WriteString(separator) // Uses the configured separator character (default: "│")
 			}
 			buffer.WriteString(colors.Reset)
 		}
 	}
 }
+
+// segmentParent returns the window's Segment or SegmentGroup (possibly on a tab
+// page or in an accordion section) that holds element directly, or nil.
+func (w *Window) segmentParent(element UIElement) UIElement {
+	if parent := segmentParent(w.Elements, element); parent != nil {
+		return parent
+	}
+	for _, el := range w.Elements {
+		if panel, ok := el.(*TabPanel); ok {
+			if parent := panel.segmentParent(element); parent != nil {
+				return parent
+			}
+		}
+		if accordion, ok := el.(*Accordion); ok {
+			if parent := accordion.segmentParent(element); parent != nil {
+				return parent
+			}
+		}
+	}
+	return nil
+}
+
+// inSegmentViews reports whether the screen position (x, y) is inside the content
+// area of every segment holding element, i.e. not clipped or scrolled out of view.
+func (w *Window) inSegmentViews(element UIElement, x, y int) bool {
+	for parent := w.segmentParent(element); parent != nil; parent = w.segmentParent(parent) {
+		seg, ok := parent.(*Segment)
+		if !ok {
+			continue
+		}
+		originX, originY := w.elementOrigin(seg)
+		left, top := originX+seg.X+seg.contentOffset(), originY+seg.Y+seg.contentOffset()
+		width, height := seg.contentSize()
+		if x < left || x >= left+width || y < top || y >= top+height {
+			return false
+		}
+	}
+	return true
+}
+
+// scrollSegmentsTo scrolls each Scrollable segment holding element so it is in view.
+func (w *Window) scrollSegmentsTo(element UIElement) {
+	for child, parent := element, w.segmentParent(element); parent != nil; child, parent = parent, w.segmentParent(parent) {
+		if seg, ok := parent.(*Segment); ok {
+			seg.EnsureVisible(child)
+		}
+	}
+}
+
+// scrollingSegment returns the innermost Scrollable segment holding element that
+// can scroll, or nil.
+func (w *Window) scrollingSegment(element UIElement) *Segment {
+	for parent := w.segmentParent(element); parent != nil; parent = w.segmentParent(parent) {
+		if seg, ok := parent.(*Segment); ok && seg.maxScrollOffset() > 0 {
+			return seg
+		}
+	}
+	return nil
+}
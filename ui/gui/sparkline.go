@@ -0,0 +1,130 @@
+package gui
+
+import (
+	"math"
+	"strings"
+	"window-go/colors"
+)
+
+// Sparkline draws a series of values as a one-row chart of block heights, scaled
+// between the smallest and largest value, to show a trend inline (in a status bar,
+// next to a list item). A series longer than Width is downsampled by averaging
+// neighbouring values; a shorter one is drawn from the left.
+type Sparkline struct {
+	X, Y          int       // Position relative to window content area
+	Width         int       // Number of bars
+	Data          []float64 // The series, oldest first
+	Color         string    // Color of the bars
+	StartColorHex string    // With EndColorHex, colors the bars with a gradient from left to right
+	EndColorHex   string    // End of the gradient (e.g., "#FF0000")
+	Hidden        bool      // Hidden sparklines are not rendered
+	themed
+}
+
+// NewSparkline creates a Sparkline at (x, y), width bars wide, showing data.
+func NewSparkline(x, y, width int, data []float64, color string) *Sparkline {
+	return &Sparkline{X: x, Y: y, Width: width, Data: data, Color: color}
+}
+
+// SetData replaces the series shown.
+func (s *Sparkline) SetData(data []float64) {
+	s.Data = data
+}
+
+// bars returns the values to draw, one per bar: the series itself if it fits in
+// Width, otherwise the averages of the finite values in Width consecutive slices
+// of it (NaN for a slice with none).
+func (s *Sparkline) bars() []float64 {
+	if len(s.Data) <= s.Width {
+		return s.Data
+	}
+	bars := make([]float64, s.Width)
+	for i := range bars {
+		start, end := i*len(s.Data)/s.Width, (i+1)*len(s.Data)/s.Width
+		sum, n := 0.0, 0
+		for _, v := range s.Data[start:end] {
+			if isFinite(v) {
+				sum += v
+				n++
+			}
+		}
+		bars[i] = math.NaN()
+		if n > 0 {
+			bars[i] = sum / float64(n)
+		}
+	}
+	return bars
+}
+
+// Bounds implements Bounded.
+func (s *Sparkline) Bounds() (int, int, int, int) {
+	return s.X, s.Y, s.Width, 1
+}
+
+// SetPosition implements Positioner.
+func (s *Sparkline) SetPosition(x, y int) {
+	s.X, s.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (s *Sparkline) SetSize(width, height int) {
+	s.Width = width
+}
+
+// SetVisible implements Hider.
+func (s *Sparkline) SetVisible(visible bool) {
+	s.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (s *Sparkline) IsVisible() bool {
+	return !s.Hidden
+}
+
+// Render draws one block per bar, its height scaled between the smallest and
+// largest finite bar. A flat series is drawn at half height, and NaN or infinite
+// values as the lowest bar.
+func (s *Sparkline) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	bars := s.bars()
+	if len(bars) == 0 {
+		return
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range bars {
+		if !isFinite(v) {
+			continue
+		}
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	levels := []rune(glyphs.sparkBars)
+	var gradient []string
+	if s.StartColorHex != "" && s.EndColorHex != "" {
+		gradient = colors.GenerateGradient(s.StartColorHex, s.EndColorHex, len(bars))
+	}
+
+	buffer.WriteString(MoveCursorCmd(winY+s.Y, winX+s.X))
+	buffer.WriteString(themeColor(s.Color, s.theme().Accent))
+	for i, v := range bars {
+		level := len(levels) / 2
+		switch {
+		case !isFinite(v):
+			level = 0
+		case high > low:
+			// Halved first, so a span wider than float64 can't overflow
+			fraction := (v/2 - low/2) / (high/2 - low/2)
+			level = int(fraction*float64(len(levels)-1) + 0.5) // Rounded
+			level = max(0, min(level, len(levels)-1))
+		}
+		if gradient != nil {
+			buffer.WriteString(gradient[i])
+		}
+		buffer.WriteRune(levels[level])
+	}
+	buffer.WriteString(colors.Reset)
+}
+
+// isFinite reports whether v is neither NaN nor infinite.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
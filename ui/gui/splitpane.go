@@ -0,0 +1,360 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// SplitPane divides an area between two segments, side by side (Horizontal) or one
+// above the other (Vertical), with a divider between them. The divider is a tab
+// stop: while it is focused the arrow keys move it, and it can be dragged with the
+// mouse. Moving it resizes both panes; each pane clips its elements to its area.
+// The panes are positioned in the same coordinates as the split pane itself, like
+// the segments of a SegmentGroup, so their elements keep X/Y relative to the pane.
+type SplitPane struct {
+	X, Y          int         // Position relative to window content area
+	Width, Height int         // Dimensions, including the divider and the frame
+	Orientation   Orientation // Horizontal: First left of Second; Vertical: First above Second
+	First, Second *Segment    // The panes
+	Ratio         float64     // Share of the space given to First (0 to 1)
+	MinSize       int         // Smallest width (Horizontal) or height (Vertical) of either pane
+	BorderStyle   string      // Optional frame around both panes, which the divider joins ("" = none)
+	DividerColor  string      // Color of the divider and frame
+	ActiveColor   string      // Color of the divider while focused
+	IsActive      bool        // State for rendering/input handling
+	Hidden        bool        // Hidden split panes are not rendered and are skipped by focus traversal
+	OnResize      func(ratio float64)
+	enableState   enableGroup // Tracks pane elements disabled via SetEnabled
+	themed
+}
+
+// NewSplitPane creates a SplitPane at (x, y) dividing width x height cells evenly
+// between first and second (new empty segments if nil). Add the panes' elements
+// to them before adding the split pane to a window.
+func NewSplitPane(x, y, width, height int, orientation Orientation, first, second *Segment) *SplitPane {
+	if first == nil {
+		first = NewSegment(0, 0, 0, 0, "")
+	}
+	if second == nil {
+		second = NewSegment(0, 0, 0, 0, "")
+	}
+	sp := &SplitPane{
+		X:            x,
+		Y:            y,
+		Width:        width,
+		Height:       height,
+		Orientation:  orientation,
+		First:        first,
+		Second:       second,
+		Ratio:        0.5,
+		MinSize:      1,
+		DividerColor: colors.Gray,
+	}
+	sp.layout()
+	return sp
+}
+
+// inset returns how far the panes are inset from the split pane's edges: one cell
+// for the frame, if any.
+func (sp *SplitPane) inset() int {
+	if sp.BorderStyle != "" {
+		return 1
+	}
+	return 0
+}
+
+// available returns the cells the two panes share along the split direction.
+func (sp *SplitPane) available() int {
+	size := sp.Width
+	if sp.Orientation == Vertical {
+		size = sp.Height
+	}
+	if available := size - 2*sp.inset() - 1; available > 0 { // Minus the divider
+		return available
+	}
+	return 0
+}
+
+// clampSize limits a size of First to the range that leaves both panes MinSize.
+func (sp *SplitPane) clampSize(size int) int {
+	available := sp.available()
+	minSize := sp.MinSize
+	if minSize > available/2 {
+		minSize = available / 2
+	}
+	if size < minSize {
+		size = minSize
+	}
+	if size > available-minSize {
+		size = available - minSize
+	}
+	return size
+}
+
+// firstSize returns the width (Horizontal) or height (Vertical) of First.
+func (sp *SplitPane) firstSize() int {
+	return sp.clampSize(int(sp.Ratio*float64(sp.available()) + 0.5))
+}
+
+// layout positions and sizes the panes on either side of the divider.
+func (sp *SplitPane) layout() {
+	inset := sp.inset()
+	x, y := sp.X+inset, sp.Y+inset
+	width, height := sp.Width-2*inset, sp.Height-2*inset
+	first := sp.firstSize()
+	second := sp.available() - first
+	if sp.Orientation == Vertical {
+		sp.First.SetPosition(x, y)
+		sp.First.SetSize(width, first)
+		sp.Second.SetPosition(x, y+first+1)
+		sp.Second.SetSize(width, second)
+	} else {
+		sp.First.SetPosition(x, y)
+		sp.First.SetSize(first, height)
+		sp.Second.SetPosition(x+first+1, y)
+		sp.Second.SetSize(second, height)
+	}
+}
+
+// SetRatio gives First ratio (0 to 1) of the space, within MinSize of either edge,
+// and calls OnResize if it changed.
+func (sp *SplitPane) SetRatio(ratio float64) {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	if ratio == sp.Ratio {
+		return
+	}
+	sp.Ratio = ratio
+	sp.layout()
+	if sp.OnResize != nil {
+		sp.OnResize(ratio)
+	}
+}
+
+// setFirstSize moves the divider so First is size cells wide (or tall).
+func (sp *SplitPane) setFirstSize(size int) {
+	if available := sp.available(); available > 0 {
+		sp.SetRatio(float64(sp.clampSize(size)) / float64(available))
+	}
+}
+
+// MoveDivider moves the divider by delta cells (positive moves it right or down).
+func (sp *SplitPane) MoveDivider(delta int) {
+	sp.setFirstSize(sp.firstSize() + delta)
+}
+
+// dividerOffset returns the column (Horizontal) or row (Vertical) of the divider,
+// relative to the split pane.
+func (sp *SplitPane) dividerOffset() int {
+	return sp.inset() + sp.firstSize()
+}
+
+// onDivider reports whether (x, y), relative to the split pane, is on the divider.
+func (sp *SplitPane) onDivider(x, y int) bool {
+	if sp.Orientation == Vertical {
+		return y == sp.dividerOffset() && x >= 0 && x < sp.Width
+	}
+	return x == sp.dividerOffset() && y >= 0 && y < sp.Height
+}
+
+// dragTo moves the divider to (x, y), relative to the split pane.
+func (sp *SplitPane) dragTo(x, y int) {
+	if sp.Orientation == Vertical {
+		sp.setFirstSize(y - sp.inset())
+	} else {
+		sp.setFirstSize(x - sp.inset())
+	}
+}
+
+// SetActive sets the focus state of the divider.
+func (sp *SplitPane) SetActive(active bool) {
+	sp.IsActive = active
+}
+
+// HandleInput implements InputHandler: while the divider is focused, the arrow keys
+// along the split move it and Home/End (or PageUp/PageDown) move it to either end.
+func (sp *SplitPane) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !sp.IsActive {
+		return false, false, false
+	}
+	vertical := sp.Orientation == Vertical
+	switch {
+	case ev.Key == KeyLeft && !vertical, ev.Key == KeyUp && vertical: // Move divider back
+		sp.MoveDivider(-1)
+	case ev.Key == KeyRight && !vertical, ev.Key == KeyDown && vertical: // Move divider forward
+		sp.MoveDivider(1)
+	case ev.Key == KeyHome, ev.Key == KeyPageUp: // Divider to the start
+		sp.SetRatio(0)
+	case ev.Key == KeyEnd, ev.Key == KeyPageDown: // Divider to the end
+		sp.SetRatio(1)
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface for an active element in one of the panes.
+func (sp *SplitPane) NeedsCursor() bool {
+	return sp.First.NeedsCursor() || sp.Second.NeedsCursor()
+}
+
+// GetCursorPosition implements CursorManager interface
+func (sp *SplitPane) GetCursorPosition() (int, int, bool) {
+	if sp.First.NeedsCursor() {
+		return sp.First.GetCursorPosition()
+	}
+	return sp.Second.GetCursorPosition()
+}
+
+// GetCursorShape implements CursorShaper for the element holding the cursor.
+func (sp *SplitPane) GetCursorShape() CursorShape {
+	if sp.First.NeedsCursor() {
+		return sp.First.GetCursorShape()
+	}
+	return sp.Second.GetCursorShape()
+}
+
+// Bounds implements Bounded.
+func (sp *SplitPane) Bounds() (int, int, int, int) {
+	return sp.X, sp.Y, sp.Width, sp.Height
+}
+
+// SetPosition implements Positioner; the panes move with the split pane.
+func (sp *SplitPane) SetPosition(x, y int) {
+	sp.X, sp.Y = x, y
+	sp.layout()
+}
+
+// SetSize implements Sizer; the panes are resized, keeping the ratio.
+func (sp *SplitPane) SetSize(width, height int) {
+	sp.Width, sp.Height = width, height
+	sp.layout()
+}
+
+// SetEnabled implements Enabler. It enables or disables the divider and the
+// elements of both panes. Re-enabling leaves elements that were disabled
+// individually disabled.
+func (sp *SplitPane) SetEnabled(enabled bool) {
+	sp.enableState.set(enabled, []UIElement{sp.First, sp.Second})
+}
+
+// IsEnabled implements Enabler.
+func (sp *SplitPane) IsEnabled() bool {
+	return !sp.enableState.disabled
+}
+
+// SetVisible implements Hider.
+func (sp *SplitPane) SetVisible(visible bool) {
+	sp.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (sp *SplitPane) IsVisible() bool {
+	return !sp.Hidden
+}
+
+// Render draws the panes, the frame (if any) and the divider, whose ends join the frame.
+func (sp *SplitPane) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	sp.layout() // The ratio, size or position may have been changed directly
+	absX := winX + sp.X
+	absY := winY + sp.Y
+
+	// 1. Panes, each clipped to its area
+	renderElement(sp.First, sp.windowTheme, buffer, winX, winY, sp.First.Width)
+	renderElement(sp.Second, sp.windowTheme, buffer, winX, winY, sp.Second.Width)
+
+	color := sp.DividerColor
+	if sp.IsActive && sp.IsEnabled() {
+		color = themeColor(sp.ActiveColor, sp.theme().Active())
+	}
+	style := sp.BorderStyle
+	if style == "" {
+		style = "single"
+	}
+	box := frameBox(style)
+
+	// 2. Frame around both panes
+	inset := sp.inset()
+	if inset > 0 {
+		buffer.WriteString(sp.DividerColor)
+		buffer.WriteString(MoveCursorCmd(absY, absX))
+		buffer.WriteString(box.TopLeft + strings.Repeat(box.Horizontal, sp.Width-2) + box.TopRight)
+		for row := 1; row < sp.Height-1; row++ {
+			buffer.WriteString(MoveCursorCmd(absY+row, absX))
+			buffer.WriteString(box.Vertical)
+			buffer.WriteString(MoveCursorCmd(absY+row, absX+sp.Width-1))
+			buffer.WriteString(box.Vertical)
+		}
+		buffer.WriteString(MoveCursorCmd(absY+sp.Height-1, absX))
+		buffer.WriteString(box.BottomLeft + strings.Repeat(box.Horizontal, sp.Width-2) + box.BottomRight)
+		buffer.WriteString(colors.Reset)
+	}
+
+	// 3. Divider, with tees where it meets the frame
+	offset := sp.dividerOffset()
+	if sp.Orientation == Vertical {
+		buffer.WriteString(MoveCursorCmd(absY+offset, absX))
+		if inset > 0 {
+			buffer.WriteString(sp.DividerColor + glyphOr(box.LeftTee, box.Vertical))
+		}
+		buffer.WriteString(color + strings.Repeat(box.Horizontal, sp.Width-2*inset) + colors.Reset)
+		if inset > 0 {
+			buffer.WriteString(sp.DividerColor + glyphOr(box.RightTee, box.Vertical) + colors.Reset)
+		}
+		return
+	}
+	if inset > 0 {
+		buffer.WriteString(MoveCursorCmd(absY, absX+offset))
+		buffer.WriteString(sp.DividerColor + glyphOr(box.TopTee, box.Horizontal) + colors.Reset)
+		buffer.WriteString(MoveCursorCmd(absY+sp.Height-1, absX+offset))
+		buffer.WriteString(sp.DividerColor + glyphOr(box.BottomTee, box.Horizontal) + colors.Reset)
+	}
+	buffer.WriteString(color)
+	for row := inset; row < sp.Height-inset; row++ {
+		buffer.WriteString(MoveCursorCmd(absY+row, absX+offset))
+		buffer.WriteString(box.Vertical)
+	}
+	buffer.WriteString(colors.Reset)
+}
+
+// handleDividerDrag moves the divider of a SplitPane while it is dragged with the
+// left button. It reports whether ev was part of a drag.
+func (w *Window) handleDividerDrag(ev MouseEvent) bool {
+	if ev.Button != 0 {
+		return false
+	}
+	if sp := w.draggedSplit; sp != nil {
+		originX, originY := w.elementOrigin(sp)
+		switch {
+		case ev.Motion:
+			sp.dragTo(ev.X-originX-sp.X, ev.Y-originY-sp.Y)
+		case !ev.Pressed: // Released
+			w.draggedSplit = nil
+		}
+		return true
+	}
+	if !ev.Pressed || ev.Motion {
+		return false
+	}
+	index := w.elementAt(ev.X, ev.Y)
+	if index == -1 {
+		return false
+	}
+	sp, ok := w.focusableElements[index].(*SplitPane)
+	if !ok {
+		return false
+	}
+	originX, originY := w.elementOrigin(sp)
+	if !sp.onDivider(ev.X-originX-sp.X, ev.Y-originY-sp.Y) {
+		return false
+	}
+	if index != w.focusedIndex {
+		w.setFocus(index)
+	}
+	w.draggedSplit = sp
+	return true
+}
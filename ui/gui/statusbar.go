@@ -0,0 +1,145 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// StatusBar is a one-row bar with left, center and right aligned text, typically
+// placed on the last content row. It is drawn above other elements and stays in
+// place when the window content scrolls.
+type StatusBar struct {
+	X, Y            int    // Position relative to window content area
+	Width           int    // Width of the bar (0 = the rest of the content width)
+	BackgroundColor string // Color of the whole bar, including the gaps between texts
+	LeftText        string
+	LeftColor       string
+	CenterText      string
+	CenterColor     string
+	RightText       string
+	RightColor      string
+	zIndex          int
+	themed
+}
+
+// NewStatusBar creates an empty StatusBar at row y spanning the content width.
+func NewStatusBar(y int, backgroundColor string) *StatusBar {
+	return &StatusBar{
+		Y:               y,
+		BackgroundColor: backgroundColor,
+		zIndex:          50, // Above regular elements, below menus and prompts
+	}
+}
+
+// SetLeft sets the left-aligned text and its color.
+func (sb *StatusBar) SetLeft(text, color string) {
+	sb.LeftText, sb.LeftColor = text, color
+}
+
+// SetCenter sets the centered text and its color.
+func (sb *StatusBar) SetCenter(text, color string) {
+	sb.CenterText, sb.CenterColor = text, color
+}
+
+// SetRight sets the right-aligned text and its color.
+func (sb *StatusBar) SetRight(text, color string) {
+	sb.RightText, sb.RightColor = text, color
+}
+
+// GetZIndex implements ZIndexer.
+func (sb *StatusBar) GetZIndex() int {
+	return sb.zIndex
+}
+
+// Render draws the bar. When the texts don't all fit, the left text wins, then the
+// right one, and the center text is dropped if it would touch either.
+func (sb *StatusBar) Render(buffer *strings.Builder, winX, winY int, contentWidth int) {
+	absX := winX + sb.X
+	absY := winY + sb.Y
+	width := sb.Width
+	if width <= 0 || width > contentWidth-sb.X {
+		width = contentWidth - sb.X
+	}
+	if width <= 0 {
+		return
+	}
+	background := themeColor(sb.BackgroundColor, sb.theme().Background)
+
+	// Background across the whole bar
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	buffer.WriteString(background)
+	buffer.WriteString(strings.Repeat(" ", width))
+
+	left := truncateToWidth(sb.LeftText, width)
+	leftWidth := DisplayWidth(left)
+	right := ""
+	if room := width - leftWidth - 1; room > 0 { // Keep a space after the left text
+		right = truncateToWidth(sb.RightText, room)
+	}
+	rightWidth := DisplayWidth(right)
+
+	if left != "" {
+		buffer.WriteString(MoveCursorCmd(absY, absX))
+		buffer.WriteString(background + sb.LeftColor + left + colors.Reset)
+	}
+	if right != "" {
+		buffer.WriteString(MoveCursorCmd(absY, absX+width-rightWidth))
+		buffer.WriteString(background + sb.RightColor + right + colors.Reset)
+	}
+	if centerWidth := DisplayWidth(sb.CenterText); sb.CenterText != "" {
+		start := (width - centerWidth) / 2
+		leftEnd := leftWidth
+		rightStart := width - rightWidth
+		if leftWidth > 0 {
+			leftEnd++ // Keep a space on each side
+		}
+		if rightWidth > 0 {
+			rightStart--
+		}
+		if start >= leftEnd && start+centerWidth <= rightStart {
+			buffer.WriteString(MoveCursorCmd(absY, absX+start))
+			buffer.WriteString(background + sb.CenterColor + sb.CenterText + colors.Reset)
+		}
+	}
+	buffer.WriteString(colors.Reset)
+}
+
+// Bounds implements Bounded. A zero Width reports the width of the texts.
+func (sb *StatusBar) Bounds() (int, int, int, int) {
+	width := sb.Width
+	if width <= 0 {
+		width = DisplayWidth(sb.LeftText) + DisplayWidth(sb.CenterText) + DisplayWidth(sb.RightText)
+	}
+	return sb.X, sb.Y, width, 1
+}
+
+// SetPosition implements Positioner.
+func (sb *StatusBar) SetPosition(x, y int) {
+	sb.X, sb.Y = x, y
+}
+
+// SetSize implements Sizer; the height is always one row.
+func (sb *StatusBar) SetSize(width, height int) {
+	sb.Width = width
+}
+
+// truncateToWidth cuts s to at most width display columns, ending with "…" if cut.
+func truncateToWidth(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := DisplayWidth(string(r))
+		if used+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	return b.String() + "…"
+}
@@ -0,0 +1,350 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// TabPage is one named page of a TabPanel. Its elements are only rendered and
+// focusable while it is the panel's active tab.
+type TabPage struct {
+	Title    string
+	Elements []UIElement // Elements positioned relative to the panel's content area
+	panel    *TabPanel
+}
+
+// AddElement adds a UI element to the page.
+func (p *TabPage) AddElement(element UIElement) {
+	p.Elements = append(p.Elements, element)
+	if p.panel != nil && p.panel.window != nil && p.panel.ActivePage() == p {
+		p.panel.window.syncTabPanel(p.panel) // Make the new element focusable right away
+	}
+}
+
+// TabPanel shows one of several named pages under a tab strip. Left/Right cycle
+// the tabs while the strip is focused, and Ctrl+Tab / Shift+Ctrl+Tab from anywhere
+// inside the panel. Only the active page's elements are part of the window's focus cycle.
+type TabPanel struct {
+	X, Y          int // Position relative to window content area
+	Width, Height int // Dimensions, including the tab strip
+	Pages         []*TabPage
+	ActiveTab     int    // Index of the page being shown
+	Color         string // Color of the tab strip
+	SelectedColor string // Color of the active tab's title
+	ActiveColor   string // Color of the active tab's title when the strip is focused
+	IsActive      bool   // State for rendering/input handling
+	Disabled      bool   // Disabled panels are dimmed and skipped by focus traversal
+	Hidden        bool   // Hidden panels are not rendered and are skipped by focus traversal
+	OnTabChanged  func(index int)
+	enableState   enableGroup // Tracks page elements disabled via SetEnabled
+	window        *Window     // Window the panel was added to, for focus bookkeeping
+	themed
+}
+
+// NewTabPanel creates a new TabPanel without pages; add them with AddTab.
+func NewTabPanel(x, y, width, height int, color, selectedColor, activeColor string) *TabPanel {
+	return &TabPanel{
+		X:             x,
+		Y:             y,
+		Width:         width,
+		Height:        height,
+		Pages:         make([]*TabPage, 0),
+		Color:         color,
+		SelectedColor: selectedColor,
+		ActiveColor:   activeColor,
+		IsActive:      false,
+	}
+}
+
+// AddTab appends a new page with the given title and returns it.
+func (tp *TabPanel) AddTab(title string) *TabPage {
+	page := &TabPage{Title: title, Elements: make([]UIElement, 0), panel: tp}
+	tp.Pages = append(tp.Pages, page)
+	return page
+}
+
+// ActivePage returns the page being shown, or nil if the panel has no pages.
+func (tp *TabPanel) ActivePage() *TabPage {
+	if tp.ActiveTab < 0 || tp.ActiveTab >= len(tp.Pages) {
+		return nil
+	}
+	return tp.Pages[tp.ActiveTab]
+}
+
+// SetActiveTab shows the page at index and calls OnTabChanged.
+// Out of range indexes are ignored.
+func (tp *TabPanel) SetActiveTab(index int) {
+	if index < 0 || index >= len(tp.Pages) || index == tp.ActiveTab {
+		return
+	}
+	tp.ActiveTab = index
+	if tp.window != nil {
+		tp.window.syncTabPanel(tp)
+	}
+	if tp.OnTabChanged != nil {
+		tp.OnTabChanged(index)
+	}
+}
+
+// NextTab shows the next page, wrapping around to the first.
+func (tp *TabPanel) NextTab() {
+	if len(tp.Pages) > 0 {
+		tp.SetActiveTab((tp.ActiveTab + 1) % len(tp.Pages))
+	}
+}
+
+// PreviousTab shows the previous page, wrapping around to the last.
+func (tp *TabPanel) PreviousTab() {
+	if len(tp.Pages) > 0 {
+		tp.SetActiveTab((tp.ActiveTab - 1 + len(tp.Pages)) % len(tp.Pages))
+	}
+}
+
+// tabAt returns the index of the tab whose title is at column col of the strip, or -1.
+func (tp *TabPanel) tabAt(col int) int {
+	used := 0
+	for i, page := range tp.Pages {
+		titleWidth := DisplayWidth(" " + page.Title + " ")
+		if i > 0 {
+			used++ // Separator
+		}
+		if used+titleWidth > tp.Width {
+			break // Tabs past here are not drawn
+		}
+		if col >= used && col < used+titleWidth {
+			return i
+		}
+		used += titleWidth
+	}
+	return -1
+}
+
+// contains reports whether element belongs to one of the panel's pages, directly
+// or inside a segment on it.
+func (tp *TabPanel) contains(element UIElement) bool {
+	for _, page := range tp.Pages {
+		if containsElement(page.Elements, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentParent returns the Segment or SegmentGroup on one of the panel's pages
+// that holds element directly, or nil.
+func (tp *TabPanel) segmentParent(element UIElement) UIElement {
+	for _, page := range tp.Pages {
+		if parent := segmentParent(page.Elements, element); parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
+
+// SetActive sets the focus state of the tab strip.
+func (tp *TabPanel) SetActive(active bool) {
+	tp.IsActive = active
+}
+
+// Render draws the tab strip and the active page's elements.
+func (tp *TabPanel) Render(buffer *strings.Builder, winX, winY int, _ int) {
+	absX := winX + tp.X
+	absY := winY + tp.Y
+
+	stripColor := themeColor(tp.Color, tp.theme().Accent)
+	if tp.Disabled {
+		stripColor = tp.disabledColor()
+	}
+
+	// 1. Tab strip: titles separated by vertical bars, the active one highlighted
+	buffer.WriteString(MoveCursorCmd(absY, absX))
+	box := frameBox("single")
+	used := 0
+	for i, page := range tp.Pages {
+		title := " " + page.Title + " "
+		titleWidth := DisplayWidth(title)
+		if used+titleWidth > tp.Width {
+			break // No room for more tabs
+		}
+		if i > 0 {
+			if used+titleWidth+1 > tp.Width {
+				break
+			}
+			buffer.WriteString(stripColor + box.Vertical)
+			used++
+		}
+		if i == tp.ActiveTab && !tp.Disabled {
+			if tp.IsActive {
+				buffer.WriteString(themeColor(tp.ActiveColor, tp.theme().Active()))
+			} else {
+				buffer.WriteString(themeColor(tp.SelectedColor, tp.theme().Selection))
+			}
+			buffer.WriteString(ReverseVideo())
+		} else {
+			buffer.WriteString(stripColor)
+		}
+		buffer.WriteString(title)
+		buffer.WriteString(colors.Reset)
+		used += titleWidth
+	}
+	if used < tp.Width {
+		buffer.WriteString(strings.Repeat(" ", tp.Width-used)) // Clear the rest of the strip
+	}
+
+	// 2. Line under the strip
+	buffer.WriteString(MoveCursorCmd(absY+1, absX))
+	buffer.WriteString(stripColor)
+	buffer.WriteString(strings.Repeat(box.Horizontal, tp.Width))
+	buffer.WriteString(colors.Reset)
+
+	// 3. Clear the page area so the previous page's elements don't linger
+	for row := 2; row < tp.Height; row++ {
+		buffer.WriteString(MoveCursorCmd(absY+row, absX))
+		buffer.WriteString(strings.Repeat(" ", tp.Width))
+	}
+
+	// 4. Active page's elements, relative to the area under the strip
+	if page := tp.ActivePage(); page != nil {
+		for _, element := range page.Elements {
+			if isVisible(element) {
+				renderElement(element, tp.windowTheme, buffer, absX, absY+2, tp.Width)
+			}
+		}
+	}
+}
+
+// activeCursorManager returns the active page's element that wants the cursor, if any.
+func (tp *TabPanel) activeCursorManager() CursorManager {
+	page := tp.ActivePage()
+	if page == nil {
+		return nil
+	}
+	for _, element := range page.Elements {
+		if cm, ok := element.(CursorManager); ok && cm.NeedsCursor() && isVisible(element) {
+			return cm
+		}
+	}
+	return nil
+}
+
+// HandleInput implements InputHandler: Left/Right switch tabs while the tab strip
+// is focused. Tab and Enter are left to the window, which moves focus into the page.
+func (tp *TabPanel) HandleInput(ev InputEvent) (bool, bool, bool) {
+	if !tp.IsActive {
+		return false, false, false
+	}
+	switch ev.Key {
+	case KeyLeft: // Previous tab
+		tp.PreviousTab()
+	case KeyRight: // Next tab
+		tp.NextTab()
+	default:
+		return false, false, false
+	}
+	return true, true, false
+}
+
+// NeedsCursor implements CursorManager interface; the panel asks for the cursor
+// on behalf of an active element on its current page.
+func (tp *TabPanel) NeedsCursor() bool {
+	return tp.activeCursorManager() != nil
+}
+
+// GetCursorPosition implements CursorManager interface
+func (tp *TabPanel) GetCursorPosition() (int, int, bool) {
+	if cm := tp.activeCursorManager(); cm != nil {
+		return cm.GetCursorPosition()
+	}
+	return 0, 0, false
+}
+
+// GetCursorShape implements CursorShaper for the element holding the cursor.
+func (tp *TabPanel) GetCursorShape() CursorShape {
+	if shaper, ok := tp.activeCursorManager().(CursorShaper); ok {
+		return shaper.GetCursorShape()
+	}
+	return CursorDefault
+}
+
+// Bounds implements Bounded.
+func (tp *TabPanel) Bounds() (int, int, int, int) {
+	return tp.X, tp.Y, tp.Width, tp.Height
+}
+
+// SetPosition implements Positioner.
+func (tp *TabPanel) SetPosition(x, y int) {
+	tp.X, tp.Y = x, y
+}
+
+// SetSize implements Sizer.
+func (tp *TabPanel) SetSize(width, height int) {
+	tp.Width, tp.Height = width, height
+}
+
+// SetEnabled implements Enabler. It enables or disables the tab strip and the
+// elements of every page. Re-enabling leaves elements that were disabled
+// individually disabled.
+func (tp *TabPanel) SetEnabled(enabled bool) {
+	tp.Disabled = !enabled
+	var elements []UIElement
+	for _, page := range tp.Pages {
+		elements = append(elements, page.Elements...)
+	}
+	tp.enableState.set(enabled, elements)
+}
+
+// IsEnabled implements Enabler.
+func (tp *TabPanel) IsEnabled() bool {
+	return !tp.Disabled
+}
+
+// SetVisible implements Hider.
+func (tp *TabPanel) SetVisible(visible bool) {
+	tp.Hidden = !visible
+}
+
+// IsVisible implements Hider.
+func (tp *TabPanel) IsVisible() bool {
+	return !tp.Hidden
+}
+
+// syncTabPanel updates the focus list after panel changed tabs (or its active page
+// changed): only the active page's elements are focusable, right after the panel's
+// tab strip. Focus inside a hidden page moves to the strip.
+func (w *Window) syncTabPanel(panel *TabPanel) {
+	var children, shown []UIElement
+	for _, page := range panel.Pages {
+		children = append(children, page.Elements...)
+	}
+	if page := panel.ActivePage(); page != nil {
+		shown = page.Elements
+	}
+	w.syncChildFocus(panel, children, shown)
+}
+
+// tabPanelFor returns the window's TabPanel that is, or contains, element.
+func (w *Window) tabPanelFor(element UIElement) *TabPanel {
+	if element == nil {
+		return nil
+	}
+	for _, el := range w.Elements {
+		if panel, ok := el.(*TabPanel); ok && (el == element || panel.contains(element)) {
+			return panel
+		}
+	}
+	return nil
+}
+
+// tabSwitchKey reports whether ev is Ctrl+Tab (1) or Shift+Ctrl+Tab (-1); it returns
+// 0 for any other key. Terminals only report these with modifyOtherKeys or CSI u.
+func tabSwitchKey(ev InputEvent) int {
+	switch {
+	case ev.Key != KeyTab && ev.Key != KeyShiftTab:
+		return 0
+	case ev.Mod == ModCtrl:
+		return 1
+	case ev.Mod == ModCtrl|ModShift:
+		return -1
+	}
+	return 0
+}
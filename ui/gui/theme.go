@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"strings"
+	"window-go/colors"
+)
+
+// Theme is a set of default colors for a window and its elements. Colors left
+// empty in a window or element (e.g. NewButton("OK", x, y, w, "", "", action))
+// are taken from the theme of the window rendering them.
+type Theme struct {
+	Border     string // Window border
+	Title      string // Window title
+	Background string // Window background
+	Content    string // Labels and other plain content
+	Accent     string // Interactive elements: buttons, inputs, lists, ...
+	ActiveBg   string // Background of the focused element
+	ActiveFg   string // Foreground of the focused element
+	Selection  string // Selected rows and tabs
+	Disabled   string // Disabled elements (falls back to DisabledColor)
+}
+
+// Active returns the color of the focused element.
+func (t *Theme) Active() string {
+	return t.ActiveBg + t.ActiveFg
+}
+
+// DarkTheme is light text on a black background with cyan accents.
+var DarkTheme = &Theme{
+	Border:     colors.Cyan,
+	Title:      colors.BoldCyan,
+	Background: colors.BgBlack,
+	Content:    colors.White,
+	Accent:     colors.Cyan,
+	ActiveBg:   colors.BgCyan,
+	ActiveFg:   colors.BoldBlack,
+	Selection:  colors.BgBlue + colors.BoldWhite,
+	Disabled:   colors.Gray,
+}
+
+// LightTheme is dark text on a white background with blue accents.
+var LightTheme = &Theme{
+	Border:     colors.Blue,
+	Title:      colors.BoldBlue,
+	Background: colors.BgWhite,
+	Content:    colors.Black,
+	Accent:     colors.Blue,
+	ActiveBg:   colors.BgBlue,
+	ActiveFg:   colors.BoldWhite,
+	Selection:  colors.BgCyan + colors.Black,
+	Disabled:   colors.Gray,
+}
+
+// themed is embedded in the built-in elements to hold the theme of the window
+// rendering them, which renderElement hands over before each Render.
+type themed struct {
+	windowTheme *Theme
+}
+
+// setTheme sets the theme the element's empty colors come from.
+func (t *themed) setTheme(theme *Theme) {
+	t.windowTheme = theme
+}
+
+// theme returns the theme of the window that last rendered the element, or an
+// empty theme.
+func (t *themed) theme() *Theme {
+	if t.windowTheme == nil {
+		return &Theme{}
+	}
+	return t.windowTheme
+}
+
+// disabledColor returns the color disabled elements are drawn with.
+func (t *themed) disabledColor() string {
+	return themeColor(t.theme().Disabled, DisabledColor)
+}
+
+// renderElement renders element with its empty colors taken from theme, if it
+// is a built-in element (custom elements choose their own colors).
+func renderElement(element UIElement, theme *Theme, buffer *strings.Builder, winX, winY, width int) {
+	if t, ok := element.(interface{ setTheme(*Theme) }); ok {
+		t.setTheme(theme)
+	}
+	element.Render(buffer, winX, winY, width)
+}
+
+// theme returns the window's theme, or an empty theme.
+func (w *Window) theme() *Theme {
+	if w.Theme == nil {
+		return &Theme{}
+	}
+	return w.Theme
+}
+
+// themeColor returns color, or fallback (a theme color) if color is empty.
+func themeColor(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWindowsRenderWithTheirOwnTheme(t *testing.T) {
+	red := &Theme{Content: "\x1b[31m", ActiveBg: "\x1b[41m"}
+	blue := &Theme{Content: "\x1b[34m", ActiveBg: "\x1b[44m"}
+	newWindow := func(theme *Theme) *Window {
+		w := NewWindow("", "T", 0, 0, 30, 6, "single", "", "", "", "")
+		w.Theme = theme
+		w.AddElement(NewLabel("label", 1, 1, ""))
+		segment := NewSegment(1, 2, 20, 3, "")
+		segment.AddElement(NewButton("OK", 1, 0, 6, "", "", nil)) // Focused, so drawn in ActiveBg
+		w.AddElement(segment)
+		return w
+	}
+	windows := []*Window{newWindow(red), newWindow(blue)}
+
+	var wg sync.WaitGroup
+	for _, w := range windows {
+		wg.Add(1)
+		go func(w *Window) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				w.RenderToString()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, w := range windows {
+		out := w.RenderToString()
+		other := blue
+		if w.Theme == blue {
+			other = red
+		}
+		if !themedLabel(w.Theme).MatchString(out) {
+			t.Errorf("label isn't drawn with its window's Content color %q", w.Theme.Content)
+		}
+		if !strings.Contains(out, w.Theme.ActiveBg+"[  OK  ]") {
+			t.Errorf("button in a segment isn't drawn with its window's ActiveBg color %q", w.Theme.ActiveBg)
+		}
+		if strings.Contains(out, other.Content) || strings.Contains(out, other.ActiveBg) {
+			t.Errorf("window uses the other window's theme colors")
+		}
+	}
+
+	// An element keeps its window's theme when rendered on its own
+	var buffer strings.Builder
+	windows[0].Elements[0].Render(&buffer, 0, 0, 10)
+	if !themedLabel(red).MatchString(buffer.String()) {
+		t.Errorf("label rendered outside the window = %q, want its window's Content color", buffer.String())
+	}
+}
+
+// themedLabel matches "label" drawn in theme's Content color.
+func themedLabel(theme *Theme) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(theme.Content) + `(\x1b\[\d+;\d+H)?label`)
+}
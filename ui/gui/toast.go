@@ -0,0 +1,89 @@
+package gui
+
+import (
+	"strings"
+	"time"
+	"window-go/colors"
+)
+
+// defaultToastDuration is how long a toast shows when ShowToast gets no duration.
+const defaultToastDuration = 3 * time.Second
+
+// toast is a transient message shown by ShowToast.
+type toast struct {
+	message string
+	color   string
+	expires time.Time
+}
+
+// ShowToast shows message in the top-right corner of the window's content area for
+// duration (3 seconds if 0), drawn in color, e.g. colors.BgGreen+colors.BoldWhite for
+// a success (the theme's selection color, or white on blue, if empty). Toasts are
+// drawn above every element; several stack downwards, oldest first, and move up as
+// older ones expire.
+// WindowActions re-renders the window when one expires; elsewhere expired toasts
+// disappear on the next Render.
+func (w *Window) ShowToast(message, color string, duration time.Duration) {
+	if duration <= 0 {
+		duration = defaultToastDuration
+	}
+	w.toasts = append(w.toasts, toast{
+		message: strings.Join(strings.Fields(message), " "), // One row: line breaks become spaces
+		color:   color,
+		expires: time.Now().Add(duration),
+	})
+}
+
+// ClearToasts removes all toasts.
+func (w *Window) ClearToasts() {
+	w.toasts = nil
+}
+
+// expireToasts removes the toasts that expired by now, reporting whether any were removed.
+func (w *Window) expireToasts(now time.Time) bool {
+	kept := w.toasts[:0]
+	for _, t := range w.toasts {
+		if now.Before(t.expires) {
+			kept = append(kept, t)
+		}
+	}
+	expired := len(kept) < len(w.toasts)
+	w.toasts = kept
+	return expired
+}
+
+// nextToastExpiry returns a channel that fires when the next toast expires, or nil
+// (which never fires) if there are no toasts.
+func (w *Window) nextToastExpiry() <-chan time.Time {
+	if len(w.toasts) == 0 {
+		return nil
+	}
+	next := w.toasts[0].expires
+	for _, t := range w.toasts[1:] {
+		if t.expires.Before(next) {
+			next = t.expires
+		}
+	}
+	return time.After(time.Until(next))
+}
+
+// renderToasts draws the toasts in the top-right corner of the content area, one
+// per row, as many as fit (the newest ones if not all do).
+func (w *Window) renderToasts(contentX, contentY, contentWidth, contentHeight int) {
+	w.expireToasts(time.Now())
+	toasts := w.toasts
+	if len(toasts) > contentHeight {
+		toasts = toasts[len(toasts)-contentHeight:]
+	}
+	for i, t := range toasts {
+		text := " " + truncateToWidth(t.message, contentWidth-2) + " "
+		if contentWidth < 2 {
+			text = truncateToWidth(t.message, contentWidth)
+		}
+		x := contentX + contentWidth - DisplayWidth(text)
+		w.buffer.WriteString(MoveCursorCmd(contentY+i, x))
+		w.buffer.WriteString(themeColor(t.color, themeColor(w.theme().Selection, colors.BgBlue+colors.BoldWhite)))
+		w.buffer.WriteString(text)
+		w.buffer.WriteString(colors.Reset)
+	}
+}
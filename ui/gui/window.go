@@ -3,9 +3,14 @@ package gui
 import (
 	"bufio" // Keep for potential future use, but not for raw input loop
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 	"unicode/utf8"
 	"window-go/colors"
 
@@ -27,7 +32,27 @@ type KeyStrokeHandler interface {
 // UIElement represents any element that can be rendered within a window.
 type UIElement interface {
 	Render(buffer *strings.Builder, x, y int, width int) // Renders the element onto a buffer at given coords
-	// Add methods for interaction later if needed (e.g., HandleInput)
+}
+
+// InputHandler is implemented by elements that take input. Elements implementing
+// it are focusable; WindowActions decodes each key once and passes it to the
+// focused element, and passes mouse clicks and wheel events to the element under
+// the pointer. Keys the element doesn't handle get the window's own bindings:
+// Tab and Shift+Tab move focus, Enter presses a button or moves focus, q and
+// Ctrl+C quit.
+type InputHandler interface {
+	// HandleInput processes an input event.
+	// It returns:
+	// - handled: true if the event was used by the element, false otherwise.
+	// - needsRender: true if the window should be re-rendered.
+	// - shouldQuit: true if the application should quit.
+	HandleInput(ev InputEvent) (handled bool, needsRender bool, shouldQuit bool)
+}
+
+// Activator is implemented by elements that show when they have focus. The window
+// calls SetActive as the focus moves to and from them.
+type Activator interface {
+	SetActive(active bool)
 }
 
 // --- Window Structure ---
@@ -44,10 +69,59 @@ type Window struct {
 	BgColor           string // Background color for the content area
 	ContentColor      string // Default text color for content area (can be overridden by elements)
 	Elements          []UIElement
-	buffer            strings.Builder  // Internal buffer for drawing commands
-	focusableElements []UIElement      // Slice to hold focusable elements (like buttons)
-	focusedIndex      int              // Index of the currently focused element in focusableElements
-	KeyHandler        KeyStrokeHandler // Optional custom key stroke handler
+	buffer            strings.Builder                 // Internal buffer for drawing commands
+	focusableElements []UIElement                     // Slice to hold focusable elements (like buttons)
+	focusedIndex      int                             // Index of the currently focused element in focusableElements
+	KeyHandler        KeyStrokeHandler                // Optional custom key stroke handler
+	InitialFocus      UIElement                       // Optional element to focus when WindowActions starts (see SetInitialFocus)
+	Scrollable        bool                            // Enables window-level scrolling when elements exceed the content height
+	scrollOffset      int                             // Number of content rows scrolled off the top (when Scrollable)
+	scrollBar         *ScrollBar                      // Window scrollbar drawn over the right border (when Scrollable)
+	helpTexts         map[UIElement]string            // Help text per element, shown in the help overlay (see SetHelpText)
+	helpOverlay       *helpOverlay                    // Open help overlay, or nil
+	CursorColor       string                          // Optional cursor color spec (e.g. "#ff8800"); empty keeps the terminal's
+	cursorShape       CursorShape                     // Cursor shape last emitted by Render
+	textCursor        CursorShape                     // Cursor shape for elements that don't choose one (see SetCursorShape)
+	cursorColor       string                          // Cursor color last emitted by Render
+	Resizable         bool                            // Allows interactive resizing with Ctrl+R in WindowActions
+	MinWidth          int                             // Smallest width allowed by Resize (at least 3)
+	MinHeight         int                             // Smallest height allowed by Resize (at least 3)
+	MaxWidth          int                             // Largest width allowed by Resize (0 = up to the terminal edge)
+	MaxHeight         int                             // Largest height allowed by Resize (0 = up to the terminal edge)
+	OnResize          func(newWidth, newHeight int)   // Called after the size changes, to reflow the layout
+	resizing          bool                            // True while in interactive resize mode
+	Movable           bool                            // Allows moving with Ctrl+G or by dragging the title bar in WindowActions
+	ReadlineKeys      bool                            // Enables Ctrl+A/E/K/U/D/Y (start, end, kill to end, kill line, delete, yank) in TextBoxes
+	moving            bool                            // True while in interactive move mode
+	dragging          bool                            // True while the title bar is dragged with the mouse
+	dragOffset        int                             // Column of the drag start, relative to X
+	draggedSplit      *SplitPane                      // SplitPane whose divider is dragged with the mouse, or nil
+	titleChanged      bool                            // True once SetTerminalTitle has changed the terminal title
+	renderMu          sync.Mutex                      // Serializes Render, RenderTo and RenderToString
+	MouseEnabled      bool                            // Enables mouse clicks and the scroll wheel in WindowActions
+	DisableDiff       bool                            // Makes Render redraw everything every frame instead of only the changed cells
+	frontScreen       *screen                         // Frame last written to the terminal, for differential rendering
+	OnTerminalResize  func(termWidth, termHeight int) // Called by WindowActions when the terminal is resized; defaults to shrinking the window to fit
+	tickInterval      time.Duration                   // Interval between onTick calls in WindowActions (see SetTicker)
+	onTick            func(*Window) bool              // Timed update, returns true to re-render
+	spinners          chan *Spinner                   // Ticks of spinners started with StartAuto, applied by WindowActions
+	pendingAction     func() bool                     // Action confirmed in a WithConfirm dialog, run by handleInput like a button press
+	toasts            []toast                         // Transient messages, oldest first (see ShowToast)
+	keyMap            KeyMap                          // Key bindings for the built-in actions (nil = DefaultKeyMap)
+	functionKeys      map[int]func() bool             // Actions bound to F1–F12 by number (see BindFunctionKey)
+	OnFocusChange     func(old, new UIElement)        // Called after focus moves; either may be nil
+	Theme             *Theme                          // Default colors for empty color fields of the window and its elements (e.g. DarkTheme)
+	ZIndex            int                             // Stacking order in a WindowManager; higher windows are drawn on top
+	manager           *WindowManager                  // Manager the window belongs to, or nil
+	managedFrame      string                          // Commands of the last Render, composed by the manager
+	Shadow            bool                            // Draws a drop shadow below and right of the window
+	ShadowColor       string                          // Background color of the shadow (empty = dark gray)
+	named             map[string]UIElement            // Elements by their layout name (see LoadWindow)
+	layouts           []Layout                        // Layouts arranged before each render (see AddLayout)
+	constraints       map[Placeable]Constraints       // Constraints resolved before each render (see SetConstraints)
+	Output            io.Writer                       // Where the window writes to the terminal (nil = os.Stdout); unused while in a WindowManager
+	vDividers         []divider                       // Vertical dividers (see AddVerticalDivider)
+	hDividers         []divider                       // Horizontal dividers (see AddHorizontalDivider)
 }
 
 // NewWindow creates a new Window instance.
@@ -71,7 +145,352 @@ func NewWindow(icon, title string, x, y, width, height int, boxStyle, titleColor
 		focusableElements: make([]UIElement, 0), // Initialize focusable elements slice
 		focusedIndex:      -1,                   // No element focused initially
 		KeyHandler:        nil,                  // Initialize custom key handler as nil
+		textCursor:        CursorBlinkingBar,    // Text fields show a bar unless told otherwise
+	}
+}
+
+// Resize changes the window size, clamped to MinWidth/MinHeight, MaxWidth/MaxHeight
+// and the terminal size. Cells the window no longer covers are cleared, and OnResize
+// is called so the app can reflow its layout. It returns false if the size didn't change.
+func (w *Window) Resize(width, height int) bool {
+	width, height = w.clampSize(width, height)
+	if width == w.Width && height == w.Height {
+		return false
+	}
+
+	// Clear the old area so shrinking doesn't leave a ghost border behind;
+	// the next Render redraws the new area.
+	fmt.Fprint(w.output(), w.clearFootprint())
+	w.Invalidate()
+
+	w.Width = width
+	w.Height = height
+	if w.Scrollable {
+		w.updateContentScroll()
+	}
+	if w.OnResize != nil {
+		w.OnResize(width, height)
+	}
+	return true
+}
+
+// Recenter centers the window in the terminal, shrinking it first if it doesn't fit.
+func (w *Window) Recenter() {
+	termWidth, termHeight := GetTerminalWidth(), GetTerminalHeight()
+	width, height := w.Width, w.Height
+	if width > termWidth {
+		width = termWidth
+	}
+	if height > termHeight {
+		height = termHeight
+	}
+	w.MoveTo((termWidth-width)/2, (termHeight-height)/2)
+	w.Resize(width, height)
+}
+
+// MoveTo moves the window's top-left corner to (x, y), keeping it on the terminal.
+// It clears the old area and invalidates the screen, so the next Render redraws
+// the whole window at its new place. It reports whether the position changed.
+func (w *Window) MoveTo(x, y int) bool {
+	if maxX := GetTerminalWidth() - w.Width; x > maxX {
+		x = maxX
+	}
+	if maxY := GetTerminalHeight() - w.Height; y > maxY {
+		y = maxY
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	if x == w.X && y == w.Y {
+		return false
+	}
+
+	fmt.Fprint(w.output(), w.clearFootprint()) // Don't leave the old border behind
+	w.Invalidate()
+	w.X, w.Y = x, y
+	return true
+}
+
+// MoveBy moves the window by (dx, dy) cells (see MoveTo).
+func (w *Window) MoveBy(dx, dy int) bool {
+	return w.MoveTo(w.X+dx, w.Y+dy)
+}
+
+// handleTerminalResize reflows the window after the terminal changed size, through
+// OnTerminalResize or by shrinking the window to fit, and redraws it on a clear screen.
+func (w *Window) handleTerminalResize() {
+	w.fitTerminal()
+	fmt.Fprint(w.output(), ClearScreenAndBuffer()) // The terminal may have rewrapped what was on screen
+	w.Invalidate()
+	w.Render()
+}
+
+// fitTerminal reflows the window for the current terminal size, through
+// OnTerminalResize or by shrinking the window to fit.
+func (w *Window) fitTerminal() {
+	if w.OnTerminalResize != nil {
+		w.OnTerminalResize(GetTerminalWidth(), GetTerminalHeight())
+	} else {
+		w.Resize(w.Width, w.Height) // Resize clamps to the terminal size
+	}
+}
+
+// SetTicker makes WindowActions call fn every interval while it waits for input,
+// for animations and other timed updates; fn returns true to re-render the window.
+// A nil fn or non-positive interval removes the ticker.
+func (w *Window) SetTicker(interval time.Duration, fn func(*Window) bool) {
+	w.tickInterval = interval
+	w.onTick = fn
+}
+
+// spinnerTicks returns the channel Spinner.StartAuto sends its ticks on: the
+// manager's if the window belongs to one, as only the manager reads input then.
+func (w *Window) spinnerTicks() chan *Spinner {
+	if w.manager != nil {
+		if w.manager.spinners == nil {
+			w.manager.spinners = make(chan *Spinner)
+		}
+		return w.manager.spinners
+	}
+	if w.spinners == nil {
+		w.spinners = make(chan *Spinner)
+	}
+	return w.spinners
+}
+
+// activeModal returns the topmost active modal prompt of the window, or nil. While
+// there is one, it receives all input and focus can't leave it.
+func (w *Window) activeModal() *Prompt {
+	var modal *Prompt
+	for _, element := range w.Elements {
+		if p, ok := element.(*Prompt); ok && p.IsModal() {
+			if modal == nil || p.zIndex >= modal.zIndex {
+				modal = p
+			}
+		}
+	}
+	return modal
+}
+
+// expirePrompts closes and removes the prompts whose Timeout has passed,
+// reporting whether any were removed.
+func (w *Window) expirePrompts() bool {
+	now := time.Now()
+	var expired []*Prompt
+	for _, element := range w.Elements {
+		if p, ok := element.(*Prompt); ok && p.timedOut(now) {
+			expired = append(expired, p)
+		}
+	}
+	for _, p := range expired {
+		p.SetActive(false)
+		w.RemoveElement(p)
+		if p.OnTimeout != nil {
+			p.OnTimeout()
+		}
+	}
+	return len(expired) > 0
+}
+
+// readInput reads the next chunk of input into buf in the background and delivers
+// it on the returned channel, which is closed instead if the read fails. Only one
+// read is started at a time, so button actions can read stdin themselves.
+func readInput(buf []byte) <-chan []byte {
+	ch := make(chan []byte, 1)
+	go func() {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(ch)
+			return
+		}
+		ch <- buf[:n]
+	}()
+	return ch
+}
+
+// clampSize limits a requested window size to the configured and terminal bounds.
+func (w *Window) clampSize(width, height int) (int, int) {
+	maxWidth := GetTerminalWidth() - w.X
+	if w.MaxWidth > 0 && w.MaxWidth < maxWidth {
+		maxWidth = w.MaxWidth
+	}
+	maxHeight := GetTerminalHeight() - w.Y
+	if w.MaxHeight > 0 && w.MaxHeight < maxHeight {
+		maxHeight = w.MaxHeight
+	}
+	if width > maxWidth {
+		width = maxWidth
 	}
+	if height > maxHeight {
+		height = maxHeight
+	}
+
+	minWidth, minHeight := w.MinWidth, w.MinHeight
+	if minWidth < 3 {
+		minWidth = 3 // Room for the borders
+	}
+	if minHeight < 3 {
+		minHeight = 3
+	}
+	if width < minWidth {
+		width = minWidth
+	}
+	if height < minHeight {
+		height = minHeight
+	}
+	return width, height
+}
+
+// clearArea returns the commands to blank a rectangle of the screen.
+func clearArea(x, y, width, height int) string {
+	var b strings.Builder
+	b.WriteString(colors.Reset)
+	for row := 0; row < height; row++ {
+		b.WriteString(MoveCursorCmd(y+row, x))
+		b.WriteString(strings.Repeat(" ", width))
+	}
+	return b.String()
+}
+
+// clearFootprint returns the commands to blank the window's area, including its shadow.
+func (w *Window) clearFootprint() string {
+	if w.Shadow {
+		return clearArea(w.X, w.Y, w.Width+1, w.Height+1)
+	}
+	return clearArea(w.X, w.Y, w.Width, w.Height)
+}
+
+// renderShadow paints the drop shadow of a width x height box at (x, y): one
+// column along its right side and one row along its bottom, offset by one cell.
+// Empty color uses a dark gray. Cells beyond the terminal edges are skipped.
+func renderShadow(buffer *strings.Builder, x, y, width, height int, color string) {
+	if color == "" {
+		color = colors.BgGray2
+	}
+	termWidth, termHeight := GetTerminalWidth(), GetTerminalHeight()
+	right := x + width
+	bottom := y + height
+
+	buffer.WriteString(colors.Reset)
+	buffer.WriteString(color)
+	if right >= 0 && right < termWidth {
+		for row := y + 1; row < bottom && row < termHeight; row++ {
+			if row >= 0 {
+				buffer.WriteString(MoveCursorCmd(row, right))
+				buffer.WriteString(" ")
+			}
+		}
+	}
+	if bottom >= 0 && bottom < termHeight {
+		start := x + 1
+		if start < 0 {
+			start = 0
+		}
+		end := right + 1 // Includes the corner cell
+		if end > termWidth {
+			end = termWidth
+		}
+		if end > start {
+			buffer.WriteString(MoveCursorCmd(bottom, start))
+			buffer.WriteString(strings.Repeat(" ", end-start))
+		}
+	}
+	buffer.WriteString(colors.Reset)
+}
+
+// handleResizeKey handles a key while the window is in interactive resize mode:
+// arrows change the size, Enter, Escape or Ctrl+R leave the mode.
+func (w *Window) handleResizeKey(ev InputEvent) {
+	switch {
+	case ev.Key == KeyUp: // Shorter
+		w.Resize(w.Width, w.Height-1)
+	case ev.Key == KeyDown: // Taller
+		w.Resize(w.Width, w.Height+1)
+	case ev.Key == KeyRight: // Wider
+		w.Resize(w.Width+1, w.Height)
+	case ev.Key == KeyLeft: // Narrower
+		w.Resize(w.Width-1, w.Height)
+	case ev.Key == KeyEnter, ev.Key == KeyEscape, ev.IsCtrl('r'): // Leave resize mode
+		w.resizing = false
+	}
+}
+
+// handleMoveKey handles a key while the window is in interactive move mode:
+// arrows move the window, Enter, Escape or Ctrl+G leave the mode.
+func (w *Window) handleMoveKey(ev InputEvent) {
+	switch {
+	case ev.Key == KeyUp:
+		w.MoveBy(0, -1)
+	case ev.Key == KeyDown:
+		w.MoveBy(0, 1)
+	case ev.Key == KeyRight:
+		w.MoveBy(1, 0)
+	case ev.Key == KeyLeft:
+		w.MoveBy(-1, 0)
+	case ev.Key == KeyEnter, ev.Key == KeyEscape, ev.IsCtrl('g'): // Leave move mode
+		w.moving = false
+	}
+}
+
+// mnemonicButton returns the focusable button whose Mnemonic key is, ignoring case:
+// Alt+letter, or the letter alone while no text field takes the keys. It returns
+// nil if there is none.
+func (w *Window) mnemonicButton(ev InputEvent) *Button {
+	switch {
+	case ev.Key != KeyRune:
+		return nil
+	case ev.Mod == ModAlt: // Alt+letter
+	case ev.Mod != 0 || utf8.RuneCountInString(ev.Text) != 1:
+		return nil
+	case w.takesTextInput():
+		return nil // The letter is typed into the field
+	}
+	for _, element := range w.focusableElements {
+		if btn, ok := element.(*Button); ok && btn.Mnemonic != 0 && isFocusEnabled(btn) && unicode.ToLower(btn.Mnemonic) == unicode.ToLower(ev.Rune) {
+			return btn
+		}
+	}
+	return nil
+}
+
+// runButtonAction runs btn's Action with the terminal restored to its normal mode
+// (in case the action prints outside the UI area), then re-enters raw mode. Buttons
+// made with WithConfirm only open their dialog, so they stay in raw mode.
+// It returns true if the action signaled quit or raw mode couldn't be restored.
+func (w *Window) runButtonAction(btn *Button, fd int, oldState *term.State) bool {
+	if btn.confirms {
+		return btn.Action()
+	}
+	return w.runAction(btn.Action, fd, oldState)
+}
+
+// runAction runs action the way runButtonAction runs a button's Action.
+func (w *Window) runAction(action func() bool, fd int, oldState *term.State) bool {
+	if w.MouseEnabled {
+		fmt.Fprint(w.output(), mouseDisable) // Don't leak mouse reports into the action's output
+	}
+	fmt.Fprint(w.output(), bracketedPasteDisable)
+	term.Restore(fd, oldState)
+	fmt.Fprint(w.output(), ClearScreenAndBuffer()) // Clear UI before action output
+	w.Invalidate()                                 // The next Render starts from a blank screen
+
+	if action() { // Execute action
+		return true
+	}
+
+	// Action didn't quit: re-setup terminal and UI
+	if _, err := term.MakeRaw(fd); err != nil { // Re-enter raw mode
+		fmt.Fprintf(w.output(), "Error re-entering raw mode: %v\n", err)
+		return true // Quit if we can't restore raw mode
+	}
+	if w.MouseEnabled {
+		fmt.Fprint(w.output(), mouseEnable)
+	}
+	fmt.Fprint(w.output(), bracketedPasteEnable)
+	return false
 }
 
 // SetKeyStrokeHandler sets a custom key stroke handler for the window.
@@ -83,7 +502,46 @@ func (w *Window) SetKeyStrokeHandler(handler KeyStrokeHandler) {
 func (w *Window) AddElement(element UIElement) {
 	w.Elements = append(w.Elements, element)
 
-	elementsToAdd := []UIElement{} // Collect focusable elements to add
+	if panel, ok := element.(*TabPanel); ok {
+		panel.window = w // The panel updates the focus list when it changes tabs
+	}
+	if accordion, ok := element.(*Accordion); ok {
+		accordion.window = w // The accordion updates the focus list when a section expands or collapses
+	}
+
+	elementsToAdd := focusablesOf(element) // Collect focusable elements to add
+
+	// Add collected elements to the focus list, checking for duplicates
+	for _, focusableElement := range elementsToAdd {
+		if focusableElement == nil {
+			continue
+		}
+
+		alreadyAdded := false
+		for _, fe := range w.focusableElements {
+			if fe == focusableElement {
+				alreadyAdded = true
+				break
+			}
+		}
+
+		if !alreadyAdded {
+			w.focusableElements = append(w.focusableElements, focusableElement)
+			// If this is the first focusable element added, focus it immediately
+			if w.focusedIndex == -1 {
+				w.focusedIndex = 0
+				// Activate the first focusable element by setting its IsActive flag
+				// (The setFocus function handles the type switching)
+				w.setFocus(0) // Call setFocus to activate the first element correctly
+			}
+		}
+	}
+}
+
+// focusablesOf returns the focusable elements element contributes to a window's
+// focus cycle (none for static elements like labels), marking them inactive.
+func focusablesOf(element UIElement) []UIElement {
+	elementsToAdd := []UIElement{}
 
 	switch v := element.(type) {
 	case *Button:
@@ -94,6 +552,9 @@ func (w *Window) AddElement(element UIElement) {
 	case *CheckBox:
 		v.IsActive = false // Explicitly set inactive
 		elementsToAdd = append(elementsToAdd, v)
+	case *ToggleSwitch:
+		v.IsActive = false // Explicitly set inactive
+		elementsToAdd = append(elementsToAdd, v)
 	case *RadioButton:
 		v.IsActive = false // Explicitly set inactive
 		elementsToAdd = append(elementsToAdd, v)
@@ -103,48 +564,121 @@ func (w *Window) AddElement(element UIElement) {
 	case *TextArea: // Add TextArea as a focusable element
 		v.IsActive = false // Explicitly set inactive
 		elementsToAdd = append(elementsToAdd, v)
-	case *Container: // Make the Container AND its ScrollBar focusable
+	case *Container: // The Container owns its scrolling (arrow keys move the highlight and scroll)
 		v.IsActive = false                       // Ensure container starts inactive
 		elementsToAdd = append(elementsToAdd, v) // Add the container
-		// Check for and add the container's scrollbar
+		// The internal scrollbar is only a separate tab stop if explicitly requested
 		scrollbar := v.GetScrollbar()
-		if scrollbar != nil {
+		if scrollbar != nil && v.ScrollbarFocusable {
 			scrollbar.IsActive = false // Ensure scrollbar starts inactive
 			elementsToAdd = append(elementsToAdd, scrollbar)
 		}
+		if hScrollbar := v.GetHorizontalScrollbar(); hScrollbar != nil && v.ScrollbarFocusable {
+			hScrollbar.IsActive = false
+			elementsToAdd = append(elementsToAdd, hScrollbar)
+		}
+	case *Table: // Add Table as a focusable element
+		v.IsActive = false // Ensure table starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+	case *Slider: // Add Slider as a focusable element
+		v.IsActive = false // Ensure slider starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+	case *DatePicker: // Add DatePicker as a focusable element
+		v.IsActive = false // Ensure date picker starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+	case *Breadcrumb: // Add Breadcrumb as a focusable element
+		v.IsActive = false // Ensure breadcrumb starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+	case *LogView: // Add LogView as a focusable element (for scrolling)
+		v.IsActive = false // Ensure log view starts inactive
+		elementsToAdd = append(elementsToAdd, v)
 	case *MenuBar: // Add MenuBar as a focusable element
 		v.IsActive = false // Ensure menubar starts inactive
 		elementsToAdd = append(elementsToAdd, v)
 	case *Prompt: // Add Prompt as a focusable element
 		v.SetActive(false) // Ensure prompt starts inactive
 		elementsToAdd = append(elementsToAdd, v)
-	}
-
-	// Add collected elements to the focus list, checking for duplicates
-	for _, focusableElement := range elementsToAdd {
-		if focusableElement == nil {
-			continue
+	case *TabPanel: // The tab strip, followed by the active page's elements
+		v.IsActive = false // Ensure panel starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+		if page := v.ActivePage(); page != nil {
+			for _, pageElement := range page.Elements {
+				elementsToAdd = append(elementsToAdd, focusablesOf(pageElement)...)
+			}
 		}
-
-		alreadyAdded := false
-		for _, fe := range w.focusableElements {
-			if fe == focusableElement {
-				alreadyAdded = true
-				break
+	case *Accordion: // The headers, followed by the expanded sections' elements
+		v.IsActive = false // Ensure accordion starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+		for _, sectionElement := range v.shownElements() {
+			elementsToAdd = append(elementsToAdd, focusablesOf(sectionElement)...)
+		}
+	case *Segment, *SegmentGroup: // Their elements, in order (nested segments included)
+		for _, child := range segmentChildren(v) {
+			elementsToAdd = append(elementsToAdd, focusablesOf(child)...)
+		}
+	case *SplitPane: // The divider, followed by the elements of both panes
+		v.IsActive = false // Ensure divider starts inactive
+		elementsToAdd = append(elementsToAdd, v)
+		for _, child := range segmentChildren(v) {
+			elementsToAdd = append(elementsToAdd, focusablesOf(child)...)
+		}
+	default:
+		if _, ok := v.(InputHandler); ok { // Custom elements that take input
+			if a, ok := v.(Activator); ok {
+				a.SetActive(false) // Ensure element starts inactive
 			}
+			elementsToAdd = append(elementsToAdd, v)
 		}
+	}
 
-		if !alreadyAdded {
-			w.focusableElements = append(w.focusableElements, focusableElement)
-			// If this is the first focusable element added, focus it immediately
-			if w.focusedIndex == -1 {
-				w.focusedIndex = 0
-				// Activate the first focusable element by setting its IsActive flag
-				// (The setFocus function handles the type switching)
-				w.setFocus(0) // Call setFocus to activate the first element correctly
-			}
+	return elementsToAdd
+}
+
+// SetInitialFocus makes element the focused control and remembers it as the
+// window's InitialFocus, so WindowActions starts with it active.
+// It returns false (and changes nothing) if element is not focusable.
+func (w *Window) SetInitialFocus(element UIElement) bool {
+	index := w.focusableIndex(element)
+	if index == -1 {
+		return false
+	}
+	w.InitialFocus = element
+	w.setFocus(index)
+	return true
+}
+
+// FocusElement moves focus to element. It returns false (and changes nothing) if
+// element is not focusable, e.g. disabled or on a hidden tab page.
+func (w *Window) FocusElement(element UIElement) bool {
+	index := w.focusableIndex(element)
+	if index == -1 || !isFocusEnabled(element) {
+		return false
+	}
+	if index != w.focusedIndex {
+		w.setFocus(index)
+	}
+	return true
+}
+
+// FocusedElement returns the element that has focus, or nil.
+func (w *Window) FocusedElement() UIElement {
+	if w.focusedIndex < 0 || w.focusedIndex >= len(w.focusableElements) {
+		return nil
+	}
+	return w.focusableElements[w.focusedIndex]
+}
+
+// focusableIndex returns the index of element in focusableElements, or -1.
+func (w *Window) focusableIndex(element UIElement) int {
+	if element == nil {
+		return -1
+	}
+	for i, fe := range w.focusableElements {
+		if fe == element {
+			return i
 		}
 	}
+	return -1
 }
 
 // RemoveElement removes a UIElement from the window
@@ -157,7 +691,99 @@ func (w *Window) RemoveElement(element UIElement) {
 		}
 	}
 
+	if w.InitialFocus == element {
+		w.InitialFocus = nil
+	}
+	if p, ok := element.(Placeable); ok {
+		delete(w.constraints, p)
+	}
+
 	// Remove from focusable elements if present
+	w.removeFocusable(element)
+	// A container's scrollbar may have been registered alongside it
+	if c, ok := element.(*Container); ok && c.GetScrollbar() != nil {
+		w.removeFocusable(c.GetScrollbar())
+		w.removeFocusable(c.GetHorizontalScrollbar())
+	}
+	// As may a tab panel's page elements
+	if panel, ok := element.(*TabPanel); ok {
+		for _, page := range panel.Pages {
+			for _, pageElement := range page.Elements {
+				for _, fe := range focusablesOf(pageElement) {
+					w.removeFocusable(fe)
+				}
+			}
+		}
+		panel.window = nil
+	}
+	// Or an accordion's section elements
+	if accordion, ok := element.(*Accordion); ok {
+		for _, sectionElement := range accordion.allElements() {
+			for _, fe := range focusablesOf(sectionElement) {
+				w.removeFocusable(fe)
+			}
+		}
+		accordion.window = nil
+	}
+	// And a segment's (or split pane's) elements
+	switch element.(type) {
+	case *Segment, *SegmentGroup, *SplitPane:
+		for _, fe := range focusablesOf(element) {
+			w.removeFocusable(fe)
+		}
+	}
+}
+
+// syncChildFocus updates the focus list after owner (a TabPanel or Accordion) changed
+// which of its children are shown: the focusable elements of every child are removed,
+// and those of the shown children are placed right after owner. Focus on a child
+// that is no longer shown moves to owner.
+func (w *Window) syncChildFocus(owner UIElement, children, shown []UIElement) {
+	ownerIndex := w.focusableIndex(owner)
+	if ownerIndex == -1 {
+		return // Owner is not part of this window's focus cycle
+	}
+
+	var focused UIElement
+	if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
+		focused = w.focusableElements[w.focusedIndex]
+	}
+
+	// Collect the elements that should be focusable: those of the shown children
+	var visible []UIElement
+	for _, element := range shown {
+		visible = append(visible, focusablesOf(element)...)
+	}
+	if focused != nil && focused != owner && containsElement(children, focused) {
+		stillVisible := false
+		for _, element := range visible {
+			if element == focused {
+				stillVisible = true
+				break
+			}
+		}
+		if !stillVisible {
+			w.setFocus(ownerIndex) // Focus was on a child that is now hidden
+			focused = owner
+		}
+	}
+
+	// Remove every child's elements, then insert the visible ones after the owner
+	for _, element := range children {
+		for _, fe := range focusablesOf(element) {
+			w.removeFocusable(fe)
+		}
+	}
+	ownerIndex = w.focusableIndex(owner)
+	rest := append([]UIElement{}, w.focusableElements[ownerIndex+1:]...)
+	w.focusableElements = append(append(w.focusableElements[:ownerIndex+1], visible...), rest...)
+
+	// The focused element is still active, only its index changed
+	w.focusedIndex = w.focusableIndex(focused)
+}
+
+// removeFocusable removes element from the focus list, keeping the focused index in step.
+func (w *Window) removeFocusable(element UIElement) {
 	for i, e := range w.focusableElements {
 		if e == element {
 			w.focusableElements = append(w.focusableElements[:i], w.focusableElements[i+1:]...)
@@ -169,10 +795,14 @@ func (w *Window) RemoveElement(element UIElement) {
 	}
 }
 
-// getStringDisplayWidth returns the display width of a string, handling emoji and wide characters
-func getStringDisplayWidth(s string) int {
+// DisplayWidth returns the number of terminal columns s occupies. East Asian wide
+// and fullwidth characters and most emoji count as two columns, and ANSI escape
+// sequences (such as color codes) take no space.
+// This is the measurement the library uses for truncation, centering and alignment,
+// so apps laying out their own strings (e.g. columns in Container lines) should use it too.
+func DisplayWidth(s string) int {
 	displayWidth := 0
-	for _, r := range s {
+	for _, r := range StripANSI(s) {
 		p := width.LookupRune(r)
 		switch p.Kind() {
 		case width.EastAsianWide, width.EastAsianFullwidth:
@@ -190,20 +820,143 @@ func getStringDisplayWidth(s string) int {
 	return displayWidth
 }
 
+// runeWidth returns the number of terminal columns r occupies (see DisplayWidth).
+func runeWidth(r rune) int {
+	return DisplayWidth(string(r))
+}
+
+// typedText returns the text a key press types: a printable ASCII character, or a
+// UTF-8 sequence such as an accented letter, a CJK character or an emoji (which may
+// come with combining marks, variation selectors or joiners in the same read).
+// ok is false for control keys and escape sequences.
+func typedText(key []byte) (text string, ok bool) {
+	if len(key) == 0 || !utf8.Valid(key) {
+		return "", false
+	}
+	for i, r := range string(key) {
+		if i == 0 && !unicode.IsPrint(r) {
+			return "", false // Starts with a control character or escape
+		}
+		if !unicode.IsPrint(r) && !unicode.In(r, unicode.Mn, unicode.Me) && r != '\u200d' {
+			return "", false
+		}
+	}
+	return string(key), true
+}
+
+// ansiSequenceLen returns the length in bytes of the ANSI escape sequence starting
+// at s[i] (a CSI sequence like "\x1b[1;31m", or a two-byte escape), or 0 if there is none.
+func ansiSequenceLen(s string, i int) int {
+	if i >= len(s) || s[i] != '\x1b' || i+1 >= len(s) {
+		return 0
+	}
+	if s[i+1] != '[' {
+		return 2 // Two-byte escape such as ESC 7
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e { // Final byte of a CSI sequence
+			return j - i + 1
+		}
+	}
+	return len(s) - i // Unterminated sequence: treat the rest as part of it
+}
+
+// StripANSI returns s without its ANSI escape sequences, i.e. only the text
+// that takes up space on screen.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLen(s, i); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
 // Render draws the window and its elements to the terminal.
 func (w *Window) Render() {
+	w.renderMu.Lock()
+	defer w.renderMu.Unlock()
+	w.render()
+	w.flush()
+}
+
+// RenderTo draws the window like Render but writes the complete frame to out
+// instead of the terminal, e.g. for golden-file tests or logging. The frame is
+// clipped to the terminal size (80x24 when there is no terminal). What Render
+// considers on screen is left as it was.
+func (w *Window) RenderTo(out io.Writer) error {
+	w.renderMu.Lock()
+	defer w.renderMu.Unlock()
+	w.render()
+	frame := parseScreen(w.buffer.String(), GetTerminalWidth(), GetTerminalHeight())
+	_, err := io.WriteString(out, frame.diff(nil))
+	return err
+}
+
+// RenderToString returns the commands Render would send to the terminal (text,
+// colors, cursor moves) without printing them or changing what Render considers
+// on screen. Use StripANSI or RenderPlain to look at the text alone.
+func (w *Window) RenderToString() string {
+	w.renderMu.Lock()
+	defer w.renderMu.Unlock()
+	w.render()
+	return w.buffer.String()
+}
+
+// RenderPlain renders the window without printing it and returns the visible
+// characters of the terminal, row by row, with colors and other escapes removed:
+// a grid of the terminal's size (80x24 when there is no terminal) where
+// grid[y][x] is the character at screen position (x, y). Undrawn cells are spaces,
+// and the right half of a wide character is left out of its row. It makes
+// rendering observable in tests without a real terminal.
+func (w *Window) RenderPlain() [][]rune {
+	return parseScreen(w.RenderToString(), GetTerminalWidth(), GetTerminalHeight()).plain()
+}
+
+// output returns where the window writes to the terminal: Output, its manager's
+// Output, or stdout.
+func (w *Window) output() io.Writer {
+	if w.Output != nil {
+		return w.Output
+	}
+	if w.manager != nil && w.manager.Output != nil {
+		return w.manager.Output
+	}
+	return os.Stdout
+}
+
+// render builds the commands that draw the window in w.buffer. Callers hold w.renderMu.
+func (w *Window) render() {
 	w.buffer.Reset()                   // Clear previous rendering commands
+	w.ensureFocusEnabled()             // Move focus off elements disabled since the last render
 	w.buffer.WriteString(HideCursor()) // Start with cursor hidden by default
 
-	box := BoxTypes[w.BoxStyle]
+	// Empty colors, in the window and its elements, come from the window's theme
+	borderColor := themeColor(w.BorderColor, w.theme().Border)
+	bgColor := themeColor(w.BgColor, w.theme().Background)
+	titleColor := themeColor(w.TitleColor, w.theme().Title)
+	contentColor := themeColor(w.ContentColor, w.theme().Content)
+
+	box := frameBox(w.BoxStyle)
 	fullTitle := w.Icon + " " + w.Title
 
 	// Calculate actual display width of the title
-	titleDisplayWidth := getStringDisplayWidth(fullTitle)
+	titleDisplayWidth := DisplayWidth(fullTitle)
+
+	if w.Shadow {
+		renderShadow(&w.buffer, w.X, w.Y, w.Width, w.Height, w.ShadowColor)
+	}
 
 	// --- Draw Border and Background ---
-	w.buffer.WriteString(w.BorderColor)
-	w.buffer.WriteString(w.BgColor) // Set background for the whole area initially
+	w.buffer.WriteString(borderColor)
+	w.buffer.WriteString(bgColor) // Set background for the whole area initially
 
 	// Top border with Title
 	contentWidth := w.Width // Available space between corners
@@ -221,7 +974,7 @@ func (w *Window) Render() {
 			truncated := ""
 			currentWidth := 0
 			for _, r := range fullTitle {
-				charWidth := getStringDisplayWidth(string(r))
+				charWidth := DisplayWidth(string(r))
 				if currentWidth+charWidth+3 <= contentWidth {
 					truncated += string(r)
 					currentWidth += charWidth
@@ -230,7 +983,7 @@ func (w *Window) Render() {
 				}
 			}
 			fullTitle = truncated + "..."
-			titleDisplayWidth = getStringDisplayWidth(fullTitle) // Recalculate after truncation
+			titleDisplayWidth = DisplayWidth(fullTitle) // Recalculate after truncation
 		} else {
 			// If we have very little space, do a hard truncate
 			fullTitle = string([]rune(fullTitle)[:contentWidth])
@@ -299,14 +1052,14 @@ func (w *Window) Render() {
 	w.buffer.WriteString(MoveCursorCmd(w.Y, w.X))
 	w.buffer.WriteString(box.TopLeft)
 	w.buffer.WriteString(strings.Repeat(box.Horizontal, leftPadding))
-	w.buffer.WriteString(w.TitleColor)  // Title color might differ from border
-	w.buffer.WriteString(fullTitle)     // Print potentially truncated title
-	w.buffer.WriteString(w.BorderColor) // Back to border color
+	w.buffer.WriteString(titleColor)  // Title color might differ from border
+	w.buffer.WriteString(fullTitle)   // Print potentially truncated title
+	w.buffer.WriteString(borderColor) // Back to border color
 	w.buffer.WriteString(strings.Repeat(box.Horizontal, rightPadding))
 	w.buffer.WriteString(box.TopRight)
 
 	// Middle rows (Vertical borders and background fill)
-	contentBg := w.BgColor + strings.Repeat(" ", w.Width-2) // Precompute background fill string
+	contentBg := bgColor + strings.Repeat(" ", w.Width-2) // Precompute background fill string
 	for i := 1; i < w.Height-1; i++ {
 		w.buffer.WriteString(MoveCursorCmd(w.Y+i, w.X))
 		w.buffer.WriteString(box.Vertical)
@@ -321,43 +1074,129 @@ func (w *Window) Render() {
 	w.buffer.WriteString(strings.Repeat(box.Horizontal, w.Width-2))
 	w.buffer.WriteString(box.BottomRight)
 
+	w.renderDividers(box, borderColor+bgColor, leftPadding, leftPadding+titleDisplayWidth)
+
+	// Show the current size on the bottom border while resizing
+	if w.resizing {
+		sizeText := fmt.Sprintf(" %dx%d ", w.Width, w.Height)
+		if len(sizeText) <= w.Width-2 {
+			w.buffer.WriteString(MoveCursorCmd(w.Y+w.Height-1, w.X+1))
+			w.buffer.WriteString(titleColor + sizeText + borderColor)
+		}
+	} else if w.moving {
+		// And the current position while moving
+		posText := fmt.Sprintf(" %d,%d ", w.X, w.Y)
+		if len(posText) <= w.Width-2 {
+			w.buffer.WriteString(MoveCursorCmd(w.Y+w.Height-1, w.X+1))
+			w.buffer.WriteString(titleColor + posText + borderColor)
+		}
+	}
+
 	// --- Render Elements ---
 	// Elements are rendered relative to the top-left corner of the *content area*
 	contentX := w.X + 1
 	contentY := w.Y + 1
 	contentWidth = w.Width - 2
+	w.resolveConstraints(contentWidth, w.Height-2) // Reflow anchored elements and layouts for the current size
+	w.arrangeLayouts(contentWidth)
 
 	// Sort elements by z-index before rendering
 	sortedElements := w.getSortedElements()
 
+	// Clamp the content scroll offset before using it
+	visibleHeight := w.Height - 2
+	if w.Scrollable {
+		w.updateContentScroll()
+	}
+
 	// Set default content color before rendering elements
-	w.buffer.WriteString(w.ContentColor)
+	w.buffer.WriteString(contentColor)
 	for _, element := range sortedElements {
-		// Pass the window's buffer, content area origin, and content width
-		element.Render(&w.buffer, contentX, contentY, contentWidth)
+		if !isVisible(element) {
+			continue
+		}
+		if !w.Scrollable || isPinned(element) {
+			// Pass the window's buffer, content area origin, and content width
+			renderElement(element, w.Theme, &w.buffer, contentX, contentY, contentWidth)
+			continue
+		}
+		// Skip elements scrolled out of view, and clip the ones only partly in view
+		// to the visible content rows, so they never draw over the border.
+		if b, ok := element.(Bounded); ok {
+			_, y, _, h := b.Bounds()
+			if y+h <= w.scrollOffset || y >= w.scrollOffset+visibleHeight {
+				continue
+			}
+			if y < w.scrollOffset || y+h > w.scrollOffset+visibleHeight {
+				var clipped strings.Builder
+				clipped.WriteString(contentColor)
+				renderElement(element, w.Theme, &clipped, contentX, contentY-w.scrollOffset, contentWidth)
+				w.buffer.WriteString(parseScreen(clipped.String(), 0, 0).region(contentY, contentX, contentWidth, visibleHeight))
+				w.buffer.WriteString(contentColor)
+				continue
+			}
+		}
+		renderElement(element, w.Theme, &w.buffer, contentX, contentY-w.scrollOffset, contentWidth)
+	}
+
+	// Draw the window scrollbar over the right border when content overflows
+	if w.Scrollable && w.scrollBar.Visible {
+		renderElement(w.scrollBar, w.Theme, &w.buffer, w.X+w.Width-1, contentY, 1)
+		w.buffer.WriteString(contentColor)
+	}
+
+	// Toasts are drawn above every element
+	w.renderToasts(contentX, contentY, contentWidth, visibleHeight)
+
+	// The help overlay is modal: draw it over everything and keep the cursor hidden
+	if w.helpOverlay != nil {
+		w.helpOverlay.MaxHeight = w.Height - 2
+		w.helpOverlay.Render(&w.buffer, contentX, contentY, contentWidth)
+		w.buffer.WriteString(HideCursor())
+		return
 	}
 
 	// --- Cursor Management ---
 	// After rendering all elements, check if any element needs the cursor
 	needsCursor := false
 	var finalCursorX, finalCursorY int
+	cursorShape := CursorDefault
 
 	// Check for active element that wants the cursor
 	for _, element := range w.Elements {
-		if cursorManager, ok := element.(CursorManager); ok {
+		if cursorManager, ok := element.(CursorManager); ok && isVisible(element) {
 			if cursorManager.NeedsCursor() {
 				x, y, valid := cursorManager.GetCursorPosition()
 				if valid {
 					needsCursor = true
 					finalCursorX = x
 					finalCursorY = y
+					if shaper, ok := element.(CursorShaper); ok {
+						cursorShape = shaper.GetCursorShape()
+					}
 					break // Use the first element that needs cursor
 				}
 			}
 		}
 	}
+	if cursorShape == CursorDefault {
+		cursorShape = w.textCursor // The element leaves the shape to the window
+	}
 
 	if needsCursor {
+		// Apply the element's cursor shape and the window's cursor color, only emitting changes
+		if cursorShape != w.cursorShape {
+			w.buffer.WriteString(CursorShapeCmd(cursorShape))
+			w.cursorShape = cursorShape
+		}
+		if w.CursorColor != w.cursorColor {
+			if w.CursorColor == "" {
+				w.buffer.WriteString(ResetCursorColorCmd())
+			} else {
+				w.buffer.WriteString(CursorColorCmd(w.CursorColor))
+			}
+			w.cursorColor = w.CursorColor
+		}
 		// Position and show cursor
 		w.buffer.WriteString(MoveCursorCmd(finalCursorY, finalCursorX))
 		w.buffer.WriteString(ShowCursor())
@@ -366,9 +1205,262 @@ func (w *Window) Render() {
 		w.buffer.WriteString(HideCursor())
 	}
 
-	// Reset colors at the end and print the buffer
+	// Reset colors at the end
 	w.buffer.WriteString(colors.Reset)
-	fmt.Print(w.buffer.String())
+}
+
+// divider is a line across the window content, joined to the borders and other
+// dividers with the box style's junctions.
+type divider struct {
+	at         int // Content column (vertical) or row (horizontal)
+	start, end int // Content rows (vertical) or columns (horizontal) it spans; -1 reaches the border
+}
+
+// AddVerticalDivider draws a line from the top border to the bottom border at
+// content column col, e.g. between the panes of a multi-pane layout. Where it meets
+// the borders or a horizontal divider, the box style's junctions (┬ ┴ ├ ┤ ┼) join
+// them, except under the title. Dividers are drawn behind the elements.
+func (w *Window) AddVerticalDivider(col int) {
+	w.AddVerticalDividerSpan(col, -1, -1)
+}
+
+// AddVerticalDividerSpan draws a vertical divider at content column col from
+// content row fromRow to toRow; -1 reaches the top or bottom border. Starting or
+// ending on a horizontal divider joins it with ┬ or ┴.
+func (w *Window) AddVerticalDividerSpan(col, fromRow, toRow int) {
+	w.vDividers = append(w.vDividers, divider{at: col, start: fromRow, end: toRow})
+}
+
+// AddHorizontalDivider draws a line from the left border to the right border at
+// content row row (see AddVerticalDivider).
+func (w *Window) AddHorizontalDivider(row int) {
+	w.AddHorizontalDividerSpan(row, -1, -1)
+}
+
+// AddHorizontalDividerSpan draws a horizontal divider at content row row from
+// content column fromCol to toCol; -1 reaches the left or right border.
+func (w *Window) AddHorizontalDividerSpan(row, fromCol, toCol int) {
+	w.hDividers = append(w.hDividers, divider{at: row, start: fromCol, end: toCol})
+}
+
+// ClearDividers removes all dividers.
+func (w *Window) ClearDividers() {
+	w.vDividers, w.hDividers = nil, nil
+}
+
+// span returns the first and last content cell d covers, in a content area size
+// cells long; -1 and size are the borders.
+func (d divider) span(size int) (int, int) {
+	end := d.end
+	if end < 0 || end > size {
+		end = size
+	}
+	return d.start, end
+}
+
+// renderDividers draws the window's dividers in color, joined to the borders and
+// to each other; titleStart and titleEnd are the content columns the title covers.
+func (w *Window) renderDividers(box BoxType, color string, titleStart, titleEnd int) {
+	if len(w.vDividers) == 0 && len(w.hDividers) == 0 {
+		return
+	}
+	contentWidth, contentHeight := w.Width-2, w.Height-2
+	w.buffer.WriteString(color)
+	for _, h := range w.hDividers {
+		if h.at < 0 || h.at >= contentHeight {
+			continue
+		}
+		start, end := h.span(contentWidth)
+		for col := start; col <= end; col++ {
+			glyph := box.Horizontal
+			if col == -1 {
+				glyph = glyphOr(box.LeftTee, box.Vertical)
+			} else if col == contentWidth {
+				glyph = glyphOr(box.RightTee, box.Vertical)
+			}
+			w.buffer.WriteString(MoveCursorCmd(w.Y+1+h.at, w.X+1+col))
+			w.buffer.WriteString(glyph)
+		}
+	}
+	for _, v := range w.vDividers {
+		if v.at < 0 || v.at >= contentWidth {
+			continue
+		}
+		start, end := v.span(contentHeight)
+		for row := start; row <= end; row++ {
+			glyph := box.Vertical
+			switch {
+			case row == -1:
+				if v.at >= titleStart && v.at < titleEnd {
+					continue // Keep the title intact
+				}
+				glyph = glyphOr(box.TopTee, box.Horizontal)
+			case row == contentHeight:
+				glyph = glyphOr(box.BottomTee, box.Horizontal)
+			default:
+				for _, h := range w.hDividers {
+					if hStart, hEnd := h.span(contentWidth); h.at == row && v.at >= hStart && v.at <= hEnd {
+						glyph = junction(box, row > start, row < end, v.at > hStart, v.at < hEnd)
+					}
+				}
+			}
+			w.buffer.WriteString(MoveCursorCmd(w.Y+1+row, w.X+1+v.at))
+			w.buffer.WriteString(glyph)
+		}
+	}
+}
+
+// junction returns the glyph of box where a vertical and a horizontal line meet,
+// given which directions the lines continue in.
+func junction(box BoxType, up, down, left, right bool) string {
+	switch {
+	case !up && down && left && right:
+		return glyphOr(box.TopTee, box.Horizontal)
+	case up && !down && left && right:
+		return glyphOr(box.BottomTee, box.Horizontal)
+	case up && down && !left && right:
+		return glyphOr(box.LeftTee, box.Vertical)
+	case up && down && left && !right:
+		return glyphOr(box.RightTee, box.Vertical)
+	}
+	return glyphOr(box.Cross, box.Vertical)
+}
+
+// glyphOr returns glyph, or fallback if it is empty (e.g. a junction missing from
+// a box style registered before junctions existed).
+func glyphOr(glyph, fallback string) string {
+	if glyph == "" {
+		return fallback
+	}
+	return glyph
+}
+
+// isPinned reports whether an element stays in place when the window content
+// scrolls. Overlays (menus, prompts - anything with a positive z-index) are pinned.
+func isPinned(element UIElement) bool {
+	if z, ok := element.(ZIndexer); ok {
+		return z.GetZIndex() > 0
+	}
+	return false
+}
+
+// contentHeight returns the number of rows needed to show every scrollable element.
+func (w *Window) contentHeight() int {
+	height := 0
+	for _, element := range w.Elements {
+		if isPinned(element) {
+			continue
+		}
+		if b, ok := element.(Bounded); ok {
+			_, y, _, h := b.Bounds()
+			if y+h > height {
+				height = y + h
+			}
+		}
+	}
+	return height
+}
+
+// updateContentScroll recalculates the window scrollbar and clamps the scroll offset.
+func (w *Window) updateContentScroll() {
+	visibleHeight := w.Height - 2
+	if visibleHeight < 1 {
+		visibleHeight = 1
+	}
+	maxOffset := w.contentHeight() - visibleHeight
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if w.scrollOffset > maxOffset {
+		w.scrollOffset = maxOffset
+	}
+	if w.scrollOffset < 0 {
+		w.scrollOffset = 0
+	}
+
+	if w.scrollBar == nil {
+		w.scrollBar = NewScrollBar(0, 0, visibleHeight, 0, 0, w.BorderColor, w.BorderColor, "window_scrollbar")
+	}
+	w.scrollBar.Height = visibleHeight
+	w.scrollBar.MaxValue = maxOffset
+	w.scrollBar.Value = w.scrollOffset
+	w.scrollBar.Visible = maxOffset > 0
+}
+
+// ScrollContent scrolls the window content by delta rows (positive scrolls down).
+// It has no effect unless the window is Scrollable.
+func (w *Window) ScrollContent(delta int) {
+	w.ScrollContentTo(w.scrollOffset + delta)
+}
+
+// ScrollContentTo sets the window content scroll offset, clamped to the valid range.
+func (w *Window) ScrollContentTo(offset int) {
+	if !w.Scrollable {
+		return
+	}
+	w.scrollOffset = offset
+	w.updateContentScroll()
+}
+
+// EnsureElementVisible scrolls the window content so that element lies within
+// the visible content area. Elements taller than the content area are aligned to
+// the top. It has no effect unless the window is Scrollable and element is Bounded.
+// Scrollable segments holding element are scrolled to it first.
+func (w *Window) EnsureElementVisible(element UIElement) {
+	w.scrollSegmentsTo(element)
+	if !w.Scrollable || isPinned(element) {
+		return
+	}
+	b, ok := element.(Bounded)
+	if !ok {
+		return
+	}
+	_, y, _, h := b.Bounds()
+	_, originY := w.elementOrigin(element)
+	y += originY - (w.Y + 1 - w.scrollOffset) // Elements in tab pages and segments are relative to them
+	visibleHeight := w.Height - 2
+	if y < w.scrollOffset || h > visibleHeight {
+		w.ScrollContentTo(y)
+	} else if y+h > w.scrollOffset+visibleHeight {
+		w.ScrollContentTo(y + h - visibleHeight)
+	}
+}
+
+// GetContentOffset returns the number of content rows scrolled off the top.
+func (w *Window) GetContentOffset() int {
+	return w.scrollOffset
+}
+
+// handleContentScrollKey scrolls the window content for PageUp/PageDown, and for
+// Up/Down when the focused element doesn't use the arrow keys itself.
+// It returns true if the key was consumed.
+func (w *Window) handleContentScrollKey(ev InputEvent, focused UIElement) bool {
+	pageSize := w.Height - 3
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	switch focused.(type) {
+	case *Container, *Table, *TextArea, *ScrollBar, *MenuBar, *Prompt:
+		return false // These elements handle navigation keys themselves
+	}
+
+	if ev.Mod != 0 {
+		return false
+	}
+	switch ev.Key {
+	case KeyPageUp:
+		w.ScrollContent(-pageSize)
+	case KeyPageDown:
+		w.ScrollContent(pageSize)
+	case KeyUp:
+		w.ScrollContent(-1)
+	case KeyDown:
+		w.ScrollContent(1)
+	default:
+		return false
+	}
+	return true
 }
 
 // Add method to collect all submenus
@@ -429,28 +1521,18 @@ func (w *Window) setFocus(newIndex int) {
 		return
 	}
 
+	previousIndex := w.focusedIndex
+	previousElement := w.FocusedElement()
+	defer func() {
+		if current := w.FocusedElement(); current != previousElement && w.OnFocusChange != nil {
+			w.OnFocusChange(previousElement, current)
+		}
+	}()
+
 	// Deactivate the previously focused element (if any)
 	if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
-		switch el := w.focusableElements[w.focusedIndex].(type) {
-		case *Button:
-			el.IsActive = false
-		case *TextBox:
-			el.IsActive = false
-		case *CheckBox:
-			el.IsActive = false
-		case *RadioButton:
-			el.IsActive = false
-		case *ScrollBar: // Handles both direct and container scrollbars
-			el.IsActive = false
-		case *Container:
-			el.IsActive = false
-		case *TextArea: // Handle TextArea focus
-			el.IsActive = false
-		case *MenuBar: // Handle MenuBar focus
-			el.IsActive = false
-			el.Deactivate() // Properly deactivate menu bar (closes submenus)
-		case *Prompt: // Handle Prompt focus
-			el.SetActive(false) // Use the prompt's SetActive method
+		if a, ok := w.focusableElements[w.focusedIndex].(Activator); ok {
+			a.SetActive(false)
 		}
 	}
 
@@ -463,29 +1545,104 @@ func (w *Window) setFocus(newIndex int) {
 		w.focusedIndex = newIndex
 	}
 
+	// Skip disabled elements, continuing in the direction focus is moving
+	step := 1
+	if newIndex < previousIndex {
+		step = -1
+	}
+	count := len(w.focusableElements)
+	for i := 0; i < count && !isFocusEnabled(w.focusableElements[w.focusedIndex]); i++ {
+		w.focusedIndex = (w.focusedIndex + step + count) % count
+	}
+	if !isFocusEnabled(w.focusableElements[w.focusedIndex]) {
+		w.focusedIndex = -1 // Every focusable element is disabled
+		return
+	}
+
 	// Activate the newly focused element
 	if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
-		switch el := w.focusableElements[w.focusedIndex].(type) {
-		case *Button:
-			el.IsActive = true
-		case *TextBox:
-			el.IsActive = true
-		case *CheckBox:
-			el.IsActive = true
-		case *RadioButton:
-			el.IsActive = true
-		case *ScrollBar: // Handles both direct and container scrollbars
-			el.IsActive = true
-		case *Container:
-			el.IsActive = true
-		case *TextArea: // Handle TextArea focus
-			el.IsActive = true
-		case *MenuBar: // Handle MenuBar focus
-			el.IsActive = true
-			el.Activate() // Properly activate the menu bar
-		case *Prompt: // Handle Prompt focus
-			el.SetActive(true) // Use the prompt's SetActive method
+		if a, ok := w.focusableElements[w.focusedIndex].(Activator); ok {
+			a.SetActive(true)
 		}
+		// Scroll the window content so the newly focused element is on screen
+		w.EnsureElementVisible(w.focusableElements[w.focusedIndex])
+	}
+}
+
+// SetTerminalTitle sets the terminal window/tab title (e.g. to the open document's name).
+// The original title is saved the first time and restored when WindowActions exits.
+func (w *Window) SetTerminalTitle(title string) {
+	if !w.titleChanged {
+		fmt.Fprint(w.output(), pushTerminalTitle)
+		w.titleChanged = true
+	}
+	fmt.Fprint(w.output(), TerminalTitleCmd(title))
+}
+
+// RestoreTerminalTitle restores the title saved by the first SetTerminalTitle call.
+func (w *Window) RestoreTerminalTitle() {
+	if w.titleChanged {
+		fmt.Fprint(w.output(), popTerminalTitle)
+		w.titleChanged = false
+	}
+}
+
+// SetCursorShape sets the cursor shape shown while a text element that doesn't
+// choose its own (its CursorShape is CursorDefault) has the cursor; new windows use
+// CursorBlinkingBar. CursorDefault keeps the terminal's configured cursor. The
+// terminal's shape is restored when WindowActions exits.
+func (w *Window) SetCursorShape(shape CursorShape) {
+	w.textCursor = shape
+}
+
+// EmitCursorShape changes the cursor shape of the window's terminal (see Output)
+// right away. Use CursorDefault to restore the terminal's configured shape.
+func (w *Window) EmitCursorShape(shape CursorShape) {
+	fmt.Fprint(w.output(), CursorShapeCmd(shape))
+	w.cursorShape = shape // Render and WindowActions restore from here
+}
+
+// Bell rings the bell of the window's terminal (see Output), unless BellEnabled is false.
+func (w *Window) Bell() {
+	if BellEnabled {
+		fmt.Fprint(w.output(), bell)
+	}
+}
+
+// restoreCursorStyle resets the cursor shape and color to the terminal's defaults
+// if Render changed them.
+func (w *Window) restoreCursorStyle() {
+	if w.cursorShape != CursorDefault {
+		fmt.Fprint(w.output(), CursorShapeCmd(CursorDefault))
+		w.cursorShape = CursorDefault
+	}
+	if w.cursorColor != "" {
+		fmt.Fprint(w.output(), ResetCursorColorCmd())
+		w.cursorColor = ""
+	}
+}
+
+// isFocusEnabled reports whether element may receive focus (i.e. it isn't disabled or hidden).
+func isFocusEnabled(element UIElement) bool {
+	if !isVisible(element) {
+		return false
+	}
+	if e, ok := element.(Enabler); ok {
+		return e.IsEnabled()
+	}
+	return true
+}
+
+// ensureFocusEnabled moves focus off an element that has been disabled, and back
+// onto an element once something is re-enabled after everything was disabled.
+func (w *Window) ensureFocusEnabled() {
+	if len(w.focusableElements) == 0 {
+		return
+	}
+	if w.focusedIndex < 0 {
+		w.setFocus(0)
+	} else if w.focusedIndex < len(w.focusableElements) && !isFocusEnabled(w.focusableElements[w.focusedIndex]) {
+		w.setFocus(w.focusedIndex + 1)
 	}
 }
 
@@ -502,11 +1659,11 @@ func (w *Window) WindowActions() {
 
 	// Check if stdin is a terminal
 	if !term.IsTerminal(fd) {
-		fmt.Println("Error: Standard input is not a terminal.")
+		fmt.Fprintln(w.output(), "Error: Standard input is not a terminal.")
 		// Fallback to the previous simulated input? Or just exit?
 		// For now, just print error and return.
 		// A simple fallback:
-		fmt.Println("Press Enter to continue...")
+		fmt.Fprintln(w.output(), "Press Enter to continue...")
 		bufio.NewReader(os.Stdin).ReadBytes('\n')
 		return
 	}
@@ -514,454 +1671,389 @@ func (w *Window) WindowActions() {
 	// Get the initial state of the terminal
 	oldState, err := term.GetState(fd)
 	if err != nil {
-		fmt.Printf("Error getting terminal state: %v\n", err)
+		fmt.Fprintf(w.output(), "Error getting terminal state: %v\n", err)
 		return
 	}
 	// Ensure terminal state is restored on exit
 	defer term.Restore(fd, oldState)
 	// Ensure cursor is shown on exit
-	defer fmt.Print(ShowCursor())
+	defer fmt.Fprint(w.output(), ShowCursor())
+	// Restore the terminal's cursor shape and color if Render changed them
+	defer w.restoreCursorStyle()
 
 	// Put the terminal into raw mode
 	_, err = term.MakeRaw(fd)
 	if err != nil {
-		fmt.Printf("Error setting terminal to raw mode: %v\n", err)
+		fmt.Fprintf(w.output(), "Error setting terminal to raw mode: %v\n", err)
 		return
 	}
 
+	// Ask the terminal to report mouse events, and stop it on exit
+	if w.MouseEnabled {
+		fmt.Fprint(w.output(), mouseEnable)
+		defer fmt.Fprint(w.output(), mouseDisable)
+	}
+	// Mark pasted text, so it can be typed into text fields in one go
+	fmt.Fprint(w.output(), bracketedPasteEnable)
+	defer fmt.Fprint(w.output(), bracketedPasteDisable)
+
+	w.Invalidate() // The screen may have changed since the last Render
+
+	w.applyInitialFocus()
+
 	// Initial render
 	w.Render()
 
+	// Reflow and redraw when the terminal is resized
+	resized := make(chan os.Signal, 1)
+	notifyTerminalResize(resized)
+	defer signal.Stop(resized)
+
 	// Buffer for reading input bytes
-	inputBuf := make([]byte, 6) // Increased buffer for escape sequences (arrows, delete)
+	inputBuf := make([]byte, inputBufferSize) // Room for escape sequences, mouse reports and pastes
+	var input <-chan []byte                   // Pending read, nil while the last input is being handled
+	var splitter keySplitter                  // Cuts each read into keys
+	var keyTimeout <-chan time.Time           // Fires when a key cut off by a read won't be completed
+
+	// Timed updates (see SetTicker); a nil channel never fires
+	var ticks <-chan time.Time
+	if w.onTick != nil && w.tickInterval > 0 {
+		ticker := time.NewTicker(w.tickInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
 
 	for {
-		// Read input from the raw terminal
-		n, err := os.Stdin.Read(inputBuf)
-		if err != nil {
+		// Read input from the raw terminal, handling resizes and ticks while waiting
+		if input == nil {
+			input = readInput(inputBuf)
+		}
+		var data []byte
+		var keys [][]byte
+		readOK := true
+		select {
+		case <-resized:
+			w.handleTerminalResize()
+			continue
+		case <-ticks:
+			needsRender := w.onTick(w)
+			if w.expirePrompts() {
+				needsRender = true
+			}
+			if needsRender {
+				w.Render()
+			}
+			continue
+		case now := <-w.nextToastExpiry():
+			if w.expireToasts(now) {
+				w.Render() // The toasts below the expired ones move up
+			}
+			continue
+		case s := <-w.spinners: // Spinners started with StartAuto
+			s.Tick()
+			w.Render()
+			continue
+		case <-keyTimeout: // The rest of the key held back didn't arrive: a lone Escape
+			keys = splitter.flush()
+		case data, readOK = <-input:
+			input = nil
+			keys = splitter.split(data)
+		}
+		if !readOK {
 			// Handle read errors (e.g., if stdin is closed)
 			break // Exit loop on read error
 		}
+		keyTimeout = splitter.timeout()
 
-		if n == 0 {
-			continue // No input read, continue loop
+		quit := false
+		for _, key := range keys {
+			if quit = w.handleInput(key, fd, oldState); quit {
+				break
+			}
+		}
+		if quit {
+			break // Exit the interaction loop
 		}
+	}
 
-		key := inputBuf[:n]
-		var loopShouldQuit bool = false  // Flag to control quitting the loop for this iteration
-		var loopNeedsRender bool = false // Flag to control re-rendering for this iteration
+	// Cleanup is handled by defers (Restore terminal state, cursor style, Show cursor)
+	// Restore the terminal's title if it was changed
+	w.RestoreTerminalTitle()
+	// Clear the screen after finishing interaction
+	fmt.Fprint(w.output(), ClearScreenAndBuffer())
+	fmt.Fprint(w.output(), ShowCursor()) // Explicitly show cursor after clearing
+}
 
-		// --- Custom Key Handler ---
-		customKeyProcessed := false
-		if w.KeyHandler != nil {
-			handled, render, quit := w.KeyHandler.HandleKeyStroke(key, w)
-			if handled {
-				customKeyProcessed = true
-				if render {
-					loopNeedsRender = true
-				}
-				if quit {
-					loopShouldQuit = true
-				}
+// applyInitialFocus focuses InitialFocus, if it is (still) focusable.
+func (w *Window) applyInitialFocus() {
+	if w.InitialFocus != nil {
+		if index := w.focusableIndex(w.InitialFocus); index != -1 && index != w.focusedIndex {
+			w.setFocus(index)
+		}
+	}
+}
+
+// handleInput handles one key read by WindowActions (a key, a mouse report, typed
+// text or a paste; see keySplitter) and reports whether the window should quit.
+func (w *Window) handleInput(key []byte, fd int, oldState *term.State) bool {
+	if len(key) == 0 {
+		return false // No input read
+	}
+
+	// Bracketed paste: insert the text into the focused text field in one go
+	// (ignored while the help overlay is open or the window is resized or moved)
+	if text, ok := pastedText(key); ok {
+		if w.helpOverlay == nil && !w.resizing && !w.moving {
+			w.handlePaste(text)
+		}
+		return false
+	}
+	// Several characters read at once (typed quickly, or pasted on a terminal without
+	// bracketed paste) go to a text field together; anything else gets them one by one
+	if text, ok := typedText(key); ok && utf8.RuneCount(key) > 1 && !w.takesTextInput() {
+		for _, r := range text {
+			if w.handleInput([]byte(string(r)), fd, oldState) {
+				return true
 			}
 		}
+		return false
+	}
 
-		if !customKeyProcessed {
-			// --- Original Key Handling Logic ---
-			// This block contains the original key handling logic.
-			// It will set loopNeedsRender and loopShouldQuit directly.
-
-			// Get the currently focused element, if any
-			var focusedElement UIElement
-			var focusedTextBox *TextBox
-			var focusedCheckBox *CheckBox
-			var focusedRadioButton *RadioButton
-			var focusedContainer *Container
-			var focusedScrollBar *ScrollBar
-			var focusedTextArea *TextArea
-			var focusedMenuBar *MenuBar // Add variable for focused MenuBar
-			var focusedPrompt *Prompt   // Add variable for focused Prompt
-
-			if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
-				focusedElement = w.focusableElements[w.focusedIndex]
-				// Type assertions to get specific element types
-				if tb, ok := focusedElement.(*TextBox); ok {
-					focusedTextBox = tb
-				}
-				if cb, ok := focusedElement.(*CheckBox); ok {
-					focusedCheckBox = cb
-				}
-				if rb, ok := focusedElement.(*RadioButton); ok {
-					focusedRadioButton = rb
-				}
-				if ct, ok := focusedElement.(*Container); ok {
-					focusedContainer = ct
-				}
-				if sb, ok := focusedElement.(*ScrollBar); ok {
-					focusedScrollBar = sb
-				}
-				// Add check for TextArea
-				if ta, ok := focusedElement.(*TextArea); ok {
-					focusedTextArea = ta
-				}
-				// Add check for MenuBar
-				if mb, ok := focusedElement.(*MenuBar); ok {
-					focusedMenuBar = mb
-				}
-				// Add check for Prompt
-				if p, ok := focusedElement.(*Prompt); ok {
-					focusedPrompt = p
-				}
+	var loopShouldQuit bool = false  // Flag to control quitting the loop for this iteration
+	var loopNeedsRender bool = false // Flag to control re-rendering for this iteration
+
+	// --- Mouse ---
+	// Mouse reports are handled here and never reach the key handling below.
+	// They are ignored while the help overlay is open or the window is being resized
+	// or moved with the keyboard.
+	ev := decodeInput(key)
+	if ev.Key == KeyMouse {
+		mouse := *ev.Mouse
+		if w.helpOverlay != nil || w.resizing || w.moving {
+			return false
+		}
+		if w.handleTitleDrag(mouse) {
+			w.Render()
+			return false
+		}
+		if modal := w.activeModal(); modal == nil && w.handleDividerDrag(mouse) {
+			w.Render()
+			return false
+		}
+		if modal := w.activeModal(); modal != nil {
+			// Clicks can't reach the elements behind a modal prompt; the wheel scrolls its message
+			switch mouse.Button {
+			case 64: // Wheel up
+				modal.ScrollUp()
+			case 65: // Wheel down
+				modal.ScrollDown()
 			}
+			w.Render()
+			return false
+		}
+		btn, quit := w.handleMouse(mouse)
+		if quit || (btn != nil && w.runButtonAction(btn, fd, oldState)) {
+			return true // An element or the button's action signaled quit
+		}
+		w.Render()
+		return false
+	}
 
-			// --- Key Handling ---
-			// Priority: Active MenuBar > Active TextArea > Active TextBox > Active Container > Active ScrollBar > Other focusable elements
-			if focusedMenuBar != nil && focusedMenuBar.IsActive {
-				// Handle MenuBar input
-				if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrow keys)
-					switch key[2] {
-					case 'A': // Up Arrow - Move up in menu
-						focusedMenuBar.MoveUp()
-						loopNeedsRender = true
-					case 'B': // Down Arrow - Move down in menu or open submenu
-						focusedMenuBar.MoveDown()
-						loopNeedsRender = true
-					case 'C': // Right Arrow - Move right in menu bar or into submenu
-						focusedMenuBar.MoveRight()
-						loopNeedsRender = true
-					case 'D': // Left Arrow - Move left in menu bar or back from submenu
-						focusedMenuBar.MoveLeft()
-						loopNeedsRender = true
-					case 'Z': // Shift+Tab - Move focus to previous focusable element
-						w.setFocus(w.focusedIndex - 1)
-						loopNeedsRender = true
-					}
-				} else if n == 1 {
-					switch key[0] {
-					case '\t': // Tab - Move focus to next element
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case '\r': // Enter - Activate selected menu item
-						shouldQuit := focusedMenuBar.ActivateSelected()
-						loopNeedsRender = true
-						if shouldQuit {
-							loopShouldQuit = true
-						}
-					case 27: // Escape - Deactivate menu
-						focusedMenuBar.Deactivate()
-						loopNeedsRender = true
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					}
-				}
-			} else if focusedPrompt != nil && focusedPrompt.IsActive {
-				// Handle Prompt input
-				if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrow keys)
-					switch key[2] {
-					case 'C': // Right Arrow - Select next button
-						focusedPrompt.SelectNext()
-						loopNeedsRender = true
-					case 'D': // Left Arrow - Select previous button
-						focusedPrompt.SelectPrevious()
-						loopNeedsRender = true
-					case 'Z': // Shift+Tab - Move focus to previous element
-						if !focusedPrompt.IsModal() { // Only allow focus change if not modal
-							w.setFocus(w.focusedIndex - 1)
-							loopNeedsRender = true
-						}
-					}
-				} else if n == 1 {
-					switch key[0] {
-					case '\t': // Tab - Move focus to next element or between buttons
-						if focusedPrompt.IsModal() {
-							focusedPrompt.SelectNext()
-						} else {
-							w.setFocus(w.focusedIndex + 1)
-						}
-						loopNeedsRender = true
-					case '\r': // Enter - Activate selected button
-						shouldQuit := focusedPrompt.ActivateSelected()
-						loopNeedsRender = true
-						// If the action signaled to quit, set the quit flag
-						if shouldQuit {
-							loopShouldQuit = true
-						}
-					case 27: // Escape - Close non-modal prompt
-						if !focusedPrompt.IsModal() {
-							focusedPrompt.SetActive(false)
-							w.setFocus(w.focusedIndex + 1)
-							loopNeedsRender = true
-						}
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					}
-				}
-			} else if focusedTextArea != nil && focusedTextArea.IsActive {
-				// Handle TextArea input
-				isPrintable := n == 1 && key[0] >= 32 && key[0] < 127 // Printable ASCII (excluding DEL)
-
-				if isPrintable {
-					// Insert character at cursor position
-					focusedTextArea.InsertChar(rune(key[0]))
-					loopNeedsRender = true
-				} else if n == 1 {
-					switch key[0] {
-					case 127, 8: // Backspace (DEL or ASCII BS)
-						focusedTextArea.DeleteChar()
-						loopNeedsRender = true
-					case '\t': // Tab - Move focus to next element
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case '\r': // Enter - Insert newline
-						focusedTextArea.InsertChar('\n')
-						loopNeedsRender = true
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					}
-				} else if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrows, etc.)
-					switch key[2] {
-					case 'D': // Left Arrow
-						focusedTextArea.MoveCursorLeft()
-						loopNeedsRender = true
-					case 'C': // Right Arrow
-						focusedTextArea.MoveCursorRight()
-						loopNeedsRender = true
-					case 'A': // Up Arrow
-						focusedTextArea.MoveCursorUp()
-						loopNeedsRender = true
-					case 'B': // Down Arrow
-						focusedTextArea.MoveCursorDown()
-						loopNeedsRender = true
-					case 'Z': // Shift+Tab
-						w.setFocus(w.focusedIndex - 1)
-						loopNeedsRender = true
-					}
-				} else if n == 4 && key[0] == '\x1b' && key[1] == '[' && key[3] == '~' { // More escape sequences
-					switch key[2] {
-					case '3': // Delete key (\x1b[3~)
-						focusedTextArea.DeleteForward()
-						loopNeedsRender = true
-					}
-				}
-			} else if focusedTextBox != nil && focusedTextBox.IsActive {
-				// ... (TextBox input handling remains the same) ...
-				isPrintable := n == 1 && key[0] >= 32 && key[0] < 127 // Printable ASCII (excluding DEL)
-
-				if isPrintable {
-					// If it's the first keypress in a pristine box, clear it first.
-					if focusedTextBox.IsPristine {
-						focusedTextBox.Text = ""
-						focusedTextBox.CursorPos = 0
-						focusedTextBox.IsPristine = false
-					}
-					// Insert character at cursor position
-					focusedTextBox.Text = focusedTextBox.Text[:focusedTextBox.CursorPos] + string(key[0]) + focusedTextBox.Text[focusedTextBox.CursorPos:]
-					focusedTextBox.CursorPos++
-					loopNeedsRender = true
-				} else if n == 1 {
-					switch key[0] {
-					case 127, 8: // Backspace (DEL or ASCII BS)
-						if focusedTextBox.CursorPos > 0 {
-							focusedTextBox.Text = focusedTextBox.Text[:focusedTextBox.CursorPos-1] + focusedTextBox.Text[focusedTextBox.CursorPos:]
-							focusedTextBox.CursorPos--
-							focusedTextBox.IsPristine = false // Edited
-							loopNeedsRender = true
-						}
-					case '\t': // Tab - Move focus to next element
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case '\r': // Enter - Treat like Tab for now (move focus)
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					}
-				} else if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrows, etc.)
-					switch key[2] {
-					case 'D': // Left Arrow
-						if focusedTextBox.CursorPos > 0 {
-							focusedTextBox.CursorPos--
-							focusedTextBox.IsPristine = false // Interacted
-							loopNeedsRender = true            // Need re-render to show cursor move
-						}
-					case 'C': // Right Arrow
-						if focusedTextBox.CursorPos < len(focusedTextBox.Text) {
-							focusedTextBox.CursorPos++
-							focusedTextBox.IsPristine = false // Interacted
-							loopNeedsRender = true            // Need re-render to show cursor move
-						}
-					case 'Z': // Shift+Tab
-						w.setFocus(w.focusedIndex - 1)
-						loopNeedsRender = true
-					}
-				} else if n == 4 && key[0] == '\x1b' && key[1] == '[' && key[3] == '~' { // More escape sequences
-					switch key[2] {
-					case '3': // Delete key (\x1b[3~)
-						if focusedTextBox.CursorPos < len(focusedTextBox.Text) {
-							focusedTextBox.Text = focusedTextBox.Text[:focusedTextBox.CursorPos] + focusedTextBox.Text[focusedTextBox.CursorPos+1:]
-							focusedTextBox.IsPristine = false // Edited
-							loopNeedsRender = true
-						}
-					}
-				}
-			} else if focusedContainer != nil && focusedContainer.IsActive { // Handle Container input
-				if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrows, etc.)
-					switch key[2] {
-					case 'A': // Up Arrow - Select previous item
-						focusedContainer.SelectPrevious()
-						loopNeedsRender = true
-					case 'B': // Down Arrow - Select next item
-						focusedContainer.SelectNext()
-						loopNeedsRender = true
-					case 'Z': // Shift+Tab
-						w.setFocus(w.focusedIndex - 1)
-						loopNeedsRender = true
-					}
-				} else if n == 1 {
-					switch key[0] {
-					case '\t': // Tab - Move focus to next element
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case '\r': // Enter - Trigger item selection callback and move focus
-						// Call the OnItemSelected callback if it exists and selection is valid
-						if focusedContainer.OnItemSelected != nil && focusedContainer.SelectedIndex >= 0 {
-							focusedContainer.OnItemSelected(focusedContainer.SelectedIndex)
-							// Callback might have updated UI elements, so render is needed
-							loopNeedsRender = true
-						}
-						// Ensure render happens even if callback didn't exist (focus changed)
-						loopNeedsRender = true
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					case 'q', 'Q': // Quit key
-						loopShouldQuit = true
-					}
-				}
-				// Potentially add PageUp/PageDown handling here later
-			} else if focusedScrollBar != nil && focusedScrollBar.IsActive { // Handle ScrollBar input
-				if n == 3 && key[0] == '\x1b' && key[1] == '[' { // ANSI Escape sequences (Arrows, etc.)
-					// NEW: Only process scroll actions if the scrollbar is visible
-					if focusedScrollBar.Visible {
-						switch key[2] {
-						case 'A': // Up Arrow - Scroll up
-							focusedScrollBar.SetValue(focusedScrollBar.Value - 1)
-							loopNeedsRender = true
-						case 'B': // Down Arrow - Scroll down
-							focusedScrollBar.SetValue(focusedScrollBar.Value + 1)
-							loopNeedsRender = true
-						}
-					}
-					// Handle focus navigation regardless of visibility
-					switch key[2] {
-					case 'Z': // Shift+Tab
-						w.setFocus(w.focusedIndex - 1)
-						loopNeedsRender = true
-					}
-				} else if n == 1 {
-					// Handle focus navigation / quit regardless of visibility
-					switch key[0] {
-					case '\t': // Tab - Move focus to next element
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case '\r': // Enter - Treat like Tab for now (move focus away from scrollbar)
-						w.setFocus(w.focusedIndex + 1)
-						loopNeedsRender = true
-					case 3: // Ctrl+C - Quit
-						loopShouldQuit = true
-					case 'q', 'Q': // Quit key
-						loopShouldQuit = true
-					}
-				}
-				// Potentially add PageUp/PageDown handling here later (checking Visible)
-			} else {
-				// --- Input Handling when TextBox/Container/ScrollBar is NOT active (handles Buttons, CheckBoxes, RadioButtons, etc.) ---
-				if n == 1 {
-					switch key[0] {
-					case '\t': // Tab key
-						if len(w.focusableElements) > 0 {
-							w.setFocus(w.focusedIndex + 1)
-							loopNeedsRender = true
-						}
-					case '\r': // Enter key (Carriage Return in raw mode)
-						// Activate focused button if it's a button
-						if btn, ok := focusedElement.(*Button); ok && btn.IsActive {
-							if btn.Action != nil {
-								// Restore terminal before action if it prints outside the UI area
-								term.Restore(fd, oldState)
-								fmt.Print(ClearScreenAndBuffer()) // Clear UI before action output
-
-								quitAction := btn.Action() // Execute action
-
-								// If action didn't quit, re-setup terminal and UI
-								if !quitAction {
-									_, err = term.MakeRaw(fd) // Re-enter raw mode
-									if err != nil {
-										fmt.Printf("Error re-entering raw mode: %v\n", err)
-										loopShouldQuit = true // Quit if we can't restore raw mode
-									} else {
-										loopNeedsRender = true // Re-render the UI
-									}
-								} else {
-									loopShouldQuit = true // Action signaled quit
-								}
-							}
-						} else if focusedCheckBox != nil && focusedCheckBox.IsActive { // Check if it's an active CheckBox
-							focusedCheckBox.Checked = !focusedCheckBox.Checked // Toggle state
-							loopNeedsRender = true
-						} else if focusedRadioButton != nil && focusedRadioButton.IsActive { // Check if it's an active RadioButton
-							// Find the index of the focused radio button within its group
-							targetIndex := -1
-							for i, rb := range focusedRadioButton.Group.Buttons {
-								if rb == focusedRadioButton {
-									targetIndex = i
-									break
-								}
-							}
-							if targetIndex != -1 {
-								focusedRadioButton.Group.Select(targetIndex) // Select this button in its group
-								loopNeedsRender = true
-							}
-							// Optionally move focus to the next element after selection
-							// w.setFocus(w.focusedIndex + 1)
-							// loopNeedsRender = true
-						} else {
-							// If Enter is pressed and not on an active Button, CheckBox, RadioButton,
-							// move focus like Tab.
-							w.setFocus(w.focusedIndex + 1)
-							loopNeedsRender = true
-						}
-					case 'q', 'Q': // Quit key
-						loopShouldQuit = true
-					case 3: // Ctrl+C
-						loopShouldQuit = true
-					}
-				} else if n == 3 && key[0] == '\x1b' && key[1] == '[' { // Check for escape sequences (Shift+Tab)
-					switch key[2] {
-					case 'Z': // Shift+Tab (Common sequence, might vary)
-						if len(w.focusableElements) > 0 {
-							w.setFocus(w.focusedIndex - 1)
-							loopNeedsRender = true
-						}
-					}
-				}
+	// --- Help Overlay ---
+	// F1 toggles the help overlay, unless bound with BindFunctionKey. While it is
+	// open, it swallows all other keys except Escape (close) and Ctrl+C (quit).
+	if functionKey(ev) == 1 && w.functionKeys[1] == nil {
+		if w.helpOverlay != nil {
+			w.HideHelpOverlay()
+		} else {
+			w.ShowHelpOverlay()
+		}
+		w.Render()
+		return false
+	}
+	if w.helpOverlay != nil {
+		if w.isQuitKey(key) { // Ctrl+C - Quit
+			return true
+		}
+		if ev.Key == KeyEscape { // Close the overlay
+			w.HideHelpOverlay()
+			w.Render()
+		}
+		return false
+	}
+
+	// --- Resize Mode ---
+	// Ctrl+R enters resize mode on a Resizable window; while in it, the arrow keys
+	// change the size and all other keys except Ctrl+C are swallowed.
+	if w.resizing || (w.Resizable && ev.IsCtrl('r')) {
+		if w.isQuitKey(key) { // Ctrl+C - Quit
+			return true
+		}
+		if w.resizing {
+			w.handleResizeKey(ev)
+		} else {
+			w.resizing = true
+		}
+		w.Render()
+		return false
+	}
+
+	// --- Move Mode ---
+	// Ctrl+G enters move mode on a Movable window; while in it, the arrow keys
+	// move the window and all other keys except Ctrl+C are swallowed.
+	if w.moving || (w.Movable && ev.IsCtrl('g')) {
+		if w.isQuitKey(key) { // Ctrl+C - Quit
+			return true
+		}
+		if w.moving {
+			w.handleMoveKey(ev)
+		} else {
+			w.moving = true
+		}
+		w.Render()
+		return false
+	}
+
+	// --- Modal Prompt ---
+	// An active modal prompt takes the focus back if it was moved away (e.g. by
+	// FocusElement) and receives every key, bypassing the custom key handler.
+	modal := w.activeModal()
+	if modal != nil {
+		if index := w.focusableIndex(modal); index != -1 && index != w.focusedIndex {
+			w.setFocus(index)
+		}
+	}
+
+	// --- Function Keys ---
+	// Keys bound with BindFunctionKey act from anywhere, bypassing the custom key handler.
+	if fn := w.functionKeys[functionKey(ev)]; fn != nil && modal == nil {
+		if fn() {
+			return true // The bound action signaled quit
+		}
+		w.Render()
+		return false
+	}
+
+	// --- Custom Key Handler ---
+	customKeyProcessed := false
+	if w.KeyHandler != nil && modal == nil {
+		handled, render, quit := w.KeyHandler.HandleKeyStroke(key, w)
+		if handled {
+			customKeyProcessed = true
+			if render {
+				loopNeedsRender = true
+			}
+			if quit {
+				loopShouldQuit = true
 			}
-		} // end if !customKeyProcessed
+		}
+	}
 
-		// --- Loop Control and Rendering ---
-		if loopShouldQuit {
-			break // Exit the interaction loop
+	if !customKeyProcessed {
+		// The key goes to the focused element (or the active modal prompt)
+		var focusedElement UIElement
+		if w.focusedIndex >= 0 && w.focusedIndex < len(w.focusableElements) {
+			focusedElement = w.focusableElements[w.focusedIndex]
+		}
+		if modal != nil {
+			focusedElement = modal // Route all input to the modal prompt, even if it isn't focusable
 		}
 
-		// Re-render ONLY if necessary
-		if loopNeedsRender {
-			// Optimization: If only cursor moved in textbox, could potentially just move cursor
-			// But full render is safer for now.
-			w.Render() // Re-render the window state
+		// Apply the window's key bindings (see SetKeyMap), then decode the key
+		key = w.standardKey(key, w.takesTextInput())
+		ev = decodeInput(key)
+		ev.readline = w.ReadlineKeys
+
+		// --- Key Handling ---
+		// Priority: Button mnemonics > Window content scrolling > Tab switching > Focused element (see InputHandler) > Window bindings
+		if len(key) == 0 {
+			// Key unbound by the KeyMap: ignore it
+		} else if btn := w.mnemonicButton(ev); btn != nil && modal == nil {
+			// A button's mnemonic activates it from anywhere, like Enter on the focused button
+			w.setFocus(w.focusableIndex(btn))
+			loopNeedsRender = true
+			if btn.Action != nil && w.runButtonAction(btn, fd, oldState) {
+				loopShouldQuit = true // Action signaled quit (or raw mode couldn't be restored)
+			}
+		} else if modal == nil && w.Scrollable && w.handleContentScrollKey(ev, focusedElement) {
+			loopNeedsRender = true
+		} else if panel := w.tabPanelFor(focusedElement); panel != nil && tabSwitchKey(ev) != 0 {
+			// Ctrl+Tab / Shift+Ctrl+Tab switch tabs from anywhere inside a TabPanel
+			if tabSwitchKey(ev) > 0 {
+				panel.NextTab()
+			} else {
+				panel.PreviousTab()
+			}
+			loopNeedsRender = true
+		} else {
+			handled := false
+			if handler, ok := focusedElement.(InputHandler); ok {
+				handled, loopNeedsRender, loopShouldQuit = handler.HandleInput(ev)
+			}
+			if !handled {
+				loopNeedsRender, loopShouldQuit = w.handleWindowKey(ev, focusedElement, fd, oldState)
+			}
+		}
+	} // end if !customKeyProcessed
+
+	// An action confirmed in a WithConfirm dialog runs like the button's own press
+	if action := w.pendingAction; action != nil && !loopShouldQuit {
+		w.pendingAction = nil
+		loopNeedsRender = true
+		if w.runAction(action, fd, oldState) {
+			loopShouldQuit = true
 		}
 	}
 
-	// Cleanup is handled by defers (Restore terminal state, Show cursor)
-	// Clear the screen after finishing interaction
-	fmt.Print(ClearScreenAndBuffer())
-	fmt.Print(ShowCursor()) // Explicitly show cursor after clearing
+	// --- Loop Control and Rendering ---
+	if loopShouldQuit {
+		return true // Quit the window
+	}
+
+	// Re-render ONLY if necessary
+	if loopNeedsRender {
+		// Optimization: If only cursor moved in textbox, could potentially just move cursor
+		// But full render is safer for now.
+		w.Render() // Re-render the window state
+	}
+	return false
+}
+
+// handleWindowKey applies the window's own bindings to a key the focused element
+// didn't handle: Tab and Shift+Tab move focus, Enter presses the focused button (or
+// moves focus like Tab), Escape closes a non-modal prompt, and q and Ctrl+C quit.
+// It reports whether the window needs rendering and whether it should quit.
+func (w *Window) handleWindowKey(ev InputEvent, focused UIElement, fd int, oldState *term.State) (bool, bool) {
+	switch {
+	case ev.Key == KeyTab:
+		if len(w.focusableElements) > 0 {
+			w.setFocus(w.focusedIndex + 1)
+			return true, false
+		}
+	case ev.Key == KeyShiftTab:
+		if len(w.focusableElements) > 0 {
+			w.setFocus(w.focusedIndex - 1)
+			return true, false
+		}
+	case ev.Key == KeyEnter:
+		if btn, ok := focused.(*Button); ok && btn.IsActive {
+			if btn.Action == nil {
+				return false, false
+			}
+			if w.runButtonAction(btn, fd, oldState) {
+				return false, true // Action signaled quit (or raw mode couldn't be restored)
+			}
+			return true, false
+		}
+		w.setFocus(w.focusedIndex + 1)
+		return true, false
+	case ev.Key == KeyEscape:
+		if p, ok := focused.(*Prompt); ok && !p.IsModal() {
+			w.setFocus(w.focusedIndex + 1) // Moving focus away closes the prompt
+			return true, false
+		}
+	case ev.Key == KeyRune && ev.Mod == 0 && (ev.Rune == 'q' || ev.Rune == 'Q'), ev.IsCtrl('c'):
+		return false, true
+	}
+	return false, false
 }
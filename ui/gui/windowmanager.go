@@ -0,0 +1,306 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// defaultSwitchKey is the key that activates the next window of a WindowManager.
+const defaultSwitchKey = "\x1b\t" // Alt+Tab
+
+// WindowManager shows several windows on one terminal, e.g. a dialog window over an
+// application window. Run reads the input once and passes it to the active window
+// only; the other windows are drawn behind it as a backdrop, back to front by
+// ZIndex. Alt+Tab (SwitchKey) and clicking on a window change the active window.
+type WindowManager struct {
+	Windows     []*Window     // Managed windows, in the order they were added
+	SwitchKey   string        // Key sequence that activates the next window ("" disables it)
+	active      *Window       // Window receiving the input
+	frontScreen *screen       // Composed frame last written to the terminal
+	batch       bool          // True while Render draws all windows, to compose them once
+	spinners    chan *Spinner // Ticks of spinners started with StartAuto in the managed windows
+	Output      io.Writer     // Where the composed frames go (nil = the active window's Output, or stdout)
+	mu          sync.Mutex
+}
+
+// NewWindowManager creates a manager for windows; the last one starts active.
+func NewWindowManager(windows ...*Window) *WindowManager {
+	wm := &WindowManager{SwitchKey: defaultSwitchKey}
+	for _, w := range windows {
+		wm.AddWindow(w)
+	}
+	return wm
+}
+
+// AddWindow adds w to the manager and makes it the active window.
+func (wm *WindowManager) AddWindow(w *Window) {
+	if w.manager == wm {
+		return
+	}
+	w.manager = wm
+	w.managedFrame = ""
+	wm.Windows = append(wm.Windows, w)
+	wm.active = w
+	wm.Invalidate()
+}
+
+// RemoveWindow removes w from the manager. If it was active, the topmost remaining
+// window becomes active.
+func (wm *WindowManager) RemoveWindow(w *Window) {
+	for i, managed := range wm.Windows {
+		if managed == w {
+			wm.Windows = append(wm.Windows[:i], wm.Windows[i+1:]...)
+			break
+		}
+	}
+	w.manager = nil
+	w.managedFrame = ""
+	if wm.active == w {
+		wm.active = nil
+		if order := wm.stackingOrder(); len(order) > 0 {
+			wm.active = order[len(order)-1]
+		}
+	}
+	wm.Invalidate()
+}
+
+// ActiveWindow returns the window receiving the input, or nil if there is none.
+func (wm *WindowManager) ActiveWindow() *Window {
+	return wm.active
+}
+
+// Activate makes w, which must be managed by wm, the active window.
+func (wm *WindowManager) Activate(w *Window) {
+	if w.manager == wm {
+		wm.active = w
+	}
+}
+
+// NextWindow activates the window added after the active one, wrapping around.
+func (wm *WindowManager) NextWindow() {
+	for i, w := range wm.Windows {
+		if w == wm.active {
+			wm.active = wm.Windows[(i+1)%len(wm.Windows)]
+			return
+		}
+	}
+}
+
+// stackingOrder returns the windows back to front: by ZIndex, with the active
+// window above the others of the same ZIndex.
+func (wm *WindowManager) stackingOrder() []*Window {
+	order := make([]*Window, len(wm.Windows))
+	copy(order, wm.Windows)
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].ZIndex != order[j].ZIndex {
+			return order[i].ZIndex < order[j].ZIndex
+		}
+		return order[j] == wm.active && order[i] != wm.active
+	})
+	return order
+}
+
+// windowAt returns the topmost window covering the screen position (x, y), or nil.
+func (wm *WindowManager) windowAt(x, y int) *Window {
+	order := wm.stackingOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		w := order[i]
+		if x >= w.X && x < w.X+w.Width && y >= w.Y && y < w.Y+w.Height {
+			return w
+		}
+	}
+	return nil
+}
+
+// Render draws all windows back to front.
+func (wm *WindowManager) Render() {
+	wm.mu.Lock()
+	wm.batch = true
+	wm.mu.Unlock()
+
+	for _, w := range wm.stackingOrder() {
+		w.Render()
+	}
+
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.batch = false
+	wm.compose()
+}
+
+// Invalidate makes the next Render redraw every window completely.
+func (wm *WindowManager) Invalidate() {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.frontScreen = nil
+}
+
+// windowRendered stores the commands of a window's Render and, unless all windows
+// are being rendered, writes the changed cells of the composed frame.
+func (wm *WindowManager) windowRendered(w *Window, frame string) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	w.managedFrame = frame
+	if !wm.batch {
+		wm.compose()
+	}
+}
+
+// compose draws the last frames of all windows back to front into one frame and
+// writes what changed since the previous one. Callers hold wm.mu.
+func (wm *WindowManager) compose() {
+	var commands strings.Builder
+	for _, w := range wm.stackingOrder() {
+		commands.WriteString(w.managedFrame)
+	}
+	frame := parseScreen(commands.String(), GetTerminalWidth(), GetTerminalHeight())
+	fmt.Fprint(wm.output(), frame.diff(wm.frontScreen))
+	wm.frontScreen = frame
+}
+
+// output returns where the manager writes to the terminal: Output, the active
+// window's Output, or stdout.
+func (wm *WindowManager) output() io.Writer {
+	if wm.Output != nil {
+		return wm.Output
+	}
+	if wm.active != nil {
+		return wm.active.output()
+	}
+	return os.Stdout
+}
+
+// Run handles user interaction with the managed windows using raw terminal input,
+// until every window has quit. A window quitting (e.g. with 'q', Ctrl+C or a button
+// action returning true) is removed from the manager. Tickers set with
+// Window.SetTicker only run in Window.WindowActions.
+func (wm *WindowManager) Run() {
+	out := wm.output() // Kept after the last window quits
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintln(out, "Error: Standard input is not a terminal.")
+		return
+	}
+
+	oldState, err := term.GetState(fd)
+	if err != nil {
+		fmt.Fprintf(out, "Error getting terminal state: %v\n", err)
+		return
+	}
+	defer term.Restore(fd, oldState)
+	defer fmt.Fprint(out, ShowCursor())
+	defer fmt.Fprint(out, CursorShapeCmd(CursorDefault)) // The windows may have changed the cursor shape
+
+	if _, err = term.MakeRaw(fd); err != nil {
+		fmt.Fprintf(out, "Error setting terminal to raw mode: %v\n", err)
+		return
+	}
+
+	// Report mouse events if any window wants them
+	for _, w := range wm.Windows {
+		if w.MouseEnabled {
+			fmt.Fprint(out, mouseEnable)
+			defer fmt.Fprint(out, mouseDisable)
+			break
+		}
+	}
+
+	for _, w := range wm.Windows {
+		w.applyInitialFocus()
+	}
+	wm.Invalidate()
+	wm.Render()
+
+	resized := make(chan os.Signal, 1)
+	notifyTerminalResize(resized)
+	defer signal.Stop(resized)
+
+	fmt.Fprint(out, bracketedPasteEnable)
+	defer fmt.Fprint(out, bracketedPasteDisable)
+
+	inputBuf := make([]byte, inputBufferSize)
+	var input <-chan []byte // Pending read, nil while the last input is being handled
+	var splitter keySplitter
+	var keyTimeout <-chan time.Time // Fires when a key cut off by a read won't be completed
+	for len(wm.Windows) > 0 {
+		if input == nil {
+			input = readInput(inputBuf)
+		}
+		var data []byte
+		var keys [][]byte
+		readOK := true
+		select {
+		case <-resized:
+			for _, w := range wm.Windows {
+				w.fitTerminal()
+			}
+			fmt.Fprint(out, ClearScreenAndBuffer()) // The terminal may have rewrapped what was on screen
+			wm.Invalidate()
+			wm.Render()
+			continue
+		case s := <-wm.spinners:
+			s.Tick()
+			wm.Render()
+			continue
+		case <-keyTimeout: // The rest of the key held back didn't arrive: a lone Escape
+			keys = splitter.flush()
+		case data, readOK = <-input:
+			input = nil
+			keys = splitter.split(data)
+		}
+		if !readOK {
+			break // Exit loop on read error
+		}
+		keyTimeout = splitter.timeout()
+		for _, key := range keys {
+			wm.handleInput(key, fd, oldState)
+		}
+	}
+
+	for _, w := range wm.Windows {
+		w.restoreCursorStyle()
+		w.RestoreTerminalTitle()
+	}
+	fmt.Fprint(out, ClearScreenAndBuffer())
+	fmt.Fprint(out, ShowCursor())
+}
+
+// handleInput passes one chunk of input to the active window, after handling the
+// keys and clicks that change the active window.
+func (wm *WindowManager) handleInput(key []byte, fd int, oldState *term.State) {
+	active := wm.active
+	if active == nil {
+		return
+	}
+
+	if wm.SwitchKey != "" && string(key) == wm.SwitchKey {
+		wm.NextWindow()
+		wm.Render()
+		return
+	}
+	// A click on a window behind the active one activates it
+	if ev, ok := parseMouseEvent(key); ok && ev.Button == 0 && ev.Pressed && !ev.Motion && !active.dragging {
+		if target := wm.windowAt(ev.X, ev.Y); target != nil && target != active {
+			wm.Activate(target)
+			wm.Render()
+			return
+		}
+	}
+
+	if active.handleInput(key, fd, oldState) {
+		active.restoreCursorStyle()
+		active.RestoreTerminalTitle()
+		wm.RemoveWindow(active)
+		wm.Render()
+	} else if wm.active != active {
+		wm.Render() // The input added a window (e.g. a dialog) or changed the active one
+	}
+}
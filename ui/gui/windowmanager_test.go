@@ -0,0 +1,41 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWindowManagerWritesToOutput(t *testing.T) {
+	var out bytes.Buffer
+	back := NewWindow("", "Back", 0, 0, 20, 6, "single", "", "", "", "")
+	front := NewWindow("", "Front", 4, 2, 20, 6, "single", "", "", "", "")
+	wm := NewWindowManager(back, front)
+	wm.Output = &out
+
+	wm.Render()
+	if text := StripANSI(out.String()); !strings.Contains(text, "Back") || !strings.Contains(text, "Front") {
+		t.Errorf("composed frame %q doesn't show both windows", text)
+	}
+
+	out.Reset()
+	front.Bell()
+	if out.String() != bell {
+		t.Errorf("Bell wrote %q to the manager's Output, want the bell", out.String())
+	}
+	out.Reset()
+	front.EmitCursorShape(CursorSteadyBlock)
+	if out.String() != CursorShapeCmd(CursorSteadyBlock) {
+		t.Errorf("EmitCursorShape wrote %q, want the cursor shape command", out.String())
+	}
+
+	// Without its own Output, the manager writes where the active window does
+	var windowOut bytes.Buffer
+	wm.Output = nil
+	front.Output = &windowOut
+	wm.Invalidate()
+	wm.Render()
+	if !strings.Contains(StripANSI(windowOut.String()), "Front") {
+		t.Errorf("composed frame didn't go to the active window's Output")
+	}
+}